@@ -39,9 +39,13 @@ async function request<T>(path: string, init?: RequestInit): Promise<T> {
   if (!response.ok) {
     const message = await response
       .json()
-      .catch(() => ({ error: response.statusText }));
+      .catch(() => ({ detail: response.statusText }));
 
-    throw new Error(message.error ?? "Request failed");
+    const fieldMessages = Array.isArray(message.errors)
+      ? message.errors.map((item: { message: string }) => item.message).join("; ")
+      : "";
+
+    throw new Error(fieldMessages || message.detail || message.title || "Request failed");
   }
 
   return response.json() as Promise<T>;
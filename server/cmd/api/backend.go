@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+	"github.com/karanm6505/dbms/server/internal/db"
+	"github.com/karanm6505/dbms/server/internal/handlers"
+	"github.com/karanm6505/dbms/server/internal/mail"
+	"github.com/karanm6505/dbms/server/internal/metrics"
+	"github.com/karanm6505/dbms/server/internal/migrate"
+	"github.com/karanm6505/dbms/server/internal/repository"
+	"github.com/karanm6505/dbms/server/internal/repository/memory"
+)
+
+// openBackend builds the handler on the configured database driver and
+// registers its metrics. The returned function releases its connections.
+func openBackend(ctx context.Context, cfg config.Config) (*handlers.Handler, func(), error) {
+	if cfg.Database.Driver == config.DriverMemory {
+		return openMemory(cfg), func() {}, nil
+	}
+	return openSQL(ctx, cfg)
+}
+
+// openSQL serves the MySQL, PostgreSQL and SQLite drivers, which share the
+// repositories and differ only in dialect.
+func openSQL(ctx context.Context, cfg config.Config) (*handlers.Handler, func(), error) {
+	cluster, err := db.ConnectCluster(cfg.Database)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	database := cluster.Primary()
+	dialect := db.Dialect(cfg.Database)
+	migrator, err := migrate.NewForDialect(database, dialect)
+	if err != nil {
+		cluster.Close()
+		return nil, nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	monitorCtx, stopMonitor := context.WithCancel(ctx)
+	go cluster.Monitor(monitorCtx, cfg.Database.Replica.HealthInterval)
+
+	reads := repository.WithReadRouter(cluster)
+	sqlDialect := repository.WithDialect(dialect)
+	statsRepo := repository.NewStatsRepository(database, reads, sqlDialect)
+
+	handler := handlers.New(
+		database,
+		migrator,
+		repository.NewStudentRepository(database, reads, sqlDialect),
+		repository.NewBookRepository(database, reads, sqlDialect),
+		repository.NewStaffRepository(database, reads, sqlDialect),
+		repository.NewBorrowRepository(database, reads, sqlDialect),
+		statsRepo,
+		repository.NewMetadataRepository(database, cfg.Database.Name, sqlDialect),
+		repository.NewUserRepository(database, sqlDialect),
+		repository.NewConsoleRepository(database, sqlDialect),
+		repository.NewSavedQueryRepository(database, sqlDialect),
+		cfg.Auth,
+		cfg.Console,
+		verificationSender(cfg.Mail),
+	)
+
+	metrics.RegisterDatabase(database, statsRepo)
+	if replica, _ := cluster.Replica(); replica != nil {
+		metrics.RegisterReplica(replica)
+	}
+
+	return handler, func() {
+		stopMonitor()
+		cluster.Close()
+	}, nil
+}
+
+func openMemory(cfg config.Config) *handlers.Handler {
+	slog.Warn("using the in-memory database: changes are lost on restart")
+
+	store := memory.NewSeeded()
+	metrics.RegisterCirculation(store.Stats())
+
+	return handlers.New(
+		store.Health(),
+		store.Health(),
+		store.Students(),
+		store.Books(),
+		store.Staff(),
+		store.Borrows(),
+		store.Stats(),
+		store.Metadata(),
+		store.Users(),
+		store.Console(),
+		store.SavedQueries(),
+		cfg.Auth,
+		cfg.Console,
+		verificationSender(cfg.Mail),
+	)
+}
+
+// verificationSender picks how email verification codes are delivered. The
+// log sender is only accepted outside production.
+func verificationSender(cfg config.MailConfig) handlers.VerificationSender {
+	if cfg.Sender == config.MailSenderSMTP {
+		return mail.NewSMTP(cfg)
+	}
+
+	slog.Warn("verification codes are written to the debug log; set MAIL_SENDER=smtp to email them")
+	return handlers.LogVerificationSender{}
+}
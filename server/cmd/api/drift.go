@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+	"github.com/karanm6505/dbms/server/internal/db"
+	"github.com/karanm6505/dbms/server/internal/drift"
+	"github.com/karanm6505/dbms/server/internal/repository"
+)
+
+// runDrift follows diff(1) exit codes: 0 in sync, 1 drift found, 2 failure.
+func runDrift(cfg config.Config, args []string) int {
+	asJSON := len(args) > 0 && args[0] == "--json"
+
+	dbConfig := cfg.Database
+	dbConfig.AutoMigrate = false
+
+	database, err := db.Connect(dbConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 2
+	}
+	defer database.Close()
+
+	metadataRepo := repository.NewMetadataRepository(database, cfg.Database.Name)
+
+	report, err := drift.Check(context.Background(), metadataRepo, cfg.Database.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drift check failed: %v\n", err)
+		return 2
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(report)
+	} else if report.InSync {
+		fmt.Printf("schema %s matches the embedded migrations\n", report.Database)
+	} else {
+		for _, diff := range report.Differences {
+			line := fmt.Sprintf("%-10s %-9s %s", diff.Kind, diff.ObjectType, diff.Name)
+			if diff.Field != "" {
+				line += fmt.Sprintf(" (%s)\n    expected: %s\n    actual:   %s", diff.Field, diff.Expected, diff.Actual)
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if !report.InSync {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+func TestHealthcheckURLFollowsTLS(t *testing.T) {
+	cfg := config.Default()
+	if got := healthcheckURL(cfg); got != "http://localhost:5050/api/health/ready" {
+		t.Fatalf("unexpected URL %s", got)
+	}
+
+	cfg.TLS.CertFile = "server.crt"
+	if got := healthcheckURL(cfg); got != "https://localhost:5050/api/health/ready" {
+		t.Fatalf("expected HTTPS once TLS is enabled, got %s", got)
+	}
+}
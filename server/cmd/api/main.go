@@ -2,12 +2,13 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
@@ -15,125 +16,259 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 
+	"github.com/karanm6505/dbms/server/internal/certs"
 	"github.com/karanm6505/dbms/server/internal/config"
-	"github.com/karanm6505/dbms/server/internal/db"
-	"github.com/karanm6505/dbms/server/internal/handlers"
-	"github.com/karanm6505/dbms/server/internal/repository"
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/metrics"
+	"github.com/karanm6505/dbms/server/internal/tracing"
 )
 
 func main() {
 	_ = godotenv.Load()
 
-	cfg := config.Load()
+	configPath := flag.String("config", "", "path to a YAML or TOML config file (defaults to $CONFIG_FILE)")
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	flag.Parse()
+	args := flag.Args()
 
-	database, err := db.Connect(cfg.Database)
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("failed to connect to database: %v", err)
+		log.Fatalf("failed to load configuration: %v", err)
 	}
-	defer database.Close()
-
-	studentRepo := repository.NewStudentRepository(database)
-	bookRepo := repository.NewBookRepository(database)
-	staffRepo := repository.NewStaffRepository(database)
-	borrowRepo := repository.NewBorrowRepository(database)
-	statsRepo := repository.NewStatsRepository(database)
-	metadataRepo := repository.NewMetadataRepository(database, cfg.Database.Name)
-	userRepo := repository.NewUserRepository(database)
-
-	handler := handlers.New(
-		database,
-		studentRepo,
-		bookRepo,
-		staffRepo,
-		borrowRepo,
-		statsRepo,
-		metadataRepo,
-		userRepo,
-		cfg.Auth,
-	)
-
-	allowedOrigins := []string{
-		"http://localhost:5173",
-		"http://127.0.0.1:5173",
-		"http://localhost:8080",
-		"http://127.0.0.1:8080",
+
+	// config print must work on an invalid configuration, that is when it
+	// is most useful.
+	if len(args) > 0 && args[0] == "config" {
+		os.Exit(runConfig(cfg, args[1:]))
 	}
 
-	if rawOrigins := os.Getenv("FRONTEND_ORIGINS"); rawOrigins != "" {
-		for _, origin := range strings.Split(rawOrigins, ",") {
-			if trimmed := strings.TrimSpace(origin); trimmed != "" {
-				allowedOrigins = append(allowedOrigins, trimmed)
-			}
-		}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+
+	logger, err := logging.New(cfg.Log, os.Stderr)
+	if err != nil {
+		log.Fatalf("invalid logging configuration: %v", err)
+	}
+
+	if len(args) > 0 {
+		os.Exit(runCommand(cfg, args))
+	}
+
+	handler, closeBackend, err := openBackend(context.Background(), cfg)
+	if err != nil {
+		logger.Error("failed to open database", "driver", cfg.Database.Driver, "error", err)
+		os.Exit(1)
 	}
+	defer closeBackend()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Error("failed to configure tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
 
 	router := chi.NewRouter()
+	router.Use(logging.Middleware(logger))
+	router.Use(tracing.Middleware)
+	router.Use(metrics.Middleware)
 	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   allowedOrigins,
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", logging.RequestIDHeader, "traceparent", "tracestate"},
+		ExposedHeaders:   []string{logging.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr == "" {
+		router.Handle("/metrics", metrics.Handler(cfg.Metrics.Token))
+	}
+
 	router.Get("/api/health", handler.HealthCheck)
+	router.Get("/api/health/live", handler.HealthCheck)
+	router.Get("/api/health/ready", handler.ReadinessCheck)
 	router.Post("/api/auth/login", handler.Login)
 	router.Post("/api/auth/register", handler.Register)
 
 	router.Group(func(r chi.Router) {
 		r.Use(handler.AuthMiddleware)
 
-	r.Get("/api/auth/me", handler.Me)
-	r.Get("/api/students", handler.GetStudents)
+		r.Get("/api/auth/me", handler.Me)
+		r.Get("/api/students", handler.GetStudents)
 		r.Get("/api/students/{id}", handler.GetStudentByID)
+		r.Get("/api/students/{id}/profile", handler.GetStudentProfile)
 		r.Post("/api/students", handler.CreateStudent)
+		r.Put("/api/students/{id}", handler.UpdateStudent)
+		r.Patch("/api/students/{id}", handler.PatchStudent)
 		r.Get("/api/books", handler.GetBooks)
 		r.Get("/api/books/available", handler.GetAvailableBooks)
-		r.Get("/api/staff", handler.GetStaff)
 		r.Get("/api/borrows", handler.GetBorrowRecords)
-		r.Get("/api/dashboard/stats", handler.GetDashboardStats)
-		r.Get("/api/schema/tables", handler.GetTables)
-		r.Get("/api/schema/functions", handler.GetFunctions)
-		r.Get("/api/schema/procedures", handler.GetProcedures)
-		r.Get("/api/schema/triggers", handler.GetTriggers)
-		r.Post("/api/schema/functions/{name}/execute", handler.ExecuteFunction)
-		r.Post("/api/schema/procedures/{name}/execute", handler.ExecuteProcedure)
+		r.Post("/api/borrows", handler.CheckoutBook)
+		r.Post("/api/borrows/{id}/return", handler.ReturnBook)
+		r.Post("/api/import/books", handler.ImportBooks)
+		r.Post("/api/import/students", handler.ImportStudents)
+		r.Get("/api/import/jobs/{id}", handler.GetImportJob)
+		r.Put("/api/users/{id}/student", handler.LinkUserStudent)
+		r.Delete("/api/users/{id}/student", handler.UnlinkUserStudent)
+		r.Post("/api/me/link", handler.RequestStudentLink)
+		r.Post("/api/me/link/verify", handler.VerifyStudentLink)
+		r.Get("/api/me/loans", handler.GetMyLoans)
+		r.Get("/api/me/holds", handler.GetMyHolds)
+		r.Get("/api/me/fines", handler.GetMyFines)
+		r.Post("/api/me/renew", handler.RenewMyLoan)
+
+		// Everything below reads across all students, so patron accounts
+		// are kept out.
+		r.Group(func(r chi.Router) {
+			r.Use(handler.RequireStaff)
+
+			r.Get("/api/staff", handler.GetStaff)
+			r.Get("/api/dashboard/stats", handler.GetDashboardStats)
+			r.Get("/api/schema/tables", handler.GetTables)
+			r.Get("/api/schema/tables/{name}", handler.GetTableDefinition)
+			r.Get("/api/schema/diagram", handler.GetSchemaDiagram)
+			r.Get("/api/schema/capabilities", handler.GetSchemaCapabilities)
+			r.Get("/api/schema/functions", handler.GetFunctions)
+			r.Get("/api/schema/functions/{name}", handler.GetFunctionDefinition)
+			r.Get("/api/schema/procedures", handler.GetProcedures)
+			r.Get("/api/schema/procedures/{name}", handler.GetProcedureDefinition)
+			r.Get("/api/schema/triggers", handler.GetTriggers)
+			r.Get("/api/schema/triggers/{name}", handler.GetTriggerDefinition)
+			r.Post("/api/schema/functions/{name}/execute", handler.ExecuteFunction)
+			r.Post("/api/schema/procedures/{name}/execute", handler.ExecuteProcedure)
+			r.Get("/api/reports", handler.GetReports)
+			r.Post("/api/reports", handler.CreateReport)
+			r.Get("/api/reports/{slug}", handler.RunReport)
+			r.Put("/api/reports/{slug}", handler.UpdateReport)
+			r.Delete("/api/reports/{slug}", handler.DeleteReport)
+		})
+
+		r.Group(func(r chi.Router) {
+			if cfg.TLS.ClientCAFile != "" {
+				r.Use(certs.RequireClientCert)
+			}
+
+			r.Get("/api/admin/schema/drift", handler.GetSchemaDrift)
+			r.Post("/api/admin/query", handler.RunConsoleQuery)
+			r.Get("/api/admin/explain/queries", handler.GetExplainableQueries)
+			r.Post("/api/admin/explain", handler.ExplainQuery)
+		})
 	})
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.API.Port),
 		Handler:           router,
-		ReadHeaderTimeout: 15 * time.Second,
-		ReadTimeout:       15 * time.Second,
-		WriteTimeout:      15 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		ReadHeaderTimeout: cfg.API.ReadHeaderTimeout,
+		ReadTimeout:       cfg.API.ReadTimeout,
+		WriteTimeout:      cfg.API.WriteTimeout,
+		IdleTimeout:       cfg.API.IdleTimeout,
+	}
+
+	servers := []*http.Server{server}
+
+	if cfg.TLS.Enabled() {
+		reloader, err := certs.NewReloader(cfg.TLS)
+		if err != nil {
+			logger.Error("failed to load TLS certificates", "error", err)
+			os.Exit(1)
+		}
+		server.TLSConfig = reloader.TLSConfig()
+
+		watchCtx, stopWatching := context.WithCancel(context.Background())
+		defer stopWatching()
+		if cfg.TLS.ReloadInterval > 0 {
+			go reloader.Watch(watchCtx, cfg.TLS.ReloadInterval)
+		}
+
+		hangup := make(chan os.Signal, 1)
+		signal.Notify(hangup, syscall.SIGHUP)
+		go func() {
+			for range hangup {
+				reloader.ReloadOnSignal()
+			}
+		}()
+
+		if cfg.TLS.RedirectAddr != "" {
+			redirectServer := &http.Server{
+				Addr:              cfg.TLS.RedirectAddr,
+				Handler:           certs.RedirectHandler(cfg.API.Port),
+				ReadHeaderTimeout: cfg.API.ReadHeaderTimeout,
+			}
+			servers = append(servers, redirectServer)
+
+			go func() {
+				logger.Info("redirecting HTTP to HTTPS", "addr", redirectServer.Addr)
+				if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					logger.Error("redirect server error", "error", err)
+					os.Exit(1)
+				}
+			}()
+		}
 	}
 
 	go func() {
-		log.Printf("Library API listening on http://localhost:%d", cfg.API.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("server error: %v", err)
+		logger.Info("library API listening", "addr", server.Addr, "tls", cfg.TLS.Enabled())
+
+		var err error
+		if cfg.TLS.Enabled() {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error("server error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
-	waitForShutdown(server)
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler(cfg.Metrics.Token))
+
+		metricsServer := &http.Server{
+			Addr:              cfg.Metrics.Addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		servers = append(servers, metricsServer)
+
+		go func() {
+			logger.Info("metrics listening", "addr", cfg.Metrics.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("metrics server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	waitForShutdown(cfg.API.ShutdownTimeout, servers...)
 }
 
-func waitForShutdown(server *http.Server) {
+func waitForShutdown(timeout time.Duration, servers ...*http.Server) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit
-	log.Println("shutdown signal received")
+	slog.Info("shutdown signal received")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
-		log.Printf("graceful shutdown failed: %v", err)
-		if err := server.Close(); err != nil {
-			log.Printf("force close error: %v", err)
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Error("graceful shutdown failed", "addr", server.Addr, "error", err)
+			if err := server.Close(); err != nil {
+				slog.Error("force close failed", "addr", server.Addr, "error", err)
+			}
 		}
 	}
-	log.Println("server stopped")
+	slog.Info("server stopped")
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+	"github.com/karanm6505/dbms/server/internal/db"
+	"github.com/karanm6505/dbms/server/internal/migrate"
+)
+
+const usage = `usage:
+  library-api [--config file] [command]
+
+  library-api                      start the API server
+  library-api config print         print the effective configuration with secrets redacted
+  library-api migrate up           apply all pending migrations
+  library-api migrate down [n]     roll back the last n migrations (default 1)
+  library-api migrate status       list migrations and their state
+  library-api drift [--json]       compare the live schema with the embedded migrations
+  library-api healthcheck          exit 0 when the local server reports ready`
+
+func runCommand(cfg config.Config, args []string) int {
+	if args[0] == "migrate" && cfg.Database.Driver == config.DriverMemory {
+		fmt.Fprintf(os.Stderr, "migrate needs the mysql, postgres or sqlite database driver, got %q\n", cfg.Database.Driver)
+		return 2
+	}
+	if args[0] == "drift" && cfg.Database.Driver != config.DriverMySQL {
+		fmt.Fprintf(os.Stderr, "drift needs the mysql database driver, got %q\n", cfg.Database.Driver)
+		return 2
+	}
+
+	switch args[0] {
+	case "migrate":
+		return runMigrate(cfg, args[1:])
+	case "drift":
+		return runDrift(cfg, args[1:])
+	case "healthcheck":
+		return runHealthcheck(cfg)
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", args[0], usage)
+		return 2
+	}
+}
+
+func runMigrate(cfg config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	dbConfig := cfg.Database
+	dbConfig.AutoMigrate = false
+
+	database, err := db.Connect(dbConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer database.Close()
+
+	migrator, err := migrate.NewForDialect(database, db.Dialect(dbConfig))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load migrations: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate up failed: %v\n", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				fmt.Fprintf(os.Stderr, "invalid step count %q\n", args[1])
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate down failed: %v\n", err)
+			return 1
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate status failed: %v\n", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		healthy := true
+		for _, status := range statuses {
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, status.AppliedAt)
+			if status.State == migrate.StateDirty || status.State == migrate.StateChecksumMismatch {
+				healthy = false
+			}
+		}
+		tw.Flush()
+
+		if !healthy {
+			return 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n%s\n", args[0], usage)
+		return 2
+	}
+
+	return 0
+}
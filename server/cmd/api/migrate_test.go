@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+// captureStdout returns what fn prints to standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestMigrateStatusOnFreshSQLite(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Driver = config.DriverSQLite
+	cfg.Database.Path = filepath.Join(t.TempDir(), "library.db")
+	cfg.Database.AutoMigrate = true
+
+	var code int
+	out := captureStdout(t, func() { code = runMigrate(cfg, []string{"status"}) })
+	if code != 0 {
+		t.Fatalf("migrate status exited with %d: %s", code, out)
+	}
+	if strings.Contains(out, "applied") || !strings.Contains(out, "pending") {
+		t.Fatalf("expected every migration to be pending, got:\n%s", out)
+	}
+}
@@ -0,0 +1,154 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+var minVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Reloader serves the certificate, key and client CA bundle named in the
+// configuration and swaps them in place when Reload succeeds, so rotating a
+// certificate does not drop existing connections or require a restart.
+type Reloader struct {
+	cfg     config.TLSConfig
+	current atomic.Pointer[tls.Config]
+
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+}
+
+func NewReloader(cfg config.TLSConfig) (*Reloader, error) {
+	if _, ok := minVersions[cfg.MinVersion]; !ok {
+		return nil, fmt.Errorf("unsupported minimum TLS version %q (expected 1.2 or 1.3)", cfg.MinVersion)
+	}
+
+	reloader := &Reloader{cfg: cfg}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// TLSConfig is the configuration to install on http.Server. Every handshake
+// is answered with the most recently loaded material.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: minVersions[r.cfg.MinVersion],
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current.Load().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Reload reads the files again. On failure the previous material stays in
+// use.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	certificate, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+
+	next := &tls.Config{
+		MinVersion:   minVersions[r.cfg.MinVersion],
+		NextProtos:   []string{"h2", "http/1.1"},
+		Certificates: []tls.Certificate{certificate},
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("client CA bundle contains no PEM certificates")
+		}
+
+		// Certificates are optional at the handshake; RequireClientCert
+		// enforces them on the routes that need them.
+		next.ClientCAs = pool
+		next.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	r.current.Store(next)
+	r.modTimes = r.statFiles()
+	return nil
+}
+
+// Watch polls the files every interval and reloads when any of them changes.
+// It returns when ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.changed() {
+				r.reloadAndLog("file change")
+			}
+		}
+	}
+}
+
+func (r *Reloader) reloadAndLog(reason string) {
+	if err := r.Reload(); err != nil {
+		slog.Error("failed to reload TLS certificates, keeping the previous ones", "reason", reason, "error", err)
+		return
+	}
+	slog.Info("reloaded TLS certificates", "reason", reason)
+}
+
+// ReloadOnSignal is called by the process when it receives SIGHUP.
+func (r *Reloader) ReloadOnSignal() {
+	r.reloadAndLog("SIGHUP")
+}
+
+func (r *Reloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.statFiles()
+	for path, modTime := range current {
+		if !modTime.Equal(r.modTimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reloader) statFiles() map[string]time.Time {
+	times := make(map[string]time.Time, 3)
+	for _, path := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			times[path] = info.ModTime()
+		}
+	}
+	return times
+}
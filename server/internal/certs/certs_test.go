@@ -0,0 +1,167 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+// writeCertificate writes a self-signed certificate and key for localhost
+// with the given serial number.
+func writeCertificate(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func servedSerial(t *testing.T, reloader *Reloader) int64 {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = reloader.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	conn, err := tls.Dial("tcp", server.Listener.Addr().String(), &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2", "http/1.1"}})
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if state.Version < tls.VersionTLS12 {
+		t.Fatalf("negotiated TLS version %x below the minimum", state.Version)
+	}
+	if state.NegotiatedProtocol != "h2" {
+		t.Fatalf("expected HTTP/2 to be negotiated, got %q", state.NegotiatedProtocol)
+	}
+	return state.PeerCertificates[0].SerialNumber.Int64()
+}
+
+func TestReloaderServesRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.TLSConfig{
+		CertFile:   filepath.Join(dir, "tls.crt"),
+		KeyFile:    filepath.Join(dir, "tls.key"),
+		MinVersion: "1.2",
+	}
+	writeCertificate(t, cfg.CertFile, cfg.KeyFile, 1)
+
+	reloader, err := NewReloader(cfg)
+	if err != nil {
+		t.Fatalf("NewReloader returned error: %v", err)
+	}
+	if got := servedSerial(t, reloader); got != 1 {
+		t.Fatalf("expected serial 1, got %d", got)
+	}
+
+	writeCertificate(t, cfg.CertFile, cfg.KeyFile, 2)
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload returned error: %v", err)
+	}
+	if got := servedSerial(t, reloader); got != 2 {
+		t.Fatalf("expected rotated serial 2, got %d", got)
+	}
+
+	if err := os.WriteFile(cfg.KeyFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Fatal("expected reload of a broken key to fail")
+	}
+	if got := servedSerial(t, reloader); got != 2 {
+		t.Fatalf("expected previous certificate after failed reload, got %d", got)
+	}
+}
+
+func TestNewReloaderRejectsUnknownMinVersion(t *testing.T) {
+	if _, err := NewReloader(config.TLSConfig{MinVersion: "1.0"}); err == nil {
+		t.Fatal("expected TLS 1.0 to be rejected")
+	}
+}
+
+func TestRequireClientCert(t *testing.T) {
+	handler := RequireClientCert(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/admin/query", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without a certificate, got %d", w.Code)
+	}
+
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected verified client to pass, got %d", w.Code)
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		port   int
+		host   string
+		target string
+	}{
+		{8443, "library.example.com:8080", "https://library.example.com:8443/api/books?page=2"},
+		{443, "library.example.com", "https://library.example.com/api/books?page=2"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://"+tt.host+"/api/books?page=2", nil)
+		w := httptest.NewRecorder()
+		RedirectHandler(tt.port).ServeHTTP(w, r)
+
+		if w.Code != http.StatusPermanentRedirect || w.Header().Get("Location") != tt.target {
+			t.Fatalf("expected redirect to %s, got %d %s", tt.target, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
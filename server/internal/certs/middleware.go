@@ -0,0 +1,38 @@
+package certs
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/karanm6505/dbms/server/internal/problem"
+)
+
+// RequireClientCert rejects requests that did not present a client
+// certificate signed by the configured CA.
+func RequireClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			problem.Write(w, r, problem.New(http.StatusForbidden, problem.CodeForbidden, "a client certificate is required"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RedirectHandler sends plain HTTP requests to the same host and path on the
+// HTTPS port.
+func RedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
@@ -1,78 +1,288 @@
 package config
 
 import (
-	"log"
-	"os"
-	"strconv"
 	"time"
 )
 
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Database drivers. The sqlite driver embeds the database in the binary and
+// keeps it in a single file. The memory driver keeps the sample library in
+// process and loses every change on restart; it is meant for demos and tests.
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// Mail senders. The log sender writes verification codes to the server log
+// and is refused in production.
+const (
+	MailSenderLog  = "log"
+	MailSenderSMTP = "smtp"
+)
+
+// DatabaseConfig describes the primary database server. DSN, when set, replaces
+// the host, port, user, password and name fields; the TLS, timeout and pool
+// settings still apply on top of it. The sqlite driver only reads Path, or
+// DSN in its place.
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
+	Driver              string            `yaml:"driver" toml:"driver"`
+	DSN                 string            `yaml:"dsn" toml:"dsn"`
+	Host                string            `yaml:"host" toml:"host"`
+	Port                string            `yaml:"port" toml:"port"`
+	User                string            `yaml:"user" toml:"user"`
+	Password            string            `yaml:"password" toml:"password"`
+	Name                string            `yaml:"name" toml:"name"`
+	Path                string            `yaml:"path" toml:"path"`
+	AutoMigrate         bool              `yaml:"auto_migrate" toml:"auto_migrate"`
+	TLS                 DatabaseTLSConfig `yaml:"tls" toml:"tls"`
+	DialTimeout         time.Duration     `yaml:"dial_timeout" toml:"dial_timeout"`
+	ReadTimeout         time.Duration     `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout        time.Duration     `yaml:"write_timeout" toml:"write_timeout"`
+	ConnectRetryTimeout time.Duration     `yaml:"connect_retry_timeout" toml:"connect_retry_timeout"`
+	MaxOpenConns        int               `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns        int               `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime     time.Duration     `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	ConnMaxIdleTime     time.Duration     `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+	Replica             ReplicaConfig     `yaml:"replica" toml:"replica"`
+}
+
+// DatabaseTLSConfig encrypts the database connection. Mode is one of the
+// MySQL driver's tls values (false, true, skip-verify, preferred), which are
+// translated to the matching sslmode for PostgreSQL; a CA file or client
+// certificate implies verified TLS.
+type DatabaseTLSConfig struct {
+	Mode     string `yaml:"mode" toml:"mode"`
+	CAFile   string `yaml:"ca_file" toml:"ca_file"`
+	CertFile string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile  string `yaml:"key_file" toml:"key_file"`
+}
+
+// ReplicaConfig enables read-replica routing when Host or DSN is set. The
+// replica shares the primary's credentials, database name, TLS and pool
+// settings unless DSN overrides them.
+type ReplicaConfig struct {
+	DSN            string        `yaml:"dsn" toml:"dsn"`
+	Host           string        `yaml:"host" toml:"host"`
+	Port           string        `yaml:"port" toml:"port"`
+	HealthInterval time.Duration `yaml:"health_interval" toml:"health_interval"`
+}
+
+// Networked reports whether the driver talks to a database server, as
+// opposed to the embedded sqlite and in-process memory drivers.
+func (c DatabaseConfig) Networked() bool {
+	return c.Driver == DriverMySQL || c.Driver == DriverPostgres
+}
+
+func (c ReplicaConfig) Enabled() bool {
+	return c.Host != "" || c.DSN != ""
 }
 
 type APIConfig struct {
-	Port int
+	Port              int           `yaml:"port" toml:"port"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
 }
 
 type Config struct {
-	API      APIConfig
-	Database DatabaseConfig
-	Auth     AuthConfig
+	// Environment is development or production; production refuses to
+	// start with the insecure defaults that are convenient locally.
+	Environment string         `yaml:"environment" toml:"environment"`
+	API         APIConfig      `yaml:"api" toml:"api"`
+	TLS         TLSConfig      `yaml:"tls" toml:"tls"`
+	CORS        CORSConfig     `yaml:"cors" toml:"cors"`
+	Database    DatabaseConfig `yaml:"database" toml:"database"`
+	Auth        AuthConfig     `yaml:"auth" toml:"auth"`
+	Console     ConsoleConfig  `yaml:"console" toml:"console"`
+	Mail        MailConfig     `yaml:"mail" toml:"mail"`
+	Metrics     MetricsConfig  `yaml:"metrics" toml:"metrics"`
+	Log         LogConfig      `yaml:"log" toml:"log"`
+	Tracing     TracingConfig  `yaml:"tracing" toml:"tracing"`
+}
+
+// TLSConfig switches the API to HTTPS (with HTTP/2) when CertFile is set.
+// ClientCAFile enables mutual TLS for the /api/admin routes, and RedirectAddr
+// starts a plain HTTP listener that redirects to HTTPS.
+type TLSConfig struct {
+	CertFile       string        `yaml:"cert_file" toml:"cert_file"`
+	KeyFile        string        `yaml:"key_file" toml:"key_file"`
+	MinVersion     string        `yaml:"min_version" toml:"min_version"`
+	ClientCAFile   string        `yaml:"client_ca_file" toml:"client_ca_file"`
+	RedirectAddr   string        `yaml:"redirect_addr" toml:"redirect_addr"`
+	ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins"`
 }
 
 type AuthConfig struct {
-	JWTSecret string
-	TokenTTL  time.Duration
+	JWTSecret string        `yaml:"jwt_secret" toml:"jwt_secret"`
+	TokenTTL  time.Duration `yaml:"token_ttl" toml:"token_ttl"`
 }
 
-func Load() Config {
-	port := getEnvAsInt("API_PORT", 5050)
-	tokenTTL := getEnvAsDuration("JWT_TOKEN_TTL", 12*time.Hour)
+type ConsoleConfig struct {
+	MaxRows int           `yaml:"max_rows" toml:"max_rows"`
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+}
 
+// MailConfig selects how email verification codes reach students. The smtp
+// sender submits them to Host, upgrading to TLS with STARTTLS when the
+// server offers it, and authenticates when Username is set.
+type MailConfig struct {
+	Sender   string `yaml:"sender" toml:"sender"`
+	From     string `yaml:"from" toml:"from"`
+	Host     string `yaml:"host" toml:"host"`
+	Port     string `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+}
+
+// MetricsConfig controls the Prometheus endpoint. An empty Addr serves
+// /metrics on the API port; otherwise it gets its own listener.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"`
+	Addr    string `yaml:"addr" toml:"addr"`
+	Token   string `yaml:"token" toml:"token"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level"`
+	Format string `yaml:"format" toml:"format"`
+}
+
+// TracingConfig selects the OpenTelemetry span exporter: off, stdout or otlp.
+// An empty OTLPEndpoint defers to the standard OTEL_EXPORTER_OTLP_* variables.
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter" toml:"exporter"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+	OTLPInsecure bool    `yaml:"otlp_insecure" toml:"otlp_insecure"`
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+	ServiceName  string  `yaml:"service_name" toml:"service_name"`
+}
+
+// developmentJWTSecret is the built-in signing key. It keeps a fresh checkout
+// working and is rejected in production.
+const developmentJWTSecret = "development-secret"
+
+func Default() Config {
 	return Config{
-		API: APIConfig{Port: port},
+		Environment: EnvDevelopment,
+		API: APIConfig{
+			Port:              5050,
+			ReadHeaderTimeout: 15 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      15 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   10 * time.Second,
+		},
+		TLS: TLSConfig{
+			MinVersion:     "1.2",
+			ReloadInterval: 30 * time.Second,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{
+				"http://localhost:5173",
+				"http://127.0.0.1:5173",
+				"http://localhost:8080",
+				"http://127.0.0.1:8080",
+			},
+		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "3306"),
-			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", ""),
-			Name:     getEnv("DB_NAME", "Library_Management_System"),
+			Driver:              DriverMySQL,
+			Host:                "localhost",
+			Port:                "3306",
+			User:                "root",
+			Name:                "Library_Management_System",
+			Path:                "library.db",
+			DialTimeout:         5 * time.Second,
+			ReadTimeout:         30 * time.Second,
+			WriteTimeout:        30 * time.Second,
+			ConnectRetryTimeout: time.Minute,
+			MaxOpenConns:        10,
+			MaxIdleConns:        5,
+			ConnMaxLifetime:     time.Hour,
+			ConnMaxIdleTime:     5 * time.Minute,
+			Replica: ReplicaConfig{
+				Port:           "3306",
+				HealthInterval: 10 * time.Second,
+			},
 		},
 		Auth: AuthConfig{
-			JWTSecret: getEnv("JWT_SECRET", "development-secret"),
-			TokenTTL:  tokenTTL,
+			JWTSecret: developmentJWTSecret,
+			TokenTTL:  12 * time.Hour,
+		},
+		Console: ConsoleConfig{
+			MaxRows: 1000,
+			Timeout: 10 * time.Second,
+		},
+		Mail: MailConfig{
+			Sender: MailSenderLog,
+			Port:   "587",
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "off",
+			SampleRatio: 1,
+			ServiceName: "library-api",
 		},
 	}
 }
 
-func getEnv(key, fallback string) string {
-	if value, exists := os.LookupEnv(key); exists && value != "" {
-		return value
+// Load layers the configuration: built-in defaults, then the YAML or TOML
+// file at path (or $CONFIG_FILE), then environment variables. It reports
+// malformed values instead of falling back; call Validate before use.
+func Load(path string) (Config, error) {
+	if path == "" {
+		path = getEnv("CONFIG_FILE", "")
+	}
+
+	cfg := Default()
+	if err := layer(path, &cfg); err != nil {
+		return cfg, err
 	}
-	return fallback
-}
 
-func getEnvAsInt(key string, fallback int) int {
-	if value, exists := os.LookupEnv(key); exists && value != "" {
-		if parsed, err := strconv.Atoi(value); err == nil {
-			return parsed
+	// An embedded database starts out empty, so the sqlite driver migrates
+	// on startup unless the file or environment says otherwise. The layers
+	// are applied again on top of that default, since either may set the
+	// driver.
+	if cfg.Database.Driver == DriverSQLite {
+		cfg = Default()
+		cfg.Database.AutoMigrate = true
+		if err := layer(path, &cfg); err != nil {
+			return cfg, err
 		}
-		log.Printf("warning: %s must be an integer, falling back to %d", key, fallback)
 	}
-	return fallback
+
+	return cfg, nil
 }
 
-func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
-	if value, exists := os.LookupEnv(key); exists && value != "" {
-		if parsed, err := time.ParseDuration(value); err == nil {
-			return parsed
+// layer applies the config file, when there is one, and then the
+// environment to cfg.
+func layer(path string, cfg *Config) error {
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return err
 		}
-		log.Printf("warning: %s must be a valid duration (e.g. 12h), falling back to %s", key, fallback)
 	}
-	return fallback
+
+	return applyEnv(cfg)
 }
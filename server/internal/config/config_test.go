@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadLayersFileAndEnvironment(t *testing.T) {
+	path := writeFile(t, "library.yaml", `
+api:
+  port: 6060
+  write_timeout: 30s
+database:
+  host: db.internal
+  max_open_conns: 25
+cors:
+  allowed_origins: [https://library.example.com]
+`)
+	t.Setenv("DB_HOST", "replica.internal")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.API.Port != 6060 || cfg.API.WriteTimeout != 30*time.Second {
+		t.Fatalf("file values not applied: %+v", cfg.API)
+	}
+	if cfg.API.ReadTimeout != 15*time.Second {
+		t.Fatalf("default read timeout lost, got %s", cfg.API.ReadTimeout)
+	}
+	if cfg.Database.Host != "replica.internal" || cfg.Database.MaxOpenConns != 25 {
+		t.Fatalf("expected env to override file, got %+v", cfg.Database)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 1 || cfg.CORS.AllowedOrigins[0] != "https://library.example.com" {
+		t.Fatalf("unexpected origins %v", cfg.CORS.AllowedOrigins)
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	path := writeFile(t, "library.toml", `
+environment = "development"
+
+[console]
+max_rows = 50
+timeout = "3s"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Console.MaxRows != 50 || cfg.Console.Timeout != 3*time.Second {
+		t.Fatalf("unexpected console config %+v", cfg.Console)
+	}
+}
+
+func TestLoadRejectsUnknownKeysAndBadEnv(t *testing.T) {
+	if _, err := Load(writeFile(t, "library.yaml", "api:\n  prot: 1\n")); err == nil {
+		t.Fatal("expected unknown YAML key to be rejected")
+	}
+	if _, err := Load(writeFile(t, "library.toml", "[api]\nprot = 1\n")); err == nil {
+		t.Fatal("expected unknown TOML key to be rejected")
+	}
+
+	t.Setenv("API_PORT", "fifty")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "API_PORT") {
+		t.Fatalf("expected API_PORT parse error, got %v", err)
+	}
+}
+
+func TestValidateProduction(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("defaults should be valid in development: %v", err)
+	}
+
+	cfg.Environment = EnvProduction
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected insecure production defaults to be rejected")
+	}
+	for _, want := range []string{"auth.jwt_secret", "database.password", "database.user", "metrics.token", "mail.sender"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s to be reported, got:\n%v", want, err)
+		}
+	}
+
+	cfg.Auth.JWTSecret = strings.Repeat("s", minProductionSecretLength)
+	cfg.Database.User = "library"
+	cfg.Database.Password = "secret"
+	cfg.Metrics.Token = "scrape"
+	cfg.Mail = MailConfig{Sender: MailSenderSMTP, From: "library@example.com", Host: "smtp.example.com", Port: "587"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected hardened production config to pass, got %v", err)
+	}
+}
+
+func TestValidateMail(t *testing.T) {
+	cfg := Default()
+	cfg.Mail.Sender = MailSenderSMTP
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "mail.host") {
+		t.Fatalf("expected the smtp sender to need a host, got %v", err)
+	}
+
+	cfg.Mail.Host, cfg.Mail.From = "smtp.example.com", "library@example.com"
+	cfg.Mail.Username = "library"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "mail.password") {
+		t.Fatalf("expected a username without a password to be rejected, got %v", err)
+	}
+
+	cfg.Mail.Password = "secret"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected smtp settings to pass, got %v", err)
+	}
+	if cfg.Redacted().Mail.Password == "secret" {
+		t.Fatal("mail.password was not redacted")
+	}
+
+	cfg.Mail.Sender = "pigeon"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "mail.sender") {
+		t.Fatalf("expected an unknown sender to be rejected, got %v", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "hunter2"
+	cfg.Metrics.Token = "scrape"
+
+	redacted := cfg.Redacted()
+	if redacted.Database.Password == "hunter2" || redacted.Auth.JWTSecret == developmentJWTSecret || redacted.Metrics.Token == "scrape" {
+		t.Fatalf("secrets were not redacted: %+v", redacted)
+	}
+	if cfg.Database.Password != "hunter2" {
+		t.Fatal("Redacted modified the original config")
+	}
+}
+
+func TestValidateMemoryDriver(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Driver = DriverMemory
+	cfg.Database.Host = ""
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("memory driver should not need a database host: %v", err)
+	}
+
+	cfg.Environment = EnvProduction
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database.driver") {
+		t.Fatalf("expected memory driver to be rejected in production, got %v", err)
+	}
+
+	cfg = Default()
+	cfg.Database.Driver = "oracle"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database.driver") {
+		t.Fatalf("expected unknown driver to be rejected, got %v", err)
+	}
+}
+
+func TestValidateSQLiteDriver(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Host = ""
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("sqlite driver should not need a database host: %v", err)
+	}
+
+	cfg.Database.Path = ""
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database.path") {
+		t.Fatalf("expected a missing path to be rejected, got %v", err)
+	}
+
+	cfg = Default()
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Replica.Host = "replica"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database.replica") {
+		t.Fatalf("expected a replica to be rejected, got %v", err)
+	}
+}
+
+func TestRedactPostgresDSN(t *testing.T) {
+	got := redactDSN("postgres://library:hunter2@db:5432/library?sslmode=require")
+	if strings.Contains(got, "hunter2") || !strings.Contains(got, "REDACTED") || !strings.Contains(got, "db:5432/library") {
+		t.Fatalf("unexpected redaction %q", got)
+	}
+}
+
+func TestLoadSQLiteAutoMigrates(t *testing.T) {
+	t.Setenv("DB_DRIVER", DriverSQLite)
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.Database.AutoMigrate {
+		t.Fatal("expected sqlite to auto-migrate by default")
+	}
+
+	cfg, err = Load(writeFile(t, "library.yaml", "database:\n  auto_migrate: false\n"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Database.AutoMigrate {
+		t.Fatal("expected an explicit auto_migrate: false to be kept")
+	}
+}
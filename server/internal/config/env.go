@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv overrides cfg with every variable that is set and non-empty.
+// Unparseable values are collected and returned together.
+func applyEnv(cfg *Config) error {
+	env := &envReader{}
+
+	env.string("APP_ENV", &cfg.Environment)
+
+	env.int("API_PORT", &cfg.API.Port)
+	env.duration("API_READ_HEADER_TIMEOUT", &cfg.API.ReadHeaderTimeout)
+	env.duration("API_READ_TIMEOUT", &cfg.API.ReadTimeout)
+	env.duration("API_WRITE_TIMEOUT", &cfg.API.WriteTimeout)
+	env.duration("API_IDLE_TIMEOUT", &cfg.API.IdleTimeout)
+	env.duration("API_SHUTDOWN_TIMEOUT", &cfg.API.ShutdownTimeout)
+
+	env.string("TLS_CERT_FILE", &cfg.TLS.CertFile)
+	env.string("TLS_KEY_FILE", &cfg.TLS.KeyFile)
+	env.string("TLS_MIN_VERSION", &cfg.TLS.MinVersion)
+	env.string("TLS_CLIENT_CA_FILE", &cfg.TLS.ClientCAFile)
+	env.string("TLS_REDIRECT_ADDR", &cfg.TLS.RedirectAddr)
+	env.duration("TLS_RELOAD_INTERVAL", &cfg.TLS.ReloadInterval)
+
+	env.list("FRONTEND_ORIGINS", &cfg.CORS.AllowedOrigins)
+
+	env.string("DB_DRIVER", &cfg.Database.Driver)
+	env.string("DB_DSN", &cfg.Database.DSN)
+	env.string("DB_HOST", &cfg.Database.Host)
+	env.string("DB_PORT", &cfg.Database.Port)
+	env.string("DB_USER", &cfg.Database.User)
+	env.string("DB_PASSWORD", &cfg.Database.Password)
+	env.string("DB_NAME", &cfg.Database.Name)
+	env.string("DB_PATH", &cfg.Database.Path)
+	env.bool("DB_AUTO_MIGRATE", &cfg.Database.AutoMigrate)
+	env.string("DB_TLS", &cfg.Database.TLS.Mode)
+	env.string("DB_TLS_CA_FILE", &cfg.Database.TLS.CAFile)
+	env.string("DB_TLS_CERT_FILE", &cfg.Database.TLS.CertFile)
+	env.string("DB_TLS_KEY_FILE", &cfg.Database.TLS.KeyFile)
+	env.duration("DB_DIAL_TIMEOUT", &cfg.Database.DialTimeout)
+	env.duration("DB_READ_TIMEOUT", &cfg.Database.ReadTimeout)
+	env.duration("DB_WRITE_TIMEOUT", &cfg.Database.WriteTimeout)
+	env.duration("DB_CONNECT_RETRY_TIMEOUT", &cfg.Database.ConnectRetryTimeout)
+	env.int("DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+	env.int("DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+	env.duration("DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+	env.duration("DB_CONN_MAX_IDLE_TIME", &cfg.Database.ConnMaxIdleTime)
+	env.string("DB_REPLICA_DSN", &cfg.Database.Replica.DSN)
+	env.string("DB_REPLICA_HOST", &cfg.Database.Replica.Host)
+	env.string("DB_REPLICA_PORT", &cfg.Database.Replica.Port)
+	env.duration("DB_REPLICA_HEALTH_INTERVAL", &cfg.Database.Replica.HealthInterval)
+
+	env.string("JWT_SECRET", &cfg.Auth.JWTSecret)
+	env.duration("JWT_TOKEN_TTL", &cfg.Auth.TokenTTL)
+
+	env.int("CONSOLE_MAX_ROWS", &cfg.Console.MaxRows)
+	env.duration("CONSOLE_QUERY_TIMEOUT", &cfg.Console.Timeout)
+
+	env.string("MAIL_SENDER", &cfg.Mail.Sender)
+	env.string("MAIL_FROM", &cfg.Mail.From)
+	env.string("SMTP_HOST", &cfg.Mail.Host)
+	env.string("SMTP_PORT", &cfg.Mail.Port)
+	env.string("SMTP_USERNAME", &cfg.Mail.Username)
+	env.string("SMTP_PASSWORD", &cfg.Mail.Password)
+
+	env.bool("METRICS_ENABLED", &cfg.Metrics.Enabled)
+	env.string("METRICS_ADDR", &cfg.Metrics.Addr)
+	env.string("METRICS_TOKEN", &cfg.Metrics.Token)
+
+	env.string("LOG_LEVEL", &cfg.Log.Level)
+	env.string("LOG_FORMAT", &cfg.Log.Format)
+
+	env.string("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	env.string("TRACING_OTLP_ENDPOINT", &cfg.Tracing.OTLPEndpoint)
+	env.bool("TRACING_OTLP_INSECURE", &cfg.Tracing.OTLPInsecure)
+	env.float("TRACING_SAMPLE_RATIO", &cfg.Tracing.SampleRatio)
+	env.string("TRACING_SERVICE_NAME", &cfg.Tracing.ServiceName)
+
+	return errors.Join(env.errs...)
+}
+
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	return value, ok && value != ""
+}
+
+func (e *envReader) fail(key, kind, value string) {
+	e.errs = append(e.errs, fmt.Errorf("%s must be %s, got %q", key, kind, value))
+}
+
+func (e *envReader) string(key string, dst *string) {
+	if value, ok := e.lookup(key); ok {
+		*dst = value
+	}
+}
+
+func (e *envReader) list(key string, dst *[]string) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	*dst = items
+}
+
+func (e *envReader) int(key string, dst *int) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			e.fail(key, "an integer", value)
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func (e *envReader) float(key string, dst *float64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.fail(key, "a number", value)
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			e.fail(key, "a boolean", value)
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			e.fail(key, "a duration (e.g. 12h)", value)
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const minProductionSecretLength = 32
+
+const redacted = "[REDACTED]"
+
+// Validate reports every invalid setting at once. In production it also
+// refuses the defaults that are only acceptable on a developer machine.
+func (c Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch c.Environment {
+	case EnvDevelopment, EnvProduction:
+	default:
+		add("environment must be %s or %s, got %q", EnvDevelopment, EnvProduction, c.Environment)
+	}
+
+	if c.API.Port <= 0 || c.API.Port > 65535 {
+		add("api.port must be between 1 and 65535, got %d", c.API.Port)
+	}
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"api.read_header_timeout", c.API.ReadHeaderTimeout},
+		{"api.read_timeout", c.API.ReadTimeout},
+		{"api.write_timeout", c.API.WriteTimeout},
+		{"api.idle_timeout", c.API.IdleTimeout},
+		{"api.shutdown_timeout", c.API.ShutdownTimeout},
+		{"database.conn_max_lifetime", c.Database.ConnMaxLifetime},
+		{"database.conn_max_idle_time", c.Database.ConnMaxIdleTime},
+		{"database.dial_timeout", c.Database.DialTimeout},
+		{"auth.token_ttl", c.Auth.TokenTTL},
+		{"console.timeout", c.Console.Timeout},
+	}
+	for _, duration := range durations {
+		if duration.value <= 0 {
+			add("%s must be positive, got %s", duration.name, duration.value)
+		}
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		add("tls.cert_file and tls.key_file must be set together")
+	}
+	if c.TLS.MinVersion != "1.2" && c.TLS.MinVersion != "1.3" {
+		add("tls.min_version must be 1.2 or 1.3, got %q", c.TLS.MinVersion)
+	}
+	if !c.TLS.Enabled() && (c.TLS.ClientCAFile != "" || c.TLS.RedirectAddr != "") {
+		add("tls.client_ca_file and tls.redirect_addr require tls.cert_file")
+	}
+	if c.TLS.Enabled() && c.TLS.ReloadInterval < 0 {
+		add("tls.reload_interval must not be negative, got %s", c.TLS.ReloadInterval)
+	}
+
+	switch c.Database.Driver {
+	case DriverMySQL, DriverPostgres, DriverSQLite, DriverMemory:
+	default:
+		add("database.driver must be %s, %s, %s or %s, got %q", DriverMySQL, DriverPostgres, DriverSQLite, DriverMemory, c.Database.Driver)
+	}
+	if c.Database.Networked() && c.Database.DSN == "" && (c.Database.Host == "" || c.Database.Name == "" || c.Database.User == "") {
+		add("database.host, database.name and database.user are required unless database.dsn is set")
+	}
+	if c.Database.Driver == DriverSQLite && c.Database.DSN == "" && c.Database.Path == "" {
+		add("database.path is required for the sqlite driver unless database.dsn is set")
+	}
+	if c.Database.Driver == DriverSQLite && c.Database.Replica.Enabled() {
+		add("database.replica is not supported by the sqlite driver")
+	}
+	if c.Database.ReadTimeout < 0 || c.Database.WriteTimeout < 0 || c.Database.ConnectRetryTimeout < 0 {
+		add("database.read_timeout, write_timeout and connect_retry_timeout must not be negative")
+	}
+	switch c.Database.TLS.Mode {
+	case "", "false", "true", "skip-verify", "preferred":
+	default:
+		add("database.tls.mode must be false, true, skip-verify or preferred, got %q", c.Database.TLS.Mode)
+	}
+	if (c.Database.TLS.CertFile == "") != (c.Database.TLS.KeyFile == "") {
+		add("database.tls.cert_file and database.tls.key_file must be set together")
+	}
+	if c.Database.Replica.Enabled() && c.Database.Replica.HealthInterval <= 0 {
+		add("database.replica.health_interval must be positive, got %s", c.Database.Replica.HealthInterval)
+	}
+	if c.Database.MaxOpenConns <= 0 {
+		add("database.max_open_conns must be positive, got %d", c.Database.MaxOpenConns)
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		add("database.max_idle_conns must be between 0 and max_open_conns, got %d", c.Database.MaxIdleConns)
+	}
+
+	if c.Auth.JWTSecret == "" {
+		add("auth.jwt_secret is required")
+	}
+	if c.Console.MaxRows <= 0 {
+		add("console.max_rows must be positive, got %d", c.Console.MaxRows)
+	}
+
+	switch c.Mail.Sender {
+	case MailSenderLog:
+	case MailSenderSMTP:
+		if c.Mail.Host == "" || c.Mail.Port == "" || c.Mail.From == "" {
+			add("mail.host, mail.port and mail.from are required for the smtp sender")
+		}
+	default:
+		add("mail.sender must be %s or %s, got %q", MailSenderLog, MailSenderSMTP, c.Mail.Sender)
+	}
+	if (c.Mail.Username == "") != (c.Mail.Password == "") {
+		add("mail.username and mail.password must be set together")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		add("log.level must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "json", "text":
+	default:
+		add("log.format must be json or text, got %q", c.Log.Format)
+	}
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "", "off", "none", "stdout", "otlp":
+	default:
+		add("tracing.exporter must be off, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	if c.Environment == EnvProduction {
+		errs = append(errs, c.productionProblems()...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c Config) productionProblems() []error {
+	var errs []error
+
+	if c.Auth.JWTSecret == developmentJWTSecret || len(c.Auth.JWTSecret) < minProductionSecretLength {
+		errs = append(errs, fmt.Errorf("auth.jwt_secret must be set to a random value of at least %d characters in production", minProductionSecretLength))
+	}
+	if c.Database.Driver == DriverMemory {
+		errs = append(errs, errors.New("database.driver must not be memory in production"))
+	}
+	if c.Mail.Sender == MailSenderLog {
+		errs = append(errs, errors.New("mail.sender must not be log in production"))
+	}
+	if c.Database.Networked() && c.Database.DSN == "" && c.Database.Password == "" {
+		errs = append(errs, errors.New("database.password must be set in production"))
+	}
+	if c.Database.Networked() && c.Database.DSN == "" && c.Database.User == "root" {
+		errs = append(errs, errors.New("database.user must not be root in production"))
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			errs = append(errs, errors.New("cors.allowed_origins must not contain * in production"))
+		}
+	}
+	if c.Metrics.Enabled && c.Metrics.Addr == "" && c.Metrics.Token == "" {
+		errs = append(errs, errors.New("metrics.token is required in production when /metrics shares the API port"))
+	}
+
+	return errs
+}
+
+// Redacted returns a copy that is safe to print or log.
+func (c Config) Redacted() Config {
+	redact := func(value *string) {
+		if *value != "" {
+			*value = redacted
+		}
+	}
+
+	redact(&c.Database.Password)
+	c.Database.DSN = redactDSN(c.Database.DSN)
+	c.Database.Replica.DSN = redactDSN(c.Database.Replica.DSN)
+	redact(&c.Auth.JWTSecret)
+	redact(&c.Metrics.Token)
+	redact(&c.Mail.Password)
+	c.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+
+	return c
+}
+
+// redactDSN hides only the password so the rest of the DSN stays readable.
+// It understands MySQL DSNs and PostgreSQL URLs.
+func redactDSN(dsn string) string {
+	if dsn == "" {
+		return ""
+	}
+
+	if parsed, err := url.Parse(dsn); err == nil && (parsed.Scheme == "postgres" || parsed.Scheme == "postgresql") {
+		if _, ok := parsed.User.Password(); ok {
+			parsed.User = url.UserPassword(parsed.User.Username(), redacted)
+		}
+		return parsed.String()
+	}
+
+	parsed, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return redacted
+	}
+	if parsed.Passwd != "" {
+		parsed.Passwd = redacted
+	}
+	return parsed.FormatDSN()
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+// Cluster pairs the primary with an optional read replica. Reads go to the
+// replica only while it is healthy: Monitor's pings decide that, and so does
+// a read that loses its connection, which marks the replica unhealthy at
+// once instead of at the next tick.
+type Cluster struct {
+	primary *sql.DB
+	replica *sql.DB
+	healthy atomic.Bool
+}
+
+func NewCluster(primary, replica *sql.DB) *Cluster {
+	return &Cluster{primary: primary, replica: replica}
+}
+
+// ConnectCluster connects the primary and, when configured, the replica. An
+// unreachable replica does not stop startup; it is used once Monitor sees it
+// healthy.
+func ConnectCluster(cfg config.DatabaseConfig) (*Cluster, error) {
+	primary, err := Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := NewCluster(primary, nil)
+	if !cfg.Replica.Enabled() {
+		return cluster, nil
+	}
+
+	replica, err := open(cfg, cfg.Replica.DSN, cfg.Replica.Host, cfg.Replica.Port)
+	if err != nil {
+		primary.Close()
+		return nil, err
+	}
+	cluster.replica = replica
+	cluster.check(context.Background())
+
+	return cluster, nil
+}
+
+func (c *Cluster) Primary() *sql.DB {
+	return c.primary
+}
+
+// Reader returns the pool for read-only queries.
+func (c *Cluster) Reader() *sql.DB {
+	if c.replica != nil && c.healthy.Load() {
+		return c.replica
+	}
+	return c.primary
+}
+
+// Read runs fn on the reader. When the replica fails it with a connection
+// error, the replica is marked unhealthy and fn runs again on the primary;
+// the next successful health check brings it back.
+func (c *Cluster) Read(ctx context.Context, fn func(db *sql.DB) error) error {
+	reader := c.Reader()
+	err := fn(reader)
+	if err == nil || reader == c.primary || ctx.Err() != nil || !isConnectionError(err) {
+		return err
+	}
+
+	if c.healthy.CompareAndSwap(true, false) {
+		slog.Warn("read replica failed a query, routing read-only queries to the primary", "error", err)
+	}
+	return fn(c.primary)
+}
+
+// isConnectionError reports whether err says the server could not be
+// reached or dropped the connection, rather than rejecting the query.
+func isConnectionError(err error) bool {
+	var (
+		netErr     net.Error
+		pgErr      *pgconn.PgError
+		connectErr *pgconn.ConnectError
+	)
+	switch {
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, mysql.ErrInvalidConn),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.As(err, &netErr), errors.As(err, &connectErr):
+		return true
+	case errors.As(err, &pgErr):
+		// Connection exceptions, and a server shutting down or starting up.
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	default:
+		return false
+	}
+}
+
+// Replica returns the replica pool and whether it is currently used, or nil
+// when none is configured.
+func (c *Cluster) Replica() (*sql.DB, bool) {
+	return c.replica, c.replica != nil && c.healthy.Load()
+}
+
+// Monitor pings the replica every interval until ctx is done.
+func (c *Cluster) Monitor(ctx context.Context, interval time.Duration) {
+	if c.replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+func (c *Cluster) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	err := c.replica.PingContext(ctx)
+	healthy := err == nil
+
+	if previous := c.healthy.Swap(healthy); previous != healthy {
+		if healthy {
+			slog.Info("read replica healthy, routing read-only queries to it")
+		} else {
+			slog.Warn("read replica unhealthy, routing read-only queries to the primary", "error", err)
+		}
+	}
+}
+
+func (c *Cluster) Close() error {
+	if c.replica != nil {
+		c.replica.Close()
+	}
+	return c.primary.Close()
+}
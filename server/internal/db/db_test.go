@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+func TestDriverConfigFromFields(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Password = "p@ss:word"
+	cfg.TLS.Mode = "skip-verify"
+
+	driver, err := driverConfig(cfg, "", "replica.internal", "3307")
+	if err != nil {
+		t.Fatalf("driverConfig returned error: %v", err)
+	}
+
+	if driver.Addr != "replica.internal:3307" || driver.Passwd != "p@ss:word" || driver.DBName != cfg.Name {
+		t.Fatalf("unexpected driver config %+v", driver)
+	}
+	if !driver.ParseTime || !driver.MultiStatements {
+		t.Fatal("parseTime and multiStatements must always be enabled")
+	}
+	if driver.Timeout != cfg.DialTimeout || driver.ReadTimeout != cfg.ReadTimeout || driver.WriteTimeout != cfg.WriteTimeout {
+		t.Fatalf("timeouts not applied: %+v", driver)
+	}
+	if driver.TLSConfig != "skip-verify" {
+		t.Fatalf("expected tls=skip-verify, got %q", driver.TLSConfig)
+	}
+}
+
+func TestDriverConfigFromDSN(t *testing.T) {
+	cfg := config.Default().Database
+
+	driver, err := driverConfig(cfg, "library:secret@tcp(db:3306)/library?readTimeout=2s", "ignored", "1")
+	if err != nil {
+		t.Fatalf("driverConfig returned error: %v", err)
+	}
+
+	if driver.Addr != "db:3306" || driver.User != "library" {
+		t.Fatalf("DSN not used: %+v", driver)
+	}
+	if driver.ReadTimeout != 2*time.Second || driver.WriteTimeout != cfg.WriteTimeout {
+		t.Fatalf("expected DSN timeouts to win and config to fill the rest, got %+v", driver)
+	}
+	if !driver.ParseTime {
+		t.Fatal("parseTime must be enabled for DSNs too")
+	}
+
+	if _, err := driverConfig(cfg, "not a dsn", "", ""); err == nil {
+		t.Fatal("expected malformed DSN to be rejected")
+	}
+}
+
+func TestWaitForDatabaseRetries(t *testing.T) {
+	database, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer database.Close()
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	mock.ExpectPing()
+
+	if err := waitForDatabase(context.Background(), database, 5*time.Second); err != nil {
+		t.Fatalf("expected second ping to succeed, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForDatabaseGivesUp(t *testing.T) {
+	database, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer database.Close()
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	if err := waitForDatabase(context.Background(), database, 0); err == nil {
+		t.Fatal("expected an error once the retry budget is spent")
+	}
+}
+
+func TestClusterFallsBackToPrimary(t *testing.T) {
+	primary, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer primary.Close()
+
+	replica, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer replica.Close()
+
+	cluster := NewCluster(primary, replica)
+	if cluster.Reader() != primary {
+		t.Fatal("replica must not be used before its first health check")
+	}
+
+	mock.ExpectPing()
+	cluster.check(context.Background())
+	if cluster.Reader() != replica {
+		t.Fatal("expected healthy replica to serve reads")
+	}
+
+	mock.ExpectPing().WillReturnError(errors.New("replica down"))
+	cluster.check(context.Background())
+	if cluster.Reader() != primary {
+		t.Fatal("expected reads to fall back to the primary")
+	}
+
+	if NewCluster(primary, nil).Reader() != primary {
+		t.Fatal("expected primary without a replica")
+	}
+}
+
+func TestClusterReadFailsOverOnConnectionError(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer primary.Close()
+
+	replica, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer replica.Close()
+
+	cluster := NewCluster(primary, replica)
+	replicaMock.ExpectPing()
+	cluster.check(context.Background())
+
+	count := func(db *sql.DB) error {
+		var n int
+		return db.QueryRow("SELECT COUNT(*) FROM book").Scan(&n)
+	}
+
+	// A rejected query is the caller's problem, not the replica's.
+	replicaMock.ExpectQuery("SELECT COUNT").WillReturnError(errors.New("unknown column"))
+	if err := cluster.Read(context.Background(), count); err == nil || cluster.Reader() != replica {
+		t.Fatalf("expected the error to be returned and the replica kept, got %v", err)
+	}
+
+	replicaMock.ExpectQuery("SELECT COUNT").WillReturnError(mysql.ErrInvalidConn)
+	primaryMock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	if err := cluster.Read(context.Background(), count); err != nil {
+		t.Fatalf("expected the read to be retried on the primary, got %v", err)
+	}
+	if cluster.Reader() != primary {
+		t.Fatal("expected the replica to be marked unhealthy")
+	}
+
+	if err := primaryMock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if err := replicaMock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresConfigFromFields(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverPostgres
+	cfg.Name = "library"
+	cfg.User = "o'brien"
+	cfg.Password = "p@ss word"
+	cfg.TLS.Mode = "false"
+
+	connConfig, err := postgresConfig(cfg, "", "pg.internal", "5433")
+	if err != nil {
+		t.Fatalf("postgresConfig returned error: %v", err)
+	}
+
+	if connConfig.Host != "pg.internal" || connConfig.Port != 5433 || connConfig.User != "o'brien" || connConfig.Database != "library" {
+		t.Fatalf("unexpected connection config %+v", connConfig.Config)
+	}
+	if connConfig.Password != "p@ss word" {
+		t.Fatalf("password not applied, got %q", connConfig.Password)
+	}
+	if connConfig.ConnectTimeout != cfg.DialTimeout {
+		t.Fatalf("expected connect timeout %s, got %s", cfg.DialTimeout, connConfig.ConnectTimeout)
+	}
+	if connConfig.TLSConfig != nil {
+		t.Fatal("tls mode false must disable TLS")
+	}
+}
+
+func TestPostgresConfigFromDSN(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverPostgres
+
+	connConfig, err := postgresConfig(cfg, "postgres://library:secret@db:5432/library?sslmode=disable&connect_timeout=2", "ignored", "1")
+	if err != nil {
+		t.Fatalf("postgresConfig returned error: %v", err)
+	}
+
+	if connConfig.Host != "db" || connConfig.Password != "secret" {
+		t.Fatalf("DSN not used: %+v", connConfig.Config)
+	}
+	if connConfig.ConnectTimeout != 2*time.Second {
+		t.Fatalf("expected the DSN connect timeout to win, got %s", connConfig.ConnectTimeout)
+	}
+
+	if _, err := postgresConfig(cfg, "postgres://db:notaport/library", "", ""); err == nil {
+		t.Fatal("expected malformed DSN to be rejected")
+	}
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+// driverConfig builds the driver configuration for a server. An explicit DSN
+// wins over the individual fields; host and port override the configured
+// ones so the same settings can describe a replica.
+func driverConfig(cfg config.DatabaseConfig, dsn, host, port string) (*mysql.Config, error) {
+	var driver *mysql.Config
+
+	if dsn != "" {
+		parsed, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid database DSN: %w", err)
+		}
+		driver = parsed
+	} else {
+		driver = mysql.NewConfig()
+		driver.User = cfg.User
+		driver.Passwd = cfg.Password
+		driver.Net = "tcp"
+		driver.Addr = net.JoinHostPort(host, port)
+		driver.DBName = cfg.Name
+	}
+
+	// Migrations and routine definitions rely on both.
+	driver.ParseTime = true
+	driver.MultiStatements = true
+
+	if driver.Timeout == 0 {
+		driver.Timeout = cfg.DialTimeout
+	}
+	if driver.ReadTimeout == 0 {
+		driver.ReadTimeout = cfg.ReadTimeout
+	}
+	if driver.WriteTimeout == 0 {
+		driver.WriteTimeout = cfg.WriteTimeout
+	}
+
+	if err := applyTLS(driver, cfg.TLS); err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+}
+
+func applyTLS(driver *mysql.Config, cfg config.DatabaseTLSConfig) error {
+	if cfg.CAFile == "" && cfg.CertFile == "" {
+		if cfg.Mode != "" {
+			driver.TLSConfig = cfg.Mode
+		}
+		return nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("read database CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("database CA file contains no PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load database client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if cfg.Mode == "skip-verify" {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	driver.TLS = tlsConfig
+	return nil
+}
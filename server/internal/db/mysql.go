@@ -1,36 +1,143 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
+
 	"github.com/karanm6505/dbms/server/internal/config"
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/migrate"
+)
 
-	_ "github.com/go-sql-driver/mysql"
+const (
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 10 * time.Second
 )
 
+// Connect opens the primary database with the configured driver. It keeps
+// retrying the first ping with exponential backoff for up to
+// ConnectRetryTimeout, so the API can start alongside a database container
+// that is still initialising.
 func Connect(cfg config.DatabaseConfig) (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&multiStatements=true",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Name,
-	)
-
-	database, err := sql.Open("mysql", dsn)
+	database, err := open(cfg, cfg.DSN, cfg.Host, cfg.Port)
 	if err != nil {
 		return nil, err
 	}
 
-	database.SetMaxOpenConns(10)
-	database.SetMaxIdleConns(5)
-	database.SetConnMaxLifetime(time.Hour)
+	if err := waitForDatabase(context.Background(), database, cfg.ConnectRetryTimeout); err != nil {
+		database.Close()
+		return nil, err
+	}
+
+	if cfg.AutoMigrate {
+		if err := autoMigrate(database, Dialect(cfg)); err != nil {
+			database.Close()
+			return nil, err
+		}
+	}
+
+	return database, nil
+}
+
+// Dialect returns the SQL dialect spoken by the configured driver.
+func Dialect(cfg config.DatabaseConfig) dialect.Dialect {
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		return dialect.Postgres
+	case config.DriverSQLite:
+		return dialect.SQLite
+	default:
+		return dialect.MySQL
+	}
+}
 
-	if err := database.Ping(); err != nil {
+func open(cfg config.DatabaseConfig, dsn, host, port string) (*sql.DB, error) {
+	var (
+		database *sql.DB
+		err      error
+	)
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		database, err = openPostgres(cfg, dsn, host, port)
+	case config.DriverSQLite:
+		database, err = openSQLite(cfg, dsn)
+	default:
+		database, err = openMySQL(cfg, dsn, host, port)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	database.SetMaxOpenConns(cfg.MaxOpenConns)
+	database.SetMaxIdleConns(cfg.MaxIdleConns)
+	database.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	database.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	if cfg.Driver == config.DriverSQLite {
+		sqlitePool(database, cfg)
+	}
+
 	return database, nil
 }
+
+func openMySQL(cfg config.DatabaseConfig, dsn, host, port string) (*sql.DB, error) {
+	driver, err := driverConfig(cfg, dsn, host, port)
+	if err != nil {
+		return nil, err
+	}
+
+	connector, err := mysql.NewConnector(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.OpenDB(connector), nil
+}
+
+func waitForDatabase(ctx context.Context, database *sql.DB, budget time.Duration) error {
+	deadline := time.Now().Add(budget)
+	backoff := initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := database.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("database unreachable after %d attempts: %w", attempt, err)
+		}
+
+		slog.Warn("database not ready, retrying", "attempt", attempt, "retry_in", backoff.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func autoMigrate(database *sql.DB, d dialect.Dialect) error {
+	migrator, err := migrate.NewForDialect(database, d)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		return fmt.Errorf("auto-migrate: %w", err)
+	}
+
+	for _, migration := range applied {
+		slog.Info("applied migration", "version", migration.Version, "name", migration.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+// openPostgres opens a pool through pgx's database/sql driver. Queries
+// without arguments use the simple protocol, so migrations can send a whole
+// PL/pgSQL script in one call.
+func openPostgres(cfg config.DatabaseConfig, dsn, host, port string) (*sql.DB, error) {
+	connConfig, err := postgresConfig(cfg, dsn, host, port)
+	if err != nil {
+		return nil, err
+	}
+	return stdlib.OpenDB(*connConfig), nil
+}
+
+// postgresConfig builds the pgx configuration for a server. As for MySQL, an
+// explicit DSN (a postgres:// URL or key=value string) wins over the
+// individual fields, and the TLS settings are translated to sslmode.
+func postgresConfig(cfg config.DatabaseConfig, dsn, host, port string) (*pgx.ConnConfig, error) {
+	connString := dsn
+	if connString == "" {
+		params := []string{
+			"host=" + quoteConnValue(host),
+			"port=" + quoteConnValue(port),
+			"user=" + quoteConnValue(cfg.User),
+			"dbname=" + quoteConnValue(cfg.Name),
+		}
+		params = append(params, postgresTLSParams(cfg.TLS)...)
+		connString = strings.Join(params, " ")
+	}
+
+	connConfig, err := pgx.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database DSN: %w", err)
+	}
+
+	if dsn == "" {
+		connConfig.Password = cfg.Password
+	}
+	if connConfig.ConnectTimeout == 0 {
+		connConfig.ConnectTimeout = cfg.DialTimeout
+	}
+
+	return connConfig, nil
+}
+
+// postgresTLSParams maps the MySQL-style TLS modes onto libpq's sslmode.
+// An empty mode keeps the libpq default, prefer, unless certificates are
+// configured.
+func postgresTLSParams(cfg config.DatabaseTLSConfig) []string {
+	var params []string
+
+	mode := ""
+	switch cfg.Mode {
+	case "false":
+		mode = "disable"
+	case "true":
+		mode = "verify-full"
+	case "skip-verify":
+		mode = "require"
+	case "preferred":
+		mode = "prefer"
+	case "":
+		if cfg.CAFile != "" || cfg.CertFile != "" {
+			mode = "verify-full"
+		}
+	}
+	if mode != "" {
+		params = append(params, "sslmode="+mode)
+	}
+
+	if cfg.CAFile != "" {
+		params = append(params, "sslrootcert="+quoteConnValue(cfg.CAFile))
+	}
+	if cfg.CertFile != "" {
+		params = append(params, "sslcert="+quoteConnValue(cfg.CertFile), "sslkey="+quoteConnValue(cfg.KeyFile))
+	}
+
+	return params
+}
+
+// quoteConnValue quotes a value for a key=value connection string.
+func quoteConnValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"database/sql"
+	"net/url"
+	"strconv"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+
+	// Registers the pure-Go "sqlite" driver, so the binary needs no cgo.
+	_ "modernc.org/sqlite"
+)
+
+const sqliteMemory = ":memory:"
+
+// openSQLite opens the embedded database file, creating it when missing.
+// An explicit DSN is passed to the driver untouched.
+func openSQLite(cfg config.DatabaseConfig, dsn string) (*sql.DB, error) {
+	if dsn == "" {
+		dsn = sqliteDSN(cfg)
+	}
+	return sql.Open("sqlite", dsn)
+}
+
+// sqliteDSN turns on the settings every connection needs: foreign keys,
+// which SQLite leaves off by default, a busy timeout so writers queue instead
+// of failing, WAL so readers are not blocked by a writer, and immediate
+// transactions so a transaction that writes holds the lock from its first
+// statement. The last one stands in for the row locks of the server drivers.
+func sqliteDSN(cfg config.DatabaseConfig) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout("+strconv.FormatInt(cfg.WriteTimeout.Milliseconds(), 10)+")")
+	if cfg.Path != sqliteMemory {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+
+	return "file:" + cfg.Path + "?" + params.Encode()
+}
+
+// sqlitePool adjusts the pool for the embedded database. Every connection to
+// :memory: opens a new, empty database, so an in-memory pool is held to a
+// single connection that is never recycled.
+func sqlitePool(database *sql.DB, cfg config.DatabaseConfig) {
+	if cfg.DSN != "" || cfg.Path != sqliteMemory {
+		return
+	}
+
+	database.SetMaxOpenConns(1)
+	database.SetMaxIdleConns(1)
+	database.SetConnMaxLifetime(0)
+	database.SetConnMaxIdleTime(0)
+}
@@ -0,0 +1,91 @@
+// Package dialect captures the SQL differences between the supported
+// databases that the repositories and the migrator have to care about.
+// Queries are written once with ? placeholders and portable SQL, and Rebind
+// adapts them for PostgreSQL.
+package dialect
+
+import "strconv"
+
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// ForUpdate returns the clause that locks the rows read by a SELECT until the
+// transaction ends. SQLite has no row locks; its write transactions lock the
+// whole database when they begin, so the clause is empty.
+func (d Dialect) ForUpdate() string {
+	if d == SQLite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// Rebind rewrites ? placeholders into PostgreSQL's $1, $2, ... form. Question
+// marks inside string literals, quoted identifiers and comments are left
+// alone. MySQL and SQLite understand ? and get the query unchanged.
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres {
+		return query
+	}
+
+	out := make([]byte, 0, len(query)+8)
+	n := 0
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := skipQuoted(query, i, c)
+			out = append(out, query[i:end]...)
+			i = end - 1
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := len(query)
+			for j := i; j < len(query); j++ {
+				if query[j] == '\n' {
+					end = j
+					break
+				}
+			}
+			out = append(out, query[i:end]...)
+			i = end - 1
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := len(query)
+			for j := i + 2; j+1 < len(query); j++ {
+				if query[j] == '*' && query[j+1] == '/' {
+					end = j + 2
+					break
+				}
+			}
+			out = append(out, query[i:end]...)
+			i = end - 1
+		case c == '?':
+			n++
+			out = append(out, '$')
+			out = strconv.AppendInt(out, int64(n), 10)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return string(out)
+}
+
+// skipQuoted returns the index just past the literal starting at start. A
+// doubled quote character is an escaped quote.
+func skipQuoted(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
@@ -0,0 +1,31 @@
+package dialect
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"placeholders", "SELECT * FROM book WHERE Book_ID = ? AND Status = ?", "SELECT * FROM book WHERE Book_ID = $1 AND Status = $2"},
+		{"string literal", "SELECT '?', 'it''s ?' FROM t WHERE a = ?", "SELECT '?', 'it''s ?' FROM t WHERE a = $1"},
+		{"quoted identifier", `SELECT "why?" FROM t WHERE a = ?`, `SELECT "why?" FROM t WHERE a = $1`},
+		{"comments", "SELECT 1 -- why?\nFROM t /* ? */ WHERE a = ?", "SELECT 1 -- why?\nFROM t /* ? */ WHERE a = $1"},
+		{"unterminated literal", "SELECT 'abc ?", "SELECT 'abc ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Postgres.Rebind(tt.query); got != tt.want {
+				t.Fatalf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	for _, d := range []Dialect{MySQL, SQLite} {
+		if got := d.Rebind("SELECT ?"); got != "SELECT ?" {
+			t.Fatalf("%s.Rebind changed the query: %q", d, got)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package drift
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+const (
+	KindMissing    = "missing"
+	KindUnexpected = "unexpected"
+	KindChanged    = "changed"
+)
+
+var (
+	intDisplayWidthRegex = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+	punctuationRegex     = regexp.MustCompile(`\s*([(),;=<>+*/-])\s*`)
+)
+
+var ignoredTables = map[string]bool{
+	"schema_migrations": true,
+}
+
+type SnapshotLoader interface {
+	GetSchemaSnapshot(ctx context.Context) (*models.SchemaSnapshot, error)
+}
+
+func Check(ctx context.Context, loader SnapshotLoader, database string) (models.DriftReport, error) {
+	expected, err := Expected()
+	if err != nil {
+		return models.DriftReport{}, err
+	}
+
+	actual, err := loader.GetSchemaSnapshot(ctx)
+	if err != nil {
+		return models.DriftReport{}, err
+	}
+
+	return Compare(database, expected, actual), nil
+}
+
+func Compare(database string, expected, actual *models.SchemaSnapshot) models.DriftReport {
+	differences := make([]models.DriftDifference, 0)
+
+	differences = append(differences, compareTables(expected.Tables, actual.Tables)...)
+	differences = append(differences, compareRoutines(expected.Routines, actual.Routines)...)
+	differences = append(differences, compareTriggers(expected.Triggers, actual.Triggers)...)
+
+	return models.DriftReport{
+		Database:    database,
+		InSync:      len(differences) == 0,
+		CheckedAt:   time.Now().UTC().Format(time.RFC3339),
+		Differences: differences,
+	}
+}
+
+func compareTables(expected, actual []models.SnapshotTable) []models.DriftDifference {
+	differences := make([]models.DriftDifference, 0)
+
+	actualByName := make(map[string]models.SnapshotTable, len(actual))
+	for _, table := range actual {
+		actualByName[strings.ToLower(table.Name)] = table
+	}
+
+	for _, table := range expected {
+		live, ok := actualByName[strings.ToLower(table.Name)]
+		if !ok {
+			differences = append(differences, models.DriftDifference{ObjectType: "table", Name: table.Name, Kind: KindMissing})
+			continue
+		}
+		delete(actualByName, strings.ToLower(table.Name))
+		differences = append(differences, compareColumns(table, live)...)
+	}
+
+	for _, name := range sortedKeys(actualByName) {
+		if ignoredTables[name] {
+			continue
+		}
+		differences = append(differences, models.DriftDifference{ObjectType: "table", Name: actualByName[name].Name, Kind: KindUnexpected})
+	}
+
+	return differences
+}
+
+func compareColumns(expected, actual models.SnapshotTable) []models.DriftDifference {
+	differences := make([]models.DriftDifference, 0)
+
+	actualByName := make(map[string]models.SnapshotColumn, len(actual.Columns))
+	for _, column := range actual.Columns {
+		actualByName[strings.ToLower(column.Name)] = column
+	}
+
+	for _, column := range expected.Columns {
+		name := expected.Name + "." + column.Name
+		live, ok := actualByName[strings.ToLower(column.Name)]
+		if !ok {
+			differences = append(differences, models.DriftDifference{ObjectType: "column", Name: name, Kind: KindMissing})
+			continue
+		}
+		delete(actualByName, strings.ToLower(column.Name))
+
+		if expectedType, liveType := NormalizeColumnType(column.Type), NormalizeColumnType(live.Type); expectedType != liveType {
+			differences = append(differences, models.DriftDifference{
+				ObjectType: "column", Name: name, Kind: KindChanged, Field: "type", Expected: expectedType, Actual: liveType,
+			})
+		}
+
+		if column.Nullable != live.Nullable {
+			differences = append(differences, models.DriftDifference{
+				ObjectType: "column", Name: name, Kind: KindChanged, Field: "nullable",
+				Expected: nullability(column.Nullable), Actual: nullability(live.Nullable),
+			})
+		}
+	}
+
+	for _, key := range sortedKeys(actualByName) {
+		differences = append(differences, models.DriftDifference{
+			ObjectType: "column", Name: expected.Name + "." + actualByName[key].Name, Kind: KindUnexpected,
+		})
+	}
+
+	return differences
+}
+
+func compareRoutines(expected, actual []models.SnapshotRoutine) []models.DriftDifference {
+	differences := make([]models.DriftDifference, 0)
+
+	actualByKey := make(map[string]models.SnapshotRoutine, len(actual))
+	for _, routine := range actual {
+		actualByKey[routineKey(routine)] = routine
+	}
+
+	for _, routine := range expected {
+		objectType := strings.ToLower(routine.Type)
+		live, ok := actualByKey[routineKey(routine)]
+		if !ok {
+			differences = append(differences, models.DriftDifference{ObjectType: objectType, Name: routine.Name, Kind: KindMissing})
+			continue
+		}
+		delete(actualByKey, routineKey(routine))
+
+		if expectedBody, liveBody := NormalizeBody(routine.Body), NormalizeBody(live.Body); expectedBody != liveBody {
+			differences = append(differences, models.DriftDifference{
+				ObjectType: objectType, Name: routine.Name, Kind: KindChanged, Field: "body", Expected: expectedBody, Actual: liveBody,
+			})
+		}
+	}
+
+	for _, key := range sortedKeys(actualByKey) {
+		routine := actualByKey[key]
+		differences = append(differences, models.DriftDifference{ObjectType: strings.ToLower(routine.Type), Name: routine.Name, Kind: KindUnexpected})
+	}
+
+	return differences
+}
+
+func compareTriggers(expected, actual []models.SnapshotTrigger) []models.DriftDifference {
+	differences := make([]models.DriftDifference, 0)
+
+	actualByName := make(map[string]models.SnapshotTrigger, len(actual))
+	for _, trigger := range actual {
+		actualByName[strings.ToLower(trigger.Name)] = trigger
+	}
+
+	for _, trigger := range expected {
+		live, ok := actualByName[strings.ToLower(trigger.Name)]
+		if !ok {
+			differences = append(differences, models.DriftDifference{ObjectType: "trigger", Name: trigger.Name, Kind: KindMissing})
+			continue
+		}
+		delete(actualByName, strings.ToLower(trigger.Name))
+
+		fields := []struct {
+			name     string
+			expected string
+			actual   string
+		}{
+			{"table", strings.ToLower(trigger.Table), strings.ToLower(live.Table)},
+			{"event", strings.ToUpper(trigger.Event), strings.ToUpper(live.Event)},
+			{"timing", strings.ToUpper(trigger.Timing), strings.ToUpper(live.Timing)},
+			{"body", NormalizeBody(trigger.Body), NormalizeBody(live.Body)},
+		}
+
+		for _, field := range fields {
+			if field.expected != field.actual {
+				differences = append(differences, models.DriftDifference{
+					ObjectType: "trigger", Name: trigger.Name, Kind: KindChanged, Field: field.name, Expected: field.expected, Actual: field.actual,
+				})
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(actualByName) {
+		differences = append(differences, models.DriftDifference{ObjectType: "trigger", Name: actualByName[name].Name, Kind: KindUnexpected})
+	}
+
+	return differences
+}
+
+func NormalizeColumnType(value string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	normalized = strings.ReplaceAll(normalized, ", ", ",")
+	normalized = strings.ReplaceAll(normalized, " (", "(")
+
+	switch {
+	case normalized == "boolean" || normalized == "bool":
+		return "tinyint(1)"
+	case strings.HasPrefix(normalized, "integer"):
+		normalized = "int" + strings.TrimPrefix(normalized, "integer")
+	}
+
+	if normalized != "tinyint(1)" {
+		normalized = intDisplayWidthRegex.ReplaceAllString(normalized, "$1")
+	}
+
+	return normalized
+}
+
+// NormalizeBody strips comments, backticks, keyword case and redundant
+// whitespace so that bodies compare equal regardless of formatting.
+func NormalizeBody(body string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(body) {
+				if body[end] == '\\' {
+					end += 2
+					continue
+				}
+				if body[end] == c {
+					if end+1 < len(body) && body[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if end >= len(body) {
+				end = len(body) - 1
+			}
+			b.WriteString(body[i : end+1])
+			i = end
+		case c == '`':
+			continue
+		case c == '#' || (c == '-' && strings.HasPrefix(body[i:], "-- ")):
+			for i < len(body) && body[i] != '\n' {
+				i++
+			}
+			b.WriteByte(' ')
+		case c == '/' && strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end < 0 {
+				i = len(body)
+			} else {
+				i += end + 3
+			}
+			b.WriteByte(' ')
+		default:
+			b.WriteString(strings.ToLower(string(c)))
+		}
+	}
+
+	normalized := strings.Join(strings.Fields(b.String()), " ")
+	normalized = punctuationRegex.ReplaceAllString(normalized, "$1")
+	return strings.TrimRight(normalized, "; ")
+}
+
+func routineKey(routine models.SnapshotRoutine) string {
+	return strings.ToUpper(routine.Type) + ":" + strings.ToLower(routine.Name)
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/migrate"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+func TestExpectedFromEmbeddedMigrations(t *testing.T) {
+	expected, err := Expected()
+	if err != nil {
+		t.Fatalf("Expected returned error: %v", err)
+	}
+
+	tables := make(map[string]models.SnapshotTable)
+	for _, table := range expected.Tables {
+		tables[table.Name] = table
+	}
+
+	for _, name := range []string{"users", "student", "staff", "book", "borrow", "genre_count"} {
+		if _, ok := tables[name]; !ok {
+			t.Fatalf("expected table %s in %+v", name, expected.Tables)
+		}
+	}
+
+	var emailColumn models.SnapshotColumn
+	for _, column := range tables["users"].Columns {
+		if column.Name == "email" {
+			emailColumn = column
+		}
+	}
+	if emailColumn.Type != "varchar(100)" || emailColumn.Nullable {
+		t.Fatalf("unexpected users.email column: %+v", emailColumn)
+	}
+
+	var found bool
+	for _, trigger := range expected.Triggers {
+		if trigger.Name == "before_borrow_limit" {
+			found = true
+			if trigger.Table != "borrow" || trigger.Event != "INSERT" || trigger.Timing != "BEFORE" {
+				t.Fatalf("unexpected trigger metadata: %+v", trigger)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected before_borrow_limit trigger")
+	}
+}
+
+func TestExpectedFromAppliesAlterTable(t *testing.T) {
+	expected := ExpectedFrom([]migrate.Migration{
+		{Version: 1, Up: "CREATE TABLE t (id INT PRIMARY KEY, name VARCHAR(10), old INT);"},
+		{Version: 2, Up: "ALTER TABLE t MODIFY COLUMN name VARCHAR(50) NOT NULL, DROP COLUMN old, ADD COLUMN created_at TIMESTAMP, ADD INDEX idx_name (name);"},
+	})
+
+	if len(expected.Tables) != 1 {
+		t.Fatalf("expected one table, got %+v", expected.Tables)
+	}
+
+	columns := expected.Tables[0].Columns
+	if len(columns) != 3 {
+		t.Fatalf("expected 3 columns, got %+v", columns)
+	}
+
+	if columns[1].Type != "varchar(50)" || columns[1].Nullable {
+		t.Fatalf("expected modified name column, got %+v", columns[1])
+	}
+
+	if columns[2].Name != "created_at" {
+		t.Fatalf("expected added created_at column, got %+v", columns[2])
+	}
+}
+
+func TestRoutineBodySkipsCharacteristics(t *testing.T) {
+	got := routineBody("(due_date DATE)\nRETURNS INT\nDETERMINISTIC\nBEGIN\n    RETURN 1;\nEND")
+	if got != "BEGIN\n    RETURN 1;\nEND" {
+		t.Fatalf("unexpected body %q", got)
+	}
+}
+
+func TestNormalizeBody(t *testing.T) {
+	a := "BEGIN\n    -- comment\n    UPDATE book\n    SET Status = 'Issued'\n    WHERE Book_ID = NEW.Book_ID;\nEND"
+	b := "begin update `book` set status='Issued' where book_id=new.book_id; end"
+
+	if NormalizeBody(a) != NormalizeBody(b) {
+		t.Fatalf("expected equal bodies:\n%s\n%s", NormalizeBody(a), NormalizeBody(b))
+	}
+
+	if NormalizeBody("SET Status = 'Issued'") == NormalizeBody("SET Status = 'ISSUED'") {
+		t.Fatal("expected string literals to stay case sensitive")
+	}
+}
+
+func TestNormalizeColumnType(t *testing.T) {
+	tests := map[string]string{
+		"INT":                     "int",
+		"int(11)":                 "int",
+		"BOOLEAN":                 "tinyint(1)",
+		"ENUM('admin', 'viewer')": "enum('admin','viewer')",
+		"VARCHAR(50)":             "varchar(50)",
+		"bigint(20) unsigned":     "bigint unsigned",
+	}
+
+	for input, expect := range tests {
+		if got := NormalizeColumnType(input); got != expect {
+			t.Fatalf("NormalizeColumnType(%q) = %q, expected %q", input, got, expect)
+		}
+	}
+}
+
+func TestCompareReportsDifferences(t *testing.T) {
+	expected := &models.SchemaSnapshot{
+		Tables: []models.SnapshotTable{
+			{Name: "book", Columns: []models.SnapshotColumn{{Name: "Book_ID", Type: "int"}, {Name: "Title", Type: "varchar(100)", Nullable: true}}},
+		},
+		Routines: []models.SnapshotRoutine{{Name: "overdueby", Type: "FUNCTION", Body: "BEGIN RETURN 1; END"}},
+		Triggers: []models.SnapshotTrigger{{Name: "after_borrow_insert", Table: "borrow", Event: "INSERT", Timing: "AFTER", Body: "BEGIN END"}},
+	}
+
+	actual := &models.SchemaSnapshot{
+		Tables: []models.SnapshotTable{
+			{Name: "book", Columns: []models.SnapshotColumn{{Name: "Book_ID", Type: "int"}, {Name: "Title", Type: "varchar(255)", Nullable: true}}},
+			{Name: "schema_migrations"},
+			{Name: "scratch"},
+		},
+		Routines: []models.SnapshotRoutine{{Name: "overdueby", Type: "FUNCTION", Body: "BEGIN RETURN 2; END"}},
+	}
+
+	report := Compare("library", expected, actual)
+	if report.InSync {
+		t.Fatal("expected drift to be reported")
+	}
+
+	kinds := make(map[string]string)
+	for _, diff := range report.Differences {
+		kinds[diff.ObjectType+":"+diff.Name] = diff.Kind
+	}
+
+	expectKinds := map[string]string{
+		"column:book.Title":           KindChanged,
+		"table:scratch":               KindUnexpected,
+		"function:overdueby":          KindChanged,
+		"trigger:after_borrow_insert": KindMissing,
+	}
+
+	for key, kind := range expectKinds {
+		if kinds[key] != kind {
+			t.Fatalf("expected %s to be %s, got %+v", key, kind, report.Differences)
+		}
+	}
+
+	if _, ok := kinds["table:schema_migrations"]; ok {
+		t.Fatal("schema_migrations should be ignored")
+	}
+}
@@ -0,0 +1,387 @@
+package drift
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/migrate"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+var (
+	createTableRegex   = regexp.MustCompile(`(?is)^CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + "`?" + `(\w+)` + "`?" + `\s*\((.*)\)[^)]*$`)
+	dropTableRegex     = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(.+)$`)
+	alterTableRegex    = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+` + "`?" + `(\w+)` + "`?" + `\s+(.*)$`)
+	renameTableRegex   = regexp.MustCompile(`(?is)^RENAME\s+TABLE\s+` + "`?" + `(\w+)` + "`?" + `\s+TO\s+` + "`?" + `(\w+)` + "`?" + `$`)
+	createRoutineRegex = regexp.MustCompile(`(?is)^CREATE\s+(?:DEFINER\s*=\s*\S+\s+)?(FUNCTION|PROCEDURE)\s+` + "`?" + `(\w+)` + "`?" + `\s*\(`)
+	dropRoutineRegex   = regexp.MustCompile(`(?is)^DROP\s+(FUNCTION|PROCEDURE|TRIGGER)\s+(?:IF\s+EXISTS\s+)?` + "`?" + `(\w+)` + "`?")
+	createTriggerRegex = regexp.MustCompile(`(?is)^CREATE\s+(?:DEFINER\s*=\s*\S+\s+)?TRIGGER\s+` + "`?" + `(\w+)` + "`?" + `\s+(BEFORE|AFTER)\s+(INSERT|UPDATE|DELETE)\s+ON\s+` + "`?" + `(\w+)` + "`?" + `\s+FOR\s+EACH\s+ROW\s+(.*)$`)
+	columnTypeRegex    = regexp.MustCompile(`(?is)^(\w+)(\s*\([^)]*\))?(\s+unsigned)?(\s+zerofill)?`)
+	characteristicRe   = regexp.MustCompile(`(?is)^(?:RETURNS\s+\w+(?:\s*\([^)]*\))?(?:\s+CHARSET\s+\w+)?|NOT\s+DETERMINISTIC|DETERMINISTIC|NO\s+SQL|READS\s+SQL\s+DATA|MODIFIES\s+SQL\s+DATA|CONTAINS\s+SQL|SQL\s+SECURITY\s+(?:DEFINER|INVOKER)|LANGUAGE\s+SQL|COMMENT\s+'(?:[^'\\]|\\.|'')*')\s*`)
+)
+
+var constraintKeywords = map[string]bool{
+	"CONSTRAINT": true,
+	"PRIMARY":    true,
+	"FOREIGN":    true,
+	"UNIQUE":     true,
+	"KEY":        true,
+	"INDEX":      true,
+	"FULLTEXT":   true,
+	"SPATIAL":    true,
+	"CHECK":      true,
+}
+
+type expectedTable struct {
+	name    string
+	columns []models.SnapshotColumn
+}
+
+type expectedState struct {
+	tables   map[string]*expectedTable
+	routines map[string]models.SnapshotRoutine
+	triggers map[string]models.SnapshotTrigger
+}
+
+// Expected replays the embedded migrations and returns the schema they are
+// expected to produce.
+func Expected() (*models.SchemaSnapshot, error) {
+	migrations, err := migrate.Embedded()
+	if err != nil {
+		return nil, err
+	}
+	return ExpectedFrom(migrations), nil
+}
+
+func ExpectedFrom(migrations []migrate.Migration) *models.SchemaSnapshot {
+	state := &expectedState{
+		tables:   make(map[string]*expectedTable),
+		routines: make(map[string]models.SnapshotRoutine),
+		triggers: make(map[string]models.SnapshotTrigger),
+	}
+
+	for _, migration := range migrations {
+		for _, statement := range migrate.SplitStatements(migration.Up) {
+			state.apply(statement)
+		}
+	}
+
+	return state.snapshot()
+}
+
+func (s *expectedState) apply(statement string) {
+	if matches := createTableRegex.FindStringSubmatch(statement); matches != nil {
+		name := strings.ToLower(matches[1])
+		if _, exists := s.tables[name]; exists && containsFold(statement, "IF NOT EXISTS") {
+			return
+		}
+		s.tables[name] = parseTableBody(matches[1], matches[2])
+		return
+	}
+
+	if matches := dropTableRegex.FindStringSubmatch(statement); matches != nil {
+		for _, name := range strings.Split(matches[1], ",") {
+			delete(s.tables, strings.ToLower(trimIdentifier(name)))
+		}
+		return
+	}
+
+	if matches := renameTableRegex.FindStringSubmatch(statement); matches != nil {
+		s.renameTable(matches[1], matches[2])
+		return
+	}
+
+	if matches := alterTableRegex.FindStringSubmatch(statement); matches != nil {
+		s.alterTable(matches[1], matches[2])
+		return
+	}
+
+	if matches := dropRoutineRegex.FindStringSubmatch(statement); matches != nil {
+		kind := strings.ToUpper(matches[1])
+		if kind == "TRIGGER" {
+			delete(s.triggers, strings.ToLower(matches[2]))
+		} else {
+			delete(s.routines, kind+":"+strings.ToLower(matches[2]))
+		}
+		return
+	}
+
+	if matches := createTriggerRegex.FindStringSubmatch(statement); matches != nil {
+		s.triggers[strings.ToLower(matches[1])] = models.SnapshotTrigger{
+			Name:   matches[1],
+			Timing: strings.ToUpper(matches[2]),
+			Event:  strings.ToUpper(matches[3]),
+			Table:  matches[4],
+			Body:   strings.TrimSpace(matches[5]),
+		}
+		return
+	}
+
+	if loc := createRoutineRegex.FindStringSubmatchIndex(statement); loc != nil {
+		kind := strings.ToUpper(statement[loc[2]:loc[3]])
+		name := statement[loc[4]:loc[5]]
+		s.routines[kind+":"+strings.ToLower(name)] = models.SnapshotRoutine{
+			Name: name,
+			Type: kind,
+			Body: routineBody(statement[loc[1]-1:]),
+		}
+	}
+}
+
+func (s *expectedState) renameTable(from, to string) {
+	table, ok := s.tables[strings.ToLower(from)]
+	if !ok {
+		return
+	}
+	delete(s.tables, strings.ToLower(from))
+	table.name = to
+	s.tables[strings.ToLower(to)] = table
+}
+
+func (s *expectedState) alterTable(name, specs string) {
+	table, ok := s.tables[strings.ToLower(name)]
+	if !ok {
+		return
+	}
+
+	for _, spec := range splitTopLevel(specs) {
+		words := strings.Fields(spec)
+		if len(words) < 2 {
+			continue
+		}
+
+		action := strings.ToUpper(words[0])
+		rest := strings.TrimSpace(spec[len(words[0]):])
+		if strings.EqualFold(words[1], "COLUMN") {
+			rest = strings.TrimSpace(rest[len(words[1]):])
+		}
+
+		switch action {
+		case "ADD":
+			if constraintKeywords[strings.ToUpper(words[1])] {
+				table.applyConstraint(rest)
+				continue
+			}
+			if column, ok := parseColumn(rest); ok {
+				table.setColumn(column.Name, column)
+			}
+		case "MODIFY":
+			if column, ok := parseColumn(rest); ok {
+				table.setColumn(column.Name, column)
+			}
+		case "CHANGE":
+			fields := strings.Fields(rest)
+			if len(fields) < 2 {
+				continue
+			}
+			if column, ok := parseColumn(strings.TrimSpace(rest[len(fields[0]):])); ok {
+				table.setColumn(trimIdentifier(fields[0]), column)
+			}
+		case "DROP":
+			if constraintKeywords[strings.ToUpper(words[1])] {
+				continue
+			}
+			table.dropColumn(trimIdentifier(strings.Fields(rest)[0]))
+		case "RENAME":
+			fields := strings.Fields(rest)
+			switch {
+			case strings.EqualFold(words[1], "COLUMN") && len(fields) == 3:
+				for i := range table.columns {
+					if strings.EqualFold(table.columns[i].Name, trimIdentifier(fields[0])) {
+						table.columns[i].Name = trimIdentifier(fields[2])
+					}
+				}
+			case len(fields) >= 1 && !strings.EqualFold(words[1], "INDEX") && !strings.EqualFold(words[1], "KEY"):
+				s.renameTable(name, trimIdentifier(fields[len(fields)-1]))
+				table = s.tables[strings.ToLower(trimIdentifier(fields[len(fields)-1]))]
+			}
+		}
+	}
+}
+
+func (t *expectedTable) setColumn(existing string, column models.SnapshotColumn) {
+	for i := range t.columns {
+		if strings.EqualFold(t.columns[i].Name, existing) {
+			t.columns[i] = column
+			return
+		}
+	}
+	t.columns = append(t.columns, column)
+}
+
+func (t *expectedTable) dropColumn(name string) {
+	for i := range t.columns {
+		if strings.EqualFold(t.columns[i].Name, name) {
+			t.columns = append(t.columns[:i], t.columns[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *expectedTable) applyConstraint(definition string) {
+	upper := strings.ToUpper(definition)
+	idx := strings.Index(upper, "PRIMARY KEY")
+	if idx < 0 {
+		return
+	}
+
+	open := strings.IndexByte(definition[idx:], '(')
+	closing := strings.IndexByte(definition[idx:], ')')
+	if open < 0 || closing < open {
+		return
+	}
+
+	for _, name := range strings.Split(definition[idx+open+1:idx+closing], ",") {
+		name = trimIdentifier(name)
+		for i := range t.columns {
+			if strings.EqualFold(t.columns[i].Name, name) {
+				t.columns[i].Nullable = false
+			}
+		}
+	}
+}
+
+func (s *expectedState) snapshot() *models.SchemaSnapshot {
+	snapshot := &models.SchemaSnapshot{
+		Tables:   make([]models.SnapshotTable, 0, len(s.tables)),
+		Routines: make([]models.SnapshotRoutine, 0, len(s.routines)),
+		Triggers: make([]models.SnapshotTrigger, 0, len(s.triggers)),
+	}
+
+	for _, table := range s.tables {
+		snapshot.Tables = append(snapshot.Tables, models.SnapshotTable{Name: table.name, Columns: table.columns})
+	}
+	for _, routine := range s.routines {
+		snapshot.Routines = append(snapshot.Routines, routine)
+	}
+	for _, trigger := range s.triggers {
+		snapshot.Triggers = append(snapshot.Triggers, trigger)
+	}
+
+	sort.Slice(snapshot.Tables, func(i, j int) bool { return snapshot.Tables[i].Name < snapshot.Tables[j].Name })
+	sort.Slice(snapshot.Routines, func(i, j int) bool { return snapshot.Routines[i].Name < snapshot.Routines[j].Name })
+	sort.Slice(snapshot.Triggers, func(i, j int) bool { return snapshot.Triggers[i].Name < snapshot.Triggers[j].Name })
+
+	return snapshot
+}
+
+func parseTableBody(name, body string) *expectedTable {
+	table := &expectedTable{name: name}
+	constraints := make([]string, 0)
+
+	for _, definition := range splitTopLevel(body) {
+		first := strings.ToUpper(strings.Fields(definition)[0])
+		if constraintKeywords[first] {
+			constraints = append(constraints, definition)
+			continue
+		}
+		if column, ok := parseColumn(definition); ok {
+			table.columns = append(table.columns, column)
+		}
+	}
+
+	for _, constraint := range constraints {
+		table.applyConstraint(constraint)
+	}
+
+	return table
+}
+
+func parseColumn(definition string) (models.SnapshotColumn, bool) {
+	fields := strings.Fields(definition)
+	if len(fields) < 2 {
+		return models.SnapshotColumn{}, false
+	}
+
+	rest := strings.TrimSpace(definition[len(fields[0]):])
+	typeMatch := columnTypeRegex.FindString(rest)
+	if typeMatch == "" {
+		return models.SnapshotColumn{}, false
+	}
+
+	attributes := strings.ToUpper(rest[len(typeMatch):])
+
+	return models.SnapshotColumn{
+		Name:     trimIdentifier(fields[0]),
+		Type:     NormalizeColumnType(typeMatch),
+		Nullable: !strings.Contains(attributes, "NOT NULL") && !strings.Contains(attributes, "PRIMARY KEY"),
+	}, true
+}
+
+func routineBody(fromParams string) string {
+	depth := 0
+	end := -1
+	for i := 0; i < len(fromParams); i++ {
+		switch fromParams[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	if end < 0 {
+		return strings.TrimSpace(fromParams)
+	}
+
+	rest := strings.TrimSpace(fromParams[end+1:])
+	for {
+		loc := characteristicRe.FindStringIndex(rest)
+		if loc == nil || loc[1] == 0 {
+			break
+		}
+		rest = rest[loc[1]:]
+	}
+
+	return strings.TrimSpace(rest)
+}
+
+func splitTopLevel(value string) []string {
+	parts := make([]string, 0)
+	depth := 0
+	start := 0
+	var quote byte
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			if part := strings.TrimSpace(value[start:i]); part != "" {
+				parts = append(parts, part)
+			}
+			start = i + 1
+		}
+	}
+
+	if part := strings.TrimSpace(value[start:]); part != "" {
+		parts = append(parts, part)
+	}
+
+	return parts
+}
+
+func trimIdentifier(value string) string {
+	return strings.Trim(strings.TrimSpace(value), "`")
+}
+
+func containsFold(value, substr string) bool {
+	return strings.Contains(strings.ToUpper(value), strings.ToUpper(substr))
+}
@@ -2,10 +2,8 @@ package handlers
 
 import (
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"net/http"
-	"net/mail"
 	"strconv"
 	"strings"
 	"time"
@@ -13,7 +11,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/karanm6505/dbms/server/internal/metrics"
 	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
 	"github.com/karanm6505/dbms/server/internal/repository"
 )
 
@@ -23,19 +23,23 @@ type authResponse struct {
 }
 
 type userResponse struct {
-	ID    int64       `json:"id"`
-	Email string      `json:"email"`
-	Role  models.Role `json:"role"`
+	ID        int64       `json:"id"`
+	Email     string      `json:"email"`
+	Role      models.Role `json:"role"`
+	StudentID *int        `json:"student_id,omitempty"`
 }
 
+// Passwords are hashed exactly as typed, so they are never trimmed.
 type loginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"notrim,required"`
 }
 
+// bcrypt refuses passwords over 72 bytes, so they are rejected as invalid
+// rather than failing to hash.
 type registerRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,max=100,email"`
+	Password string `json:"password" validate:"notrim,required,min=8,maxbytes=72"`
 }
 
 type userClaims struct {
@@ -46,41 +50,38 @@ type userClaims struct {
 
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req loginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid JSON body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	email := normalizeEmail(req.Email)
-	password := strings.TrimSpace(req.Password)
-
-	if email == "" || password == "" {
-		writeError(w, http.StatusBadRequest, "email and password are required")
-		return
-	}
+	password := req.Password
 
 	user, err := h.UserRepo.GetByEmail(r.Context(), email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			metrics.AuthAttempt("login", false)
 			writeError(w, http.StatusUnauthorized, "invalid credentials")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "failed to authenticate")
+		writeServerError(w, r, "failed to authenticate", err)
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		metrics.AuthAttempt("login", false)
 		writeError(w, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
 
 	token, err := h.generateToken(user)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to generate token")
+		writeServerError(w, r, "failed to generate token", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, authResponse{
+	metrics.AuthAttempt("login", true)
+	writeJSON(w, r, http.StatusOK, authResponse{
 		Token: token,
 		User:  newUserResponse(user),
 	})
@@ -88,57 +89,45 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var req registerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid JSON body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	email := normalizeEmail(req.Email)
-	password := strings.TrimSpace(req.Password)
-
-	if email == "" || password == "" {
-		writeError(w, http.StatusBadRequest, "email and password are required")
-		return
-	}
-
-	if _, err := mail.ParseAddress(email); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid email address")
-		return
-	}
-
-	if len(password) < 8 {
-		writeError(w, http.StatusBadRequest, "password must be at least 8 characters")
-		return
-	}
+	password := req.Password
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to secure password")
+		writeServerError(w, r, "failed to secure password", err)
 		return
 	}
 
+	// Anyone can register, so new accounts are patrons and see nothing
+	// beyond their own student once it is linked.
 	user := &models.User{
 		Email:        email,
 		PasswordHash: string(hash),
-		Role:         models.RoleViewer,
+		Role:         models.RolePatron,
 	}
 
 	if err := h.UserRepo.Create(r.Context(), user); err != nil {
 		if errors.Is(err, repository.ErrUserAlreadyExists) {
-			writeError(w, http.StatusConflict, "an account with that email already exists")
+			metrics.AuthAttempt("register", false)
+			writeProblem(w, r, problem.New(http.StatusConflict, problem.CodeAlreadyExists, "an account with that email already exists"))
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "failed to create user")
+		writeRepositoryError(w, r, "failed to create user", err)
 		return
 	}
 
 	token, err := h.generateToken(user)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to generate token")
+		writeServerError(w, r, "failed to generate token", err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, authResponse{
+	metrics.AuthAttempt("register", true)
+	writeJSON(w, r, http.StatusCreated, authResponse{
 		Token: token,
 		User:  newUserResponse(user),
 	})
@@ -151,7 +140,7 @@ func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, newUserResponse(user))
+	writeJSON(w, r, http.StatusOK, newUserResponse(user))
 }
 
 func (h *Handler) generateToken(user *models.User) (string, error) {
@@ -179,9 +168,10 @@ func (h *Handler) generateToken(user *models.User) (string, error) {
 
 func newUserResponse(user *models.User) userResponse {
 	return userResponse{
-		ID:    user.ID,
-		Email: user.Email,
-		Role:  user.Role,
+		ID:        user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		StudentID: user.StudentID,
 	}
 }
 
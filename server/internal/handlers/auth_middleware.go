@@ -10,6 +10,8 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/metrics"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
@@ -21,19 +23,19 @@ func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
 		if authHeader == "" {
-			writeError(w, http.StatusUnauthorized, "missing authorization header")
+			rejectToken(w, "missing authorization header")
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-			writeError(w, http.StatusUnauthorized, "invalid authorization header")
+			rejectToken(w, "invalid authorization header")
 			return
 		}
 
 		tokenString := strings.TrimSpace(parts[1])
 		if tokenString == "" {
-			writeError(w, http.StatusUnauthorized, "invalid authorization header")
+			rejectToken(w, "invalid authorization header")
 			return
 		}
 
@@ -46,35 +48,42 @@ func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
 		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
-				writeError(w, http.StatusUnauthorized, "token expired")
+				rejectToken(w, "token expired")
 			} else {
-				writeError(w, http.StatusUnauthorized, "invalid token")
+				rejectToken(w, "invalid token")
 			}
 			return
 		}
 
 		if !token.Valid {
-			writeError(w, http.StatusUnauthorized, "invalid token")
+			rejectToken(w, "invalid token")
 			return
 		}
 
 		userID, err := strconv.ParseInt(claims.Subject, 10, 64)
 		if err != nil {
-			writeError(w, http.StatusUnauthorized, "invalid token subject")
+			rejectToken(w, "invalid token subject")
 			return
 		}
 
 		user, err := h.UserRepo.GetByID(r.Context(), userID)
 		if err != nil {
-			writeError(w, http.StatusUnauthorized, "user not found")
+			rejectToken(w, "user not found")
 			return
 		}
 
+		metrics.AuthAttempt("token", true)
+		logging.SetUser(r.Context(), user.ID)
 		ctx := context.WithValue(r.Context(), userContextKey, user)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+func rejectToken(w http.ResponseWriter, message string) {
+	metrics.AuthAttempt("token", false)
+	writeError(w, http.StatusUnauthorized, message)
+}
+
 func (h *Handler) currentUser(r *http.Request) (*models.User, bool) {
 	value := r.Context().Value(userContextKey)
 	if value == nil {
@@ -99,3 +108,29 @@ func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) (*models.
 
 	return user, true
 }
+
+// RequireStaff keeps patron accounts out of routes that read every student's
+// records. It runs after AuthMiddleware.
+func (h *Handler) RequireStaff(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := h.currentUser(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		if !user.IsStaff() {
+			writeError(w, http.StatusForbidden, "staff access required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// canReadStudent reports whether the user may see the student's records:
+// staff see everyone, a patron only the student it is linked to.
+func canReadStudent(user *models.User, studentID int) bool {
+	if user.IsStaff() {
+		return true
+	}
+	return user.StudentID != nil && *user.StudentID == studentID
+}
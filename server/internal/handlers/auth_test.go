@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postAuth(handler http.HandlerFunc, path, body string) *httptest.ResponseRecorder {
+	return serve(handler, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+}
+
+func TestRegisterRejectsPasswordOverBcryptLimit(t *testing.T) {
+	h := newMemoryHandler()
+
+	// 30 characters, but 90 bytes.
+	password := strings.Repeat("€", 30)
+	rec := postAuth(h.Register, "/api/auth/register", `{"email":"ada@example.com","password":"`+password+`"}`)
+
+	p := decodeProblem(t, rec)
+	if rec.Code != http.StatusBadRequest || len(p.Errors) != 1 || p.Errors[0].Field != "password" || p.Errors[0].Code != "max_bytes" {
+		t.Fatalf("expected a max_bytes error for password, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPasswordsAreNotTrimmed(t *testing.T) {
+	h := newMemoryHandler()
+
+	if rec := postAuth(h.Register, "/api/auth/register", `{"email":"ada@example.com","password":" correct horse "}`); rec.Code != http.StatusCreated {
+		t.Fatalf("expected registration to succeed, got %d: %s", rec.Code, rec.Body)
+	}
+
+	if rec := postAuth(h.Login, "/api/auth/login", `{"email":"ada@example.com","password":"correct horse"}`); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the trimmed password to be refused, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := postAuth(h.Login, "/api/auth/login", `{"email":"ada@example.com","password":" correct horse "}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected the password as typed to sign in, got %d: %s", rec.Code, rec.Body)
+	}
+}
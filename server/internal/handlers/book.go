@@ -5,19 +5,19 @@ import "net/http"
 func (h *Handler) GetBooks(w http.ResponseWriter, r *http.Request) {
 	books, err := h.BookRepo.GetAll(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch books")
+		writeServerError(w, r, "failed to fetch books", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, books)
+	writeJSON(w, r, http.StatusOK, books)
 }
 
 func (h *Handler) GetAvailableBooks(w http.ResponseWriter, r *http.Request) {
 	books, err := h.BookRepo.GetAvailable(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch available books")
+		writeServerError(w, r, "failed to fetch available books", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, books)
+	writeJSON(w, r, http.StatusOK, books)
 }
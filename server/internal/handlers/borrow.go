@@ -1,13 +1,107 @@
 package handlers
 
-import "net/http"
+import (
+	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+type checkoutRequest struct {
+	StudentID int    `json:"student_id" validate:"required,min=1"`
+	BookID    int    `json:"book_id" validate:"required,min=1"`
+	StaffID   int    `json:"staff_id" validate:"required,min=1"`
+	DueDate   string `json:"due_date" validate:"date"`
+}
+
+// GetBorrowRecords lists every loan for staff and only the linked
+// student's loans for a patron.
 func (h *Handler) GetBorrowRecords(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
 	records, err := h.BorrowRepo.GetAll(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch borrow records")
+		writeServerError(w, r, "failed to fetch borrow records", err)
+		return
+	}
+
+	if !user.IsStaff() {
+		visible := make([]models.BorrowRecord, 0)
+		for _, record := range records {
+			if canReadStudent(user, record.StudentID) {
+				visible = append(visible, record)
+			}
+		}
+		records = visible
+	}
+
+	writeJSON(w, r, http.StatusOK, records)
+}
+
+// CheckoutBook issues a book. The borrow limit and the book's availability
+// are enforced by the store, so both backends answer with the same problem.
+func (h *Handler) CheckoutBook(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req checkoutRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.DueDate != "" && req.DueDate < time.Now().Format(time.DateOnly) {
+		writeValidationError(w, r, []models.FieldError{{Field: "due_date", Code: "past", Message: "due_date must not be in the past"}})
+		return
+	}
+
+	loan := &models.BorrowRecord{
+		StudentID: req.StudentID,
+		BookID:    req.BookID,
+		StaffID:   req.StaffID,
+		DueDate:   req.DueDate,
+	}
+
+	if err := h.BorrowRepo.Checkout(r.Context(), loan); err != nil {
+		writeRepositoryError(w, r, "failed to check out book", err)
+		return
+	}
+
+	h.writeBorrowRecord(w, r, http.StatusCreated, loan.ID)
+}
+
+func (h *Handler) ReturnBook(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	borrowID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid borrow id")
+		return
+	}
+
+	if err := h.BorrowRepo.Return(r.Context(), borrowID); err != nil {
+		writeRepositoryError(w, r, "failed to return book", err)
+		return
+	}
+
+	h.writeBorrowRecord(w, r, http.StatusOK, borrowID)
+}
+
+func (h *Handler) writeBorrowRecord(w http.ResponseWriter, r *http.Request, status, id int) {
+	record, err := h.BorrowRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeServerError(w, r, "failed to fetch borrow record", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, records)
+	writeJSON(w, r, status, record)
 }
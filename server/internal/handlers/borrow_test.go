@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+	"github.com/karanm6505/dbms/server/internal/repository/memory"
+)
+
+func newMemoryHandler() *Handler {
+	store := memory.NewSeeded()
+	return New(
+		store.Health(), store.Health(),
+		store.Students(), store.Books(), store.Staff(), store.Borrows(), store.Stats(),
+		store.Metadata(), store.Users(), store.Console(), store.SavedQueries(),
+		config.AuthConfig{}, config.ConsoleConfig{}, LogVerificationSender{},
+	)
+}
+
+func asAdmin(r *http.Request) *http.Request {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	return r.WithContext(context.WithValue(r.Context(), userContextKey, admin))
+}
+
+func postCheckout(h *Handler, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.CheckoutBook(rec, asAdmin(httptest.NewRequest(http.MethodPost, "/api/borrows", strings.NewReader(body))))
+	return rec
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) problem.Problem {
+	t.Helper()
+
+	var p problem.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("invalid problem document %q: %v", rec.Body.String(), err)
+	}
+	return p
+}
+
+func TestCheckoutBookEnforcesBorrowLimit(t *testing.T) {
+	h := newMemoryHandler()
+
+	rec := postCheckout(h, `{"student_id":9,"book_id":1,"staff_id":1}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var record models.BorrowRecord
+	if err := json.Unmarshal(rec.Body.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.StudentName != "James White" || record.BookTitle != "Introduction to Algorithms" {
+		t.Fatalf("unexpected record %+v", record)
+	}
+
+	// Student 3 already holds three books in the sample data.
+	rec = postCheckout(h, `{"student_id":3,"book_id":3,"staff_id":1}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body)
+	}
+	if p := decodeProblem(t, rec); p.Code != problem.CodeBorrowLimitExceeded {
+		t.Fatalf("expected %s, got %s", problem.CodeBorrowLimitExceeded, p.Code)
+	}
+}
+
+func TestCheckoutBookRejectsIssuedBook(t *testing.T) {
+	h := newMemoryHandler()
+
+	rec := postCheckout(h, `{"student_id":9,"book_id":5,"staff_id":1}`)
+	if p := decodeProblem(t, rec); rec.Code != http.StatusConflict || p.Code != problem.CodeBookUnavailable {
+		t.Fatalf("expected 409 %s, got %d %s", problem.CodeBookUnavailable, rec.Code, p.Code)
+	}
+}
+
+func TestReturnBookMakesItAvailable(t *testing.T) {
+	h := newMemoryHandler()
+
+	// Loan 10 holds book 5 in the sample data.
+	returnLoan := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/borrows/10/return", nil)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", "10")
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+
+		rec := httptest.NewRecorder()
+		h.ReturnBook(rec, asAdmin(r))
+		return rec
+	}
+
+	if rec := returnLoan(); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := returnLoan(); rec.Code != http.StatusConflict {
+		t.Fatalf("expected second return to conflict, got %d", rec.Code)
+	}
+
+	if rec := postCheckout(h, `{"student_id":9,"book_id":5,"staff_id":1}`); rec.Code != http.StatusCreated {
+		t.Fatalf("expected returned book to be issued again, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+	"github.com/karanm6505/dbms/server/internal/repository"
+	"github.com/karanm6505/dbms/server/internal/sqlguard"
+)
+
+const flushEvery = 100
+
+type consoleRequest struct {
+	Query   string `json:"query" validate:"required"`
+	Format  string `json:"format"`
+	MaxRows int    `json:"max_rows"`
+}
+
+func (h *Handler) RunConsoleQuery(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req consoleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	format, err := resolveResultFormat(r, req.Format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	maxRows := h.consoleConfig.MaxRows
+	if req.MaxRows > 0 && (maxRows <= 0 || req.MaxRows < maxRows) {
+		maxRows = req.MaxRows
+	}
+
+	entry := &models.QueryLogEntry{
+		UserID:    user.ID,
+		UserEmail: user.Email,
+		Statement: req.Query,
+		Format:    format,
+	}
+	defer h.logConsoleQuery(r.Context(), entry)
+
+	statement, err := sqlguard.ParseReadOnly(req.Query)
+	if err != nil {
+		entry.Status = models.QueryStatusRejected
+		entry.ErrorMessage = err.Error()
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	started := time.Now()
+	results := newResultWriter(w, format, "query")
+
+	count, truncated, err := h.ConsoleRepo.QueryReadOnly(
+		r.Context(),
+		statement.Text,
+		nil,
+		maxRows,
+		h.consoleConfig.Timeout,
+		results.columns,
+		results.row,
+	)
+
+	entry.RowCount = count
+	entry.DurationMS = time.Since(started).Milliseconds()
+	entry.Status = models.QueryStatusOK
+
+	if err != nil {
+		entry.Status = models.QueryStatusError
+		entry.ErrorMessage = err.Error()
+
+		if !results.started() {
+			writeQueryError(w, r, err)
+			return
+		}
+	}
+
+	results.finish(count, truncated, time.Since(started), err)
+}
+
+func (h *Handler) logConsoleQuery(ctx context.Context, entry *models.QueryLogEntry) {
+	if err := h.ConsoleRepo.LogQuery(context.WithoutCancel(ctx), entry); err != nil {
+		logging.FromContext(ctx).Error("failed to record console query", "user_email", entry.UserEmail, "error", err)
+	}
+}
+
+// consoleQueryError reports a failed console or report query. The console is
+// admin-only, so the database's message is passed through to help fix the
+// statement.
+func consoleQueryError(err error) *problem.Problem {
+	if errors.Is(err, context.DeadlineExceeded) || repository.IsQueryTimeout(err) {
+		return problem.New(http.StatusGatewayTimeout, problem.CodeTimeout, "query timed out")
+	}
+	if errors.Is(err, errors.ErrUnsupported) {
+		return problem.New(http.StatusNotImplemented, problem.CodeNotSupported, "the configured database cannot run SQL queries")
+	}
+	if message, ok := repository.RejectedStatement(err); ok {
+		return problem.New(http.StatusBadRequest, problem.CodeQueryFailed, message)
+	}
+	return problem.New(http.StatusInternalServerError, problem.CodeInternal, "failed to run query")
+}
+
+func writeQueryError(w http.ResponseWriter, r *http.Request, err error) {
+	p := consoleQueryError(err)
+	logging.RecordError(r.Context(), p.Detail, err)
+	writeProblem(w, r, p)
+}
+
+func resolveResultFormat(r *http.Request, requested string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(requested))
+	if format == "" {
+		format = strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	}
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		format = "csv"
+	}
+	if format == "" {
+		format = "json"
+	}
+
+	if format != "json" && format != "csv" {
+		return "", errors.New("format must be json or csv")
+	}
+
+	return format, nil
+}
+
+type resultWriter interface {
+	columns(columns []string) error
+	row(values []any) error
+	finish(rowCount int, truncated bool, duration time.Duration, err error)
+	started() bool
+}
+
+func newResultWriter(w http.ResponseWriter, format, filename string) resultWriter {
+	if format == "csv" {
+		return &csvResultWriter{w: w, filename: filename}
+	}
+	return &jsonResultWriter{w: w}
+}
+
+type jsonResultWriter struct {
+	w       http.ResponseWriter
+	begun   bool
+	written int
+}
+
+func (j *jsonResultWriter) columns(columns []string) error {
+	encoded, err := json.Marshal(columns)
+	if err != nil {
+		return err
+	}
+
+	j.w.Header().Set("Content-Type", "application/json")
+	j.w.WriteHeader(http.StatusOK)
+	j.begun = true
+
+	_, err = fmt.Fprintf(j.w, `{"columns":%s,"rows":[`, encoded)
+	return err
+}
+
+func (j *jsonResultWriter) row(values []any) error {
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	if j.written > 0 {
+		if _, err := j.w.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+
+	if _, err := j.w.Write(encoded); err != nil {
+		return err
+	}
+
+	j.written++
+	if j.written%flushEvery == 0 {
+		flush(j.w)
+	}
+	return nil
+}
+
+func (j *jsonResultWriter) finish(rowCount int, truncated bool, duration time.Duration, err error) {
+	if !j.begun {
+		_ = j.columns([]string{})
+	}
+
+	trailer := map[string]any{
+		"row_count":   rowCount,
+		"truncated":   truncated,
+		"duration_ms": duration.Milliseconds(),
+	}
+	if err != nil {
+		trailer["error"] = err.Error()
+	}
+
+	encoded, _ := json.Marshal(trailer)
+	_, _ = fmt.Fprintf(j.w, "],%s\n", encoded[1:])
+}
+
+func (j *jsonResultWriter) started() bool {
+	return j.begun
+}
+
+type csvResultWriter struct {
+	w        http.ResponseWriter
+	csv      *csv.Writer
+	filename string
+	written  int
+}
+
+func (c *csvResultWriter) columns(columns []string) error {
+	header := c.w.Header()
+	header.Set("Content-Type", "text/csv; charset=utf-8")
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", c.filename+".csv"))
+	header.Set("Trailer", "X-Row-Count, X-Truncated, X-Query-Error")
+	c.w.WriteHeader(http.StatusOK)
+
+	c.csv = csv.NewWriter(c.w)
+	return c.csv.Write(columns)
+}
+
+func (c *csvResultWriter) row(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatCSVValue(value)
+	}
+
+	if err := c.csv.Write(record); err != nil {
+		return err
+	}
+
+	c.written++
+	if c.written%flushEvery == 0 {
+		c.csv.Flush()
+		flush(c.w)
+	}
+	return c.csv.Error()
+}
+
+func (c *csvResultWriter) finish(rowCount int, truncated bool, _ time.Duration, err error) {
+	if c.csv == nil {
+		_ = c.columns([]string{})
+	}
+
+	c.csv.Flush()
+
+	header := c.w.Header()
+	header.Set("X-Row-Count", strconv.Itoa(rowCount))
+	header.Set("X-Truncated", strconv.FormatBool(truncated))
+	if err != nil {
+		header.Set("X-Query-Error", err.Error())
+	}
+}
+
+func (c *csvResultWriter) started() bool {
+	return c.csv != nil
+}
+
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func flush(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/karanm6505/dbms/server/internal/problem"
+)
+
+func TestJSONResultWriterProducesValidDocument(t *testing.T) {
+	rec := httptest.NewRecorder()
+	results := newResultWriter(rec, "json", "query")
+
+	if err := results.columns([]string{"Book_ID", "Title"}); err != nil {
+		t.Fatalf("columns returned error: %v", err)
+	}
+	_ = results.row([]any{1, "Clean Code"})
+	_ = results.row([]any{2, nil})
+	results.finish(2, true, 5*time.Millisecond, errors.New("boom"))
+
+	var payload struct {
+		Columns   []string `json:"columns"`
+		Rows      [][]any  `json:"rows"`
+		RowCount  int      `json:"row_count"`
+		Truncated bool     `json:"truncated"`
+		Error     string   `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+
+	if len(payload.Rows) != 2 || payload.RowCount != 2 || !payload.Truncated || payload.Error != "boom" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestCSVResultWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	results := newResultWriter(rec, "csv", "query")
+
+	_ = results.columns([]string{"Title", "Due_Date"})
+	_ = results.row([]any{"Clean, Code", time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)})
+	results.finish(1, false, 0, nil)
+
+	expect := "Title,Due_Date\n\"Clean, Code\",2025-09-30T00:00:00Z\n"
+	if rec.Body.String() != expect {
+		t.Fatalf("expected %q, got %q", expect, rec.Body.String())
+	}
+}
+
+func TestConsoleQueryError(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	sqliteErr := func(query string) error {
+		t.Helper()
+		_, err := db.Exec(query)
+		if err == nil {
+			t.Fatalf("expected %q to fail", query)
+		}
+		return err
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   problem.Code
+	}{
+		{"mysql syntax", &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}, http.StatusBadRequest, problem.CodeQueryFailed},
+		{"mysql timeout", &mysql.MySQLError{Number: 3024, Message: "Query execution was interrupted"}, http.StatusGatewayTimeout, problem.CodeTimeout},
+		{"postgres unknown column", &pgconn.PgError{Code: "42703", Message: `column "nope" does not exist`}, http.StatusBadRequest, problem.CodeQueryFailed},
+		{"postgres permission", &pgconn.PgError{Code: "42501", Message: "permission denied for table users"}, http.StatusBadRequest, problem.CodeQueryFailed},
+		{"postgres timeout", &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}, http.StatusGatewayTimeout, problem.CodeTimeout},
+		{"postgres connection", &pgconn.PgError{Code: "08006", Message: "connection failure"}, http.StatusInternalServerError, problem.CodeInternal},
+		{"sqlite syntax", sqliteErr("SELEC 1"), http.StatusBadRequest, problem.CodeQueryFailed},
+		{"sqlite unknown table", sqliteErr("SELECT * FROM nope"), http.StatusBadRequest, problem.CodeQueryFailed},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, problem.CodeTimeout},
+		{"unknown", errors.New("connection reset"), http.StatusInternalServerError, problem.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := consoleQueryError(tt.err)
+			if p.Status != tt.status || p.Code != tt.code {
+				t.Fatalf("expected %d %s, got %d %s: %s", tt.status, tt.code, p.Status, p.Code, p.Detail)
+			}
+		})
+	}
+}
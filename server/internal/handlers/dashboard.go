@@ -5,9 +5,9 @@ import "net/http"
 func (h *Handler) GetDashboardStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.StatsRepo.GetDashboardStats(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch dashboard stats")
+		writeServerError(w, r, "failed to fetch dashboard stats", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, stats)
+	writeJSON(w, r, http.StatusOK, stats)
 }
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+func (h *Handler) GetSchemaDiagram(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "json"
+	}
+
+	if format != "json" && format != "mermaid" && format != "dot" {
+		writeError(w, http.StatusBadRequest, "format must be one of json, mermaid, dot")
+		return
+	}
+
+	diagram, err := h.MetadataRepo.GetSchemaDiagram(r.Context())
+	if err != nil {
+		writeRepositoryError(w, r, "failed to build schema diagram", err)
+		return
+	}
+
+	switch format {
+	case "mermaid":
+		writeText(w, http.StatusOK, "text/vnd.mermaid; charset=utf-8", renderMermaid(diagram))
+	case "dot":
+		writeText(w, http.StatusOK, "text/vnd.graphviz; charset=utf-8", renderDOT(diagram))
+	default:
+		writeJSON(w, r, http.StatusOK, diagram)
+	}
+}
+
+func renderMermaid(diagram *models.SchemaDiagram) string {
+	var b strings.Builder
+
+	b.WriteString("erDiagram\n")
+
+	for _, table := range diagram.Tables {
+		fmt.Fprintf(&b, "    %s {\n", table.Name)
+		for _, column := range table.Columns {
+			fmt.Fprintf(&b, "        %s %s", column.DataType, column.Name)
+			if keys := columnKeys(column); len(keys) > 0 {
+				fmt.Fprintf(&b, " %s", strings.Join(keys, ","))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, rel := range diagram.Relationships {
+		parent := "||"
+		if rel.Nullable {
+			parent = "|o"
+		}
+		fmt.Fprintf(&b, "    %s %s--o{ %s : %q\n", rel.ReferencedTable, parent, rel.Table, strings.Join(rel.Columns, ","))
+	}
+
+	return b.String()
+}
+
+func renderDOT(diagram *models.SchemaDiagram) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %q {\n", diagram.Database)
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=plaintext];\n")
+
+	for _, table := range diagram.Tables {
+		fmt.Fprintf(&b, "    %q [label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">", table.Name)
+		fmt.Fprintf(&b, "<TR><TD BGCOLOR=\"lightgrey\"><B>%s</B></TD></TR>", html.EscapeString(table.Name))
+		for _, column := range table.Columns {
+			label := column.Name + " : " + column.ColumnType
+			if keys := columnKeys(column); len(keys) > 0 {
+				label += " (" + strings.Join(keys, ",") + ")"
+			}
+			fmt.Fprintf(&b, "<TR><TD PORT=%q ALIGN=\"LEFT\">%s</TD></TR>", column.Name, html.EscapeString(label))
+		}
+		b.WriteString("</TABLE>>];\n")
+	}
+
+	for _, rel := range diagram.Relationships {
+		fmt.Fprintf(&b, "    %q:%q -> %q:%q [label=%q];\n",
+			rel.Table, rel.Columns[0], rel.ReferencedTable, rel.ReferencedColumns[0], rel.Name)
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func columnKeys(column models.DiagramColumn) []string {
+	keys := make([]string, 0, 2)
+	if column.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if column.ForeignKey {
+		keys = append(keys, "FK")
+	}
+	return keys
+}
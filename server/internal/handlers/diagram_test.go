@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+func sampleDiagram() *models.SchemaDiagram {
+	return &models.SchemaDiagram{
+		Database: "Library_Management_System",
+		Tables: []models.DiagramTable{
+			{
+				Name: "borrow",
+				Columns: []models.DiagramColumn{
+					{Name: "Borrow_ID", DataType: "int", ColumnType: "int", PrimaryKey: true},
+					{Name: "Student_ID", DataType: "int", ColumnType: "int", Nullable: true, ForeignKey: true},
+				},
+			},
+			{
+				Name: "student",
+				Columns: []models.DiagramColumn{
+					{Name: "Student_ID", DataType: "int", ColumnType: "int", PrimaryKey: true},
+					{Name: "Email", DataType: "varchar", ColumnType: "varchar(100)", Nullable: true},
+				},
+			},
+		},
+		Relationships: []models.DiagramRelationship{
+			{
+				Name:              "borrow_ibfk_1",
+				Table:             "borrow",
+				Columns:           []string{"Student_ID"},
+				ReferencedTable:   "student",
+				ReferencedColumns: []string{"Student_ID"},
+				Nullable:          true,
+			},
+		},
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	got := renderMermaid(sampleDiagram())
+
+	expect := `erDiagram
+    borrow {
+        int Borrow_ID PK
+        int Student_ID FK
+    }
+    student {
+        int Student_ID PK
+        varchar Email
+    }
+    student |o--o{ borrow : "Student_ID"
+`
+
+	if got != expect {
+		t.Fatalf("unexpected mermaid output:\n%s", got)
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	got := renderDOT(sampleDiagram())
+
+	if !strings.HasPrefix(got, `digraph "Library_Management_System" {`) {
+		t.Fatalf("expected digraph header, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, `"borrow":"Student_ID" -> "student":"Student_ID" [label="borrow_ibfk_1"];`) {
+		t.Fatalf("expected foreign key edge, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, `Email : varchar(100)`) {
+		t.Fatalf("expected column type in label, got:\n%s", got)
+	}
+}
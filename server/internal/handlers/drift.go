@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/karanm6505/dbms/server/internal/drift"
+)
+
+func (h *Handler) GetSchemaDrift(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	report, err := drift.Check(r.Context(), h.MetadataRepo, h.MetadataRepo.DatabaseName())
+	if err != nil {
+		writeRepositoryError(w, r, "failed to check schema drift", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, report)
+}
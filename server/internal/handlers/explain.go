@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/queryplan"
+	"github.com/karanm6505/dbms/server/internal/repository"
+	"github.com/karanm6505/dbms/server/internal/sqlguard"
+)
+
+type explainRequest struct {
+	Name      string `json:"name" validate:"max=100"`
+	Query     string `json:"query"`
+	Arguments []any  `json:"arguments"`
+	Analyze   bool   `json:"analyze"`
+}
+
+func (h *Handler) GetExplainableQueries(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, repository.RegisteredQueries())
+}
+
+func (h *Handler) ExplainQuery(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req explainRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	var statement string
+
+	switch {
+	case req.Name != "" && strings.TrimSpace(req.Query) != "":
+		writeError(w, http.StatusBadRequest, "provide either name or query, not both")
+		return
+	case req.Name != "":
+		named, ok := repository.LookupQuery(req.Name)
+		if !ok {
+			writeError(w, http.StatusNotFound, "query is not registered")
+			return
+		}
+		if len(req.Arguments) != named.Parameters {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("query %s expects %d arguments", named.Name, named.Parameters))
+			return
+		}
+		statement = named.SQL
+	case strings.TrimSpace(req.Query) != "":
+		parsed, err := sqlguard.ParseReadOnly(req.Query)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if parsed.Kind != sqlguard.KindSelect {
+			writeError(w, http.StatusBadRequest, "only SELECT statements can be explained")
+			return
+		}
+		statement = parsed.Text
+	default:
+		writeError(w, http.StatusBadRequest, "name or query is required")
+		return
+	}
+
+	entry := &models.QueryLogEntry{
+		UserID:    user.ID,
+		UserEmail: user.Email,
+		Statement: statement,
+		Format:    "plan",
+		Status:    models.QueryStatusOK,
+	}
+	defer h.logConsoleQuery(r.Context(), entry)
+
+	started := time.Now()
+	plan, document, err := h.ConsoleRepo.Explain(r.Context(), statement, req.Arguments, req.Analyze, h.consoleConfig.Timeout)
+	entry.DurationMS = time.Since(started).Milliseconds()
+	if err != nil {
+		entry.Status = models.QueryStatusError
+		entry.ErrorMessage = err.Error()
+		writeQueryError(w, r, err)
+		return
+	}
+
+	root, warnings, raw, err := queryplan.Parse(document)
+	if err != nil {
+		writeServerError(w, r, "failed to parse query plan", err)
+		return
+	}
+
+	plan.Name = req.Name
+	plan.Root = root
+	plan.Cost = root.Cost
+	plan.Warnings = warnings
+	plan.Raw = raw
+
+	writeJSON(w, r, http.StatusOK, plan)
+}
@@ -1,44 +1,81 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/karanm6505/dbms/server/internal/config"
+	"github.com/karanm6505/dbms/server/internal/importer"
+	"github.com/karanm6505/dbms/server/internal/migrate"
 	"github.com/karanm6505/dbms/server/internal/repository"
 )
 
+// Database is the connection checked by the readiness probe.
+type Database interface {
+	PingContext(ctx context.Context) error
+	Stats() sql.DBStats
+}
+
+// MigrationSource reports the state of every known schema migration.
+type MigrationSource interface {
+	Status(ctx context.Context) ([]migrate.Status, error)
+}
+
 type Handler struct {
-	DB           *sql.DB
-	StudentRepo  *repository.StudentRepository
-	BookRepo     *repository.BookRepository
-	StaffRepo    *repository.StaffRepository
-	BorrowRepo   *repository.BorrowRepository
-	StatsRepo    *repository.StatsRepository
-	MetadataRepo *repository.MetadataRepository
-	UserRepo     *repository.UserRepository
-	authConfig   config.AuthConfig
+	DB             Database
+	Migrations     MigrationSource
+	StudentRepo    repository.StudentStore
+	BookRepo       repository.BookStore
+	StaffRepo      repository.StaffStore
+	BorrowRepo     repository.BorrowStore
+	StatsRepo      repository.StatsStore
+	MetadataRepo   repository.MetadataStore
+	UserRepo       repository.UserStore
+	ConsoleRepo    repository.ConsoleStore
+	SavedQueryRepo repository.SavedQueryStore
+	// Verifier delivers email verification codes for linking accounts to
+	// students.
+	Verifier VerificationSender
+	// linkRequests limits how often each account can ask for a code.
+	linkRequests *accountLimiter
+	// Imports tracks the bulk imports running in the background.
+	Imports       *importer.Jobs
+	authConfig    config.AuthConfig
+	consoleConfig config.ConsoleConfig
 }
 
 func New(
-	db *sql.DB,
-	studentRepo *repository.StudentRepository,
-	bookRepo *repository.BookRepository,
-	staffRepo *repository.StaffRepository,
-	borrowRepo *repository.BorrowRepository,
-	statsRepo *repository.StatsRepository,
-	metadataRepo *repository.MetadataRepository,
-	userRepo *repository.UserRepository,
+	db Database,
+	migrations MigrationSource,
+	studentRepo repository.StudentStore,
+	bookRepo repository.BookStore,
+	staffRepo repository.StaffStore,
+	borrowRepo repository.BorrowStore,
+	statsRepo repository.StatsStore,
+	metadataRepo repository.MetadataStore,
+	userRepo repository.UserStore,
+	consoleRepo repository.ConsoleStore,
+	savedQueryRepo repository.SavedQueryStore,
 	authCfg config.AuthConfig,
+	consoleCfg config.ConsoleConfig,
+	verifier VerificationSender,
 ) *Handler {
 	return &Handler{
-		DB:           db,
-		StudentRepo:  studentRepo,
-		BookRepo:     bookRepo,
-		StaffRepo:    staffRepo,
-		BorrowRepo:   borrowRepo,
-		StatsRepo:    statsRepo,
-		MetadataRepo: metadataRepo,
-		UserRepo:     userRepo,
-		authConfig:   authCfg,
+		DB:             db,
+		Migrations:     migrations,
+		StudentRepo:    studentRepo,
+		BookRepo:       bookRepo,
+		StaffRepo:      staffRepo,
+		BorrowRepo:     borrowRepo,
+		StatsRepo:      statsRepo,
+		MetadataRepo:   metadataRepo,
+		UserRepo:       userRepo,
+		ConsoleRepo:    consoleRepo,
+		SavedQueryRepo: savedQueryRepo,
+		Verifier:       verifier,
+		linkRequests:   newAccountLimiter(linkRequestLimit, linkRequestWindow),
+		Imports:        importer.NewJobs(),
+		authConfig:     authCfg,
+		consoleConfig:  consoleCfg,
 	}
 }
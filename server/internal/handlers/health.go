@@ -1,23 +1,177 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"net/http"
 	"time"
+
+	"github.com/karanm6505/dbms/server/internal/buildinfo"
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/migrate"
+)
+
+const (
+	healthStatusOK       = "ok"
+	healthStatusDegraded = "degraded"
+)
+
+const readinessTimeout = 3 * time.Second
+
+// The readiness probe is public, so a failed check reports one of these
+// instead of the driver's message, which names hosts and SQL. The error
+// itself goes to the log.
+const (
+	readinessErrorUnreachable = "database unreachable"
+	readinessErrorTimeout     = "database timed out"
+	readinessErrorMigrations  = "migration status unavailable"
 )
 
 type healthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
+	Status    string         `json:"status"`
+	Timestamp string         `json:"timestamp"`
+	Build     buildinfo.Info `json:"build"`
 }
 
+type readinessResponse struct {
+	healthResponse
+	Database   databaseCheck  `json:"database"`
+	Migrations migrationCheck `json:"migrations"`
+}
+
+type databaseCheck struct {
+	Status            string `json:"status"`
+	Error             string `json:"error,omitempty"`
+	LatencyMS         int64  `json:"latency_ms"`
+	MaxOpen           int    `json:"max_open_connections"`
+	Open              int    `json:"open_connections"`
+	InUse             int    `json:"in_use"`
+	Idle              int    `json:"idle"`
+	WaitCount         int64  `json:"wait_count"`
+	WaitDurationMS    int64  `json:"wait_duration_ms"`
+	MaxIdleClosed     int64  `json:"max_idle_closed"`
+	MaxLifetimeClosed int64  `json:"max_lifetime_closed"`
+}
+
+type migrationCheck struct {
+	Status         string  `json:"status"`
+	Error          string  `json:"error,omitempty"`
+	CurrentVersion int64   `json:"current_version"`
+	LatestVersion  int64   `json:"latest_version"`
+	Pending        int     `json:"pending"`
+	Dirty          []int64 `json:"dirty,omitempty"`
+	Mismatched     []int64 `json:"checksum_mismatch,omitempty"`
+}
+
+// HealthCheck is the liveness probe: it only reports that the process is up
+// and serving requests, never touching the database.
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	response := healthResponse{
-		Status:    "ok",
+	writeJSON(w, r, http.StatusOK, newHealthResponse(healthStatusOK))
+}
+
+// ReadinessCheck reports whether the service can take traffic: the database
+// must answer a ping and the schema must be at the latest migration.
+func (h *Handler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	response := readinessResponse{
+		healthResponse: newHealthResponse(healthStatusOK),
+		Database:       h.checkDatabase(ctx),
+	}
+
+	if response.Database.Status == healthStatusOK {
+		response.Migrations = h.checkMigrations(ctx)
+	} else {
+		response.Migrations = migrationCheck{Status: healthStatusDegraded, Error: readinessErrorUnreachable}
+	}
+
+	status := http.StatusOK
+	if response.Database.Status != healthStatusOK || response.Migrations.Status != healthStatusOK {
+		response.Status = healthStatusDegraded
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, r, status, response)
+}
+
+func newHealthResponse(status string) healthResponse {
+	return healthResponse{
+		Status:    status,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Build:     buildinfo.Get(),
+	}
+}
+
+func (h *Handler) checkDatabase(ctx context.Context) databaseCheck {
+	started := time.Now()
+	err := h.DB.PingContext(ctx)
+
+	stats := h.DB.Stats()
+	check := databaseCheck{
+		Status:            healthStatusOK,
+		LatencyMS:         time.Since(started).Milliseconds(),
+		MaxOpen:           stats.MaxOpenConnections,
+		Open:              stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDurationMS:    stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:     stats.MaxIdleClosed,
+		MaxLifetimeClosed: stats.MaxLifetimeClosed,
+	}
+
+	if err != nil {
+		check.Status = healthStatusDegraded
+		check.Error = readinessErrorUnreachable
+		if errors.Is(err, context.DeadlineExceeded) {
+			check.Error = readinessErrorTimeout
+		}
+		logging.RecordError(ctx, "readiness: database ping failed", err)
+	}
+
+	return check
+}
+
+func (h *Handler) checkMigrations(ctx context.Context) migrationCheck {
+	check := migrationCheck{Status: healthStatusOK}
+
+	statuses, err := h.Migrations.Status(ctx)
+	if err != nil {
+		check.Status = healthStatusDegraded
+		check.Error = readinessErrorMigrations
+		logging.RecordError(ctx, "readiness: failed to read migration status", err)
+		return check
+	}
+
+	return summarizeMigrations(statuses)
+}
+
+func summarizeMigrations(statuses []migrate.Status) migrationCheck {
+	check := migrationCheck{Status: healthStatusOK}
+
+	for _, status := range statuses {
+		if status.State != migrate.StateMissing && status.Version > check.LatestVersion {
+			check.LatestVersion = status.Version
+		}
+
+		switch status.State {
+		case migrate.StateApplied:
+			if status.Version > check.CurrentVersion {
+				check.CurrentVersion = status.Version
+			}
+		case migrate.StatePending:
+			check.Pending++
+		case migrate.StateDirty:
+			check.Dirty = append(check.Dirty, status.Version)
+		case migrate.StateChecksumMismatch:
+			check.Mismatched = append(check.Mismatched, status.Version)
+		}
+	}
+
+	if check.Pending > 0 || len(check.Dirty) > 0 || len(check.Mismatched) > 0 {
+		check.Status = healthStatusDegraded
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(response)
+	return check
 }
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/karanm6505/dbms/server/internal/migrate"
+)
+
+func TestReadinessCheckReportsUnavailableDatabase(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing().WillReturnError(errors.New("dial tcp 10.0.0.5:3306: connect: connection refused"))
+
+	h := &Handler{DB: db}
+	rec := httptest.NewRecorder()
+	h.ReadinessCheck(rec, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+
+	var payload readinessResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if payload.Status != healthStatusDegraded || payload.Database.Error != readinessErrorUnreachable {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if strings.Contains(rec.Body.String(), "10.0.0.5") {
+		t.Fatalf("the driver error leaked into the response: %s", rec.Body)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+type failingMigrations struct{}
+
+func (failingMigrations) Status(context.Context) ([]migrate.Status, error) {
+	return nil, errors.New(`Error 1146 (42S02): Table 'library.schema_migrations' doesn't exist`)
+}
+
+func TestReadinessCheckHidesMigrationErrors(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing()
+
+	h := &Handler{DB: db, Migrations: failingMigrations{}}
+	rec := httptest.NewRecorder()
+	h.ReadinessCheck(rec, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+
+	var payload readinessResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || payload.Migrations.Error != readinessErrorMigrations {
+		t.Fatalf("unexpected response %d %+v", rec.Code, payload)
+	}
+	if strings.Contains(rec.Body.String(), "schema_migrations") {
+		t.Fatalf("the driver error leaked into the response: %s", rec.Body)
+	}
+}
+
+func TestSummarizeMigrations(t *testing.T) {
+	check := summarizeMigrations([]migrate.Status{
+		{Version: 1, State: migrate.StateApplied},
+		{Version: 2, State: migrate.StateApplied},
+	})
+	if check.Status != healthStatusOK || check.CurrentVersion != 2 || check.LatestVersion != 2 {
+		t.Fatalf("unexpected check %+v", check)
+	}
+
+	check = summarizeMigrations([]migrate.Status{
+		{Version: 1, State: migrate.StateApplied},
+		{Version: 2, State: migrate.StateDirty},
+		{Version: 3, State: migrate.StatePending},
+	})
+	if check.Status != healthStatusDegraded || check.CurrentVersion != 1 || check.LatestVersion != 3 || check.Pending != 1 || len(check.Dirty) != 1 {
+		t.Fatalf("unexpected check %+v", check)
+	}
+}
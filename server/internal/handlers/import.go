@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/importer"
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+const (
+	maxImportBytes = 10 << 20
+	// importWait is how long an import request waits for its job. Imports
+	// that take longer answer 202 with the job to poll.
+	importWait = 2 * time.Second
+)
+
+// ImportBooks imports a CSV or XLSX file of books. See readImport for the
+// form it accepts.
+func (h *Handler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	table, mapping, dryRun, ok := h.readImport(w, r)
+	if !ok {
+		return
+	}
+
+	plan, problems := importer.PlanBooks(table, mapping)
+	if problems != nil {
+		writeValidationError(w, r, problems)
+		return
+	}
+
+	h.startImport(w, r, importer.KindBooks, dryRun, plan.Columns, len(table.Rows), func(ctx context.Context, job *importer.Job) error {
+		return plan.Run(ctx, job, h.BookRepo.Import)
+	})
+}
+
+// ImportStudents imports a CSV or XLSX file of students. See readImport for
+// the form it accepts.
+func (h *Handler) ImportStudents(w http.ResponseWriter, r *http.Request) {
+	table, mapping, dryRun, ok := h.readImport(w, r)
+	if !ok {
+		return
+	}
+
+	plan, problems := importer.PlanStudents(table, mapping)
+	if problems != nil {
+		writeValidationError(w, r, problems)
+		return
+	}
+
+	h.startImport(w, r, importer.KindStudents, dryRun, plan.Columns, len(table.Rows), func(ctx context.Context, job *importer.Job) error {
+		return plan.Run(ctx, job, h.StudentRepo.Import)
+	})
+}
+
+func (h *Handler) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	job, ok := h.Imports.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "import job not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, job.Snapshot())
+}
+
+// readImport reads a multipart form with the file in "file", an optional
+// JSON object mapping fields to column names in "mapping" and an optional
+// "dry_run" flag. It is admin only.
+func (h *Handler) readImport(w http.ResponseWriter, r *http.Request) (*importer.Table, importer.Mapping, bool, bool) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return nil, nil, false, false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "file is too large")
+			return nil, nil, false, false
+		}
+		writeError(w, http.StatusBadRequest, "request must be a multipart form")
+		return nil, nil, false, false
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	failures := make([]models.FieldError, 0)
+
+	var mapping importer.Mapping
+	if raw := r.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			failures = append(failures, models.FieldError{Field: "mapping", Code: "invalid", Message: "mapping must be a JSON object of field names to column names"})
+		}
+	}
+
+	dryRun := false
+	if raw := r.FormValue("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			failures = append(failures, models.FieldError{Field: "dry_run", Code: "invalid", Message: "dry_run must be true or false"})
+		}
+	}
+
+	var table *importer.Table
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		failures = append(failures, models.FieldError{Field: "file", Code: "required", Message: "file is required"})
+	} else {
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err == nil {
+			table, err = importer.Read(header.Filename, data)
+		}
+		if err != nil {
+			failures = append(failures, models.FieldError{Field: "file", Code: "invalid", Message: err.Error()})
+		}
+	}
+
+	if len(failures) > 0 {
+		writeValidationError(w, r, failures)
+		return nil, nil, false, false
+	}
+	return table, mapping, dryRun, true
+}
+
+// startImport runs work as a background job that outlives the request. It
+// answers with the finished job when it completes within importWait, and
+// otherwise with 202 and the job to poll.
+func (h *Handler) startImport(w http.ResponseWriter, r *http.Request, kind string, dryRun bool, columns map[string]string, total int, work func(ctx context.Context, job *importer.Job) error) {
+	ctx := context.WithoutCancel(r.Context())
+	job := h.Imports.Start(ctx, kind, dryRun, columns, total, func(ctx context.Context, job *importer.Job) error {
+		err := work(ctx, job)
+		if err != nil {
+			logging.FromContext(ctx).Error("import failed", "kind", kind, "job", job.Snapshot().ID, "error", err)
+		}
+		return err
+	})
+
+	select {
+	case <-job.Done():
+	case <-time.After(importWait):
+	case <-r.Context().Done():
+	}
+
+	state := job.Snapshot()
+	w.Header().Set("Location", "/api/import/jobs/"+state.ID)
+	status := http.StatusOK
+	if !state.Done() {
+		status = http.StatusAccepted
+	}
+	writeJSON(w, r, status, state)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+)
+
+const studentsCSV = `first_name,last_name,email address
+Ada,Lovelace,ada@example.com
+James,White,JAMES.WHITE@example.com
+Alan,Turing,not-an-email
+`
+
+// importRequest builds the multipart form an import endpoint reads. Empty
+// fields are left out.
+func importRequest(t *testing.T, path string, fields map[string]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if name == "file" {
+			part, err := form.CreateFormFile("file", "students.csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = part.Write([]byte(value))
+			continue
+		}
+		_ = form.WriteField(name, value)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, path, &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	return asAdmin(r)
+}
+
+func decodeJob(t *testing.T, rec *httptest.ResponseRecorder) models.ImportJob {
+	t.Helper()
+
+	var job models.ImportJob
+	if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+		t.Fatalf("failed to decode job: %v: %s", err, rec.Body)
+	}
+	return job
+}
+
+func TestImportStudents(t *testing.T) {
+	h := newMemoryHandler()
+	mapping := `{"email": "Email Address"}`
+
+	countStudents := func() int {
+		students, err := h.StudentRepo.GetAll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(students)
+	}
+	before := countStudents()
+
+	rec := serve(h.ImportStudents, importRequest(t, "/api/import/students", map[string]string{"file": studentsCSV, "mapping": mapping, "dry_run": "true"}))
+	job := decodeJob(t, rec)
+	if rec.Code != http.StatusOK || job.Status != models.ImportCompleted || !job.DryRun {
+		t.Fatalf("expected a completed dry run, got %d: %s", rec.Code, rec.Body)
+	}
+	if job.Total != 3 || job.Imported != 1 || job.Duplicates != 1 || job.Invalid != 1 || countStudents() != before {
+		t.Fatalf("expected 1 importable, 1 duplicate and 1 invalid row and nothing written, got %+v", job)
+	}
+	if len(job.Errors) != 2 || job.Errors[0].Row != 3 || job.Errors[0].Code != "duplicate" || job.Errors[1].Row != 4 || job.Errors[1].Field != "email" {
+		t.Fatalf("unexpected row errors %+v", job.Errors)
+	}
+
+	rec = serve(h.ImportStudents, importRequest(t, "/api/import/students", map[string]string{"file": studentsCSV, "mapping": mapping}))
+	if job := decodeJob(t, rec); rec.Code != http.StatusOK || job.Imported != 1 || countStudents() != before+1 {
+		t.Fatalf("expected one student to be imported, got %d: %s", rec.Code, rec.Body)
+	}
+
+	location := rec.Header().Get("Location")
+	r := withID(asAdmin(httptest.NewRequest(http.MethodGet, location, nil)), strings.TrimPrefix(location, "/api/import/jobs/"))
+	if rec := serve(h.GetImportJob, r); rec.Code != http.StatusOK || decodeJob(t, rec).Imported != 1 {
+		t.Fatalf("expected the job to be pollable at %s, got %d: %s", location, rec.Code, rec.Body)
+	}
+}
+
+func TestImportValidatesForm(t *testing.T) {
+	h := newMemoryHandler()
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+		field  string
+	}{
+		{"missing file", map[string]string{"dry_run": "true"}, "file"},
+		{"bad dry_run", map[string]string{"file": studentsCSV, "dry_run": "maybe"}, "dry_run"},
+		{"bad mapping", map[string]string{"file": studentsCSV, "mapping": `["email"]`}, "mapping"},
+		// email address is not a column name the email field matches.
+		{"unmapped required field", map[string]string{"file": studentsCSV}, "mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h.ImportStudents, importRequest(t, "/api/import/students", tt.fields))
+			p := decodeProblem(t, rec)
+			if rec.Code != http.StatusBadRequest || p.Code != problem.CodeValidationFailed || len(p.Errors) == 0 || p.Errors[0].Field != tt.field {
+				t.Fatalf("expected a validation error for %s, got %d: %s", tt.field, rec.Code, rec.Body)
+			}
+		})
+	}
+}
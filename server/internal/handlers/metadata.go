@@ -1,14 +1,15 @@
 package handlers
 
 import (
-	"encoding/json"
+	"database/sql"
 	"errors"
-	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/karanm6505/dbms/server/internal/models"
 	"github.com/karanm6505/dbms/server/internal/repository"
+	"github.com/karanm6505/dbms/server/internal/validate"
 )
 
 type executeRequest struct {
@@ -18,41 +19,87 @@ type executeRequest struct {
 func (h *Handler) GetTables(w http.ResponseWriter, r *http.Request) {
 	tables, err := h.MetadataRepo.ListTables(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch tables")
+		writeServerError(w, r, "failed to fetch tables", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, tables)
+	writeJSON(w, r, http.StatusOK, tables)
 }
 
 func (h *Handler) GetFunctions(w http.ResponseWriter, r *http.Request) {
 	functions, err := h.MetadataRepo.ListFunctions(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch functions")
+		writeServerError(w, r, "failed to fetch functions", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, functions)
+	writeJSON(w, r, http.StatusOK, functions)
 }
 
 func (h *Handler) GetProcedures(w http.ResponseWriter, r *http.Request) {
 	procedures, err := h.MetadataRepo.ListProcedures(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch procedures")
+		writeServerError(w, r, "failed to fetch procedures", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, procedures)
+	writeJSON(w, r, http.StatusOK, procedures)
 }
 
 func (h *Handler) GetTriggers(w http.ResponseWriter, r *http.Request) {
 	triggers, err := h.MetadataRepo.ListTriggers(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch triggers")
+		writeServerError(w, r, "failed to fetch triggers", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, triggers)
+	writeJSON(w, r, http.StatusOK, triggers)
+}
+
+// GetSchemaCapabilities reports which schema operations the database
+// driver supports, so clients can hide the ones that would answer 501.
+func (h *Handler) GetSchemaCapabilities(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, h.MetadataRepo.Capabilities())
+}
+
+func (h *Handler) GetTableDefinition(w http.ResponseWriter, r *http.Request) {
+	table, err := h.MetadataRepo.GetTableDefinition(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		writeDefinitionError(w, r, err, "table")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, table)
+}
+
+func (h *Handler) GetFunctionDefinition(w http.ResponseWriter, r *http.Request) {
+	function, err := h.MetadataRepo.GetFunctionDefinition(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		writeDefinitionError(w, r, err, "function")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, function)
+}
+
+func (h *Handler) GetProcedureDefinition(w http.ResponseWriter, r *http.Request) {
+	procedure, err := h.MetadataRepo.GetProcedureDefinition(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		writeDefinitionError(w, r, err, "procedure")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, procedure)
+}
+
+func (h *Handler) GetTriggerDefinition(w http.ResponseWriter, r *http.Request) {
+	trigger, err := h.MetadataRepo.GetTriggerDefinition(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		writeDefinitionError(w, r, err, "trigger")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, trigger)
 }
 
 func (h *Handler) ExecuteProcedure(w http.ResponseWriter, r *http.Request) {
@@ -62,23 +109,19 @@ func (h *Handler) ExecuteProcedure(w http.ResponseWriter, r *http.Request) {
 
 	name := chi.URLParam(r, "name")
 
-	req, err := decodeExecuteRequest(r)
+	req, err := decodeExecuteRequest(w, r)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+		writeDecodeError(w, r, err)
 		return
 	}
 
 	rows, err := h.MetadataRepo.ExecuteProcedure(r.Context(), name, req.Arguments)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, repository.ErrInvalidIdentifier) {
-			status = http.StatusBadRequest
-		}
-		writeError(w, status, err.Error())
+		writeRoutineError(w, r, "procedure", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	writeJSON(w, r, http.StatusOK, map[string]any{
 		"name": name,
 		"rows": rows,
 	})
@@ -91,41 +134,51 @@ func (h *Handler) ExecuteFunction(w http.ResponseWriter, r *http.Request) {
 
 	name := chi.URLParam(r, "name")
 
-	req, err := decodeExecuteRequest(r)
+	req, err := decodeExecuteRequest(w, r)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+		writeDecodeError(w, r, err)
 		return
 	}
 
 	result, err := h.MetadataRepo.ExecuteFunction(r.Context(), name, req.Arguments)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, repository.ErrInvalidIdentifier) {
-			status = http.StatusBadRequest
-		}
-		writeError(w, status, err.Error())
+		writeRoutineError(w, r, "function", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	writeJSON(w, r, http.StatusOK, map[string]any{
 		"name":   name,
 		"result": result,
 	})
 }
 
-func decodeExecuteRequest(r *http.Request) (executeRequest, error) {
-	var payload executeRequest
+func writeDefinitionError(w http.ResponseWriter, r *http.Request, err error, objectType string) {
+	switch {
+	case errors.Is(err, repository.ErrInvalidIdentifier):
+		writeError(w, http.StatusBadRequest, "invalid "+objectType+" name")
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, objectType+" not found")
+	default:
+		writeRepositoryError(w, r, "failed to fetch "+objectType+" definition", err)
+	}
+}
 
-	if r.Body == nil || r.Body == http.NoBody {
-		return payload, nil
+// writeRoutineError keeps raw MySQL text out of the response: SIGNAL messages
+// raised by the schema's own routines are passed through, everything else is
+// reported by code.
+func writeRoutineError(w http.ResponseWriter, r *http.Request, objectType string, err error) {
+	if errors.Is(err, repository.ErrInvalidIdentifier) {
+		writeValidationError(w, r, []models.FieldError{{Field: "name", Code: "identifier", Message: "invalid " + objectType + " name"}})
+		return
 	}
-	defer r.Body.Close()
+	writeRepositoryError(w, r, "failed to execute "+objectType, err)
+}
+
+// decodeExecuteRequest accepts an empty body as a call without arguments.
+func decodeExecuteRequest(w http.ResponseWriter, r *http.Request) (executeRequest, error) {
+	var payload executeRequest
 
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&payload); err != nil {
-		if errors.Is(err, io.EOF) {
-			return payload, nil
-		}
+	if err := validate.Decode(w, r, &payload, validate.DefaultMaxBodyBytes); err != nil && !errors.Is(err, validate.ErrEmptyBody) {
 		return payload, err
 	}
 
@@ -2,15 +2,22 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/problem"
 )
 
 func TestDecodeExecuteRequestNilBody(t *testing.T) {
 	r := httptest.NewRequest(http.MethodPost, "/", nil)
 
-	payload, err := decodeExecuteRequest(r)
+	payload, err := decodeExecuteRequest(httptest.NewRecorder(), r)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -24,7 +31,7 @@ func TestDecodeExecuteRequestEmptyJSON(t *testing.T) {
 	body := bytes.NewBufferString(`{}`)
 	r := httptest.NewRequest(http.MethodPost, "/", body)
 
-	payload, err := decodeExecuteRequest(r)
+	payload, err := decodeExecuteRequest(httptest.NewRecorder(), r)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -38,7 +45,7 @@ func TestDecodeExecuteRequestWithArguments(t *testing.T) {
 	json := `{"arguments": [1, "two", true]}`
 	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(json))
 
-	payload, err := decodeExecuteRequest(r)
+	payload, err := decodeExecuteRequest(httptest.NewRecorder(), r)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -51,8 +58,49 @@ func TestDecodeExecuteRequestWithArguments(t *testing.T) {
 func TestDecodeExecuteRequestInvalidJSON(t *testing.T) {
 	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{`))
 
-	_, err := decodeExecuteRequest(r)
+	_, err := decodeExecuteRequest(httptest.NewRecorder(), r)
 	if err == nil {
 		t.Fatal("expected error for invalid json")
 	}
 }
+
+func TestWriteRoutineErrorHidesDriverMessage(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/procedures/get_student/execute", nil)
+	w := httptest.NewRecorder()
+	w.Header().Set(logging.RequestIDHeader, "req-1")
+
+	writeRoutineError(w, r, "procedure", &mysql.MySQLError{Number: 1146, Message: "Table 'library.users' doesn't exist"})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != problem.ContentType {
+		t.Fatalf("unexpected content type %q", got)
+	}
+
+	var body problem.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid problem document: %v", err)
+	}
+	if body.Code != problem.CodeInternal || body.RequestID != "req-1" || body.Instance != r.URL.Path {
+		t.Fatalf("unexpected problem %+v", body)
+	}
+	if strings.Contains(w.Body.String(), "library.users") {
+		t.Fatalf("response leaked driver message: %s", w.Body.String())
+	}
+}
+
+func TestWriteRoutineErrorReportsBorrowLimit(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/procedures/issue_book/execute", nil)
+	w := httptest.NewRecorder()
+
+	writeRoutineError(w, r, "procedure", &mysql.MySQLError{Number: 1644, Message: "Cannot borrow more than 3 books at a time"})
+
+	var body problem.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid problem document: %v", err)
+	}
+	if w.Code != http.StatusConflict || body.Code != problem.CodeBorrowLimitExceeded {
+		t.Fatalf("expected borrow limit conflict, got %d %+v", w.Code, body)
+	}
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+	"github.com/karanm6505/dbms/server/internal/repository"
+)
+
+// verificationCodeTTL is how long an email verification code can be used.
+const verificationCodeTTL = 15 * time.Minute
+
+// An account can ask for linkRequestLimit codes per linkRequestWindow. Each
+// code allows a single guess, so this also caps guesses at the code space.
+const (
+	linkRequestLimit  = 5
+	linkRequestWindow = time.Hour
+)
+
+// VerificationSender delivers the code that proves a user owns a student's
+// email address.
+type VerificationSender interface {
+	SendVerificationCode(ctx context.Context, email, code string) error
+}
+
+// LogVerificationSender writes verification codes to the server log at
+// debug level, for development without a mail relay. Configuration refuses
+// it in production.
+type LogVerificationSender struct{}
+
+func (LogVerificationSender) SendVerificationCode(ctx context.Context, email, code string) error {
+	logging.FromContext(ctx).Debug("email verification code", "email", email, "code", code)
+	return nil
+}
+
+type verifyLinkRequest struct {
+	Code string `json:"code" validate:"required,max=6"`
+}
+
+type linkStudentRequest struct {
+	StudentID int `json:"student_id" validate:"required,min=1"`
+}
+
+type renewRequest struct {
+	BorrowID int `json:"borrow_id" validate:"required,min=1"`
+}
+
+type linkResponse struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RequestStudentLink sends a verification code to the account's email
+// address when exactly one student has it. Confirming the code with
+// VerifyStudentLink links the account to that student. Requests beyond
+// linkRequestLimit per account are refused with 429.
+func (h *Handler) RequestStudentLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	if user.StudentID != nil {
+		writeError(w, http.StatusConflict, "account is already linked to a student")
+		return
+	}
+
+	if wait, ok := h.linkRequests.allow(user.ID); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Round(time.Second)/time.Second)))
+		writeProblem(w, r, problem.New(http.StatusTooManyRequests, problem.CodeTooManyRequests, "too many verification codes requested; try again later"))
+		return
+	}
+
+	if _, ok := h.studentForEmail(w, r, user.Email); !ok {
+		return
+	}
+
+	code, err := verificationCode()
+	if err != nil {
+		writeServerError(w, r, "failed to create verification code", err)
+		return
+	}
+
+	expires := time.Now().Add(verificationCodeTTL).UTC()
+	if err := h.UserRepo.SetVerification(r.Context(), user.ID, hashCode(code), expires); err != nil {
+		writeRepositoryError(w, r, "failed to store verification code", err)
+		return
+	}
+
+	if err := h.Verifier.SendVerificationCode(r.Context(), user.Email, code); err != nil {
+		writeServerError(w, r, "failed to send verification code", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusAccepted, linkResponse{Email: user.Email, ExpiresAt: expires})
+}
+
+// VerifyStudentLink links the account to the student with its email address
+// once the code sent by RequestStudentLink is confirmed. A wrong code uses
+// up the pending one, so codes cannot be guessed.
+func (h *Handler) VerifyStudentLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req verifyLinkRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	pending := user.VerificationHash != "" && user.VerificationExpiresAt != nil && time.Now().Before(*user.VerificationExpiresAt)
+	if !pending || subtle.ConstantTimeCompare([]byte(user.VerificationHash), []byte(hashCode(req.Code))) != 1 {
+		if pending {
+			if err := h.UserRepo.SetVerification(r.Context(), user.ID, "", time.Time{}); err != nil {
+				writeRepositoryError(w, r, "failed to clear verification code", err)
+				return
+			}
+		}
+		writeValidationError(w, r, []models.FieldError{{Field: "code", Code: "invalid", Message: "code is wrong or has expired; request a new one"}})
+		return
+	}
+
+	student, ok := h.studentForEmail(w, r, user.Email)
+	if !ok {
+		return
+	}
+
+	if err := h.UserRepo.ConfirmEmail(r.Context(), user.ID, student.ID); err != nil {
+		writeLinkError(w, r, err)
+		return
+	}
+
+	h.writeUser(w, r, user.ID)
+}
+
+// LinkUserStudent lets an admin link an account to a student without email
+// verification, for students whose address is missing or shared.
+func (h *Handler) LinkUserStudent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	var req linkStudentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if _, err := h.StudentRepo.GetByID(r.Context(), req.StudentID); err != nil {
+		writeRepositoryError(w, r, "failed to fetch student", err)
+		return
+	}
+
+	if err := h.UserRepo.LinkStudent(r.Context(), userID, &req.StudentID); err != nil {
+		writeLinkError(w, r, err)
+		return
+	}
+
+	h.writeUser(w, r, userID)
+}
+
+func (h *Handler) UnlinkUserStudent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if err := h.UserRepo.LinkStudent(r.Context(), userID, nil); err != nil {
+		writeLinkError(w, r, err)
+		return
+	}
+
+	h.writeUser(w, r, userID)
+}
+
+func (h *Handler) GetMyLoans(w http.ResponseWriter, r *http.Request) {
+	if profile, ok := h.myProfile(w, r, models.ProfileLoans); ok {
+		writeJSON(w, r, http.StatusOK, profile.Loans)
+	}
+}
+
+func (h *Handler) GetMyHolds(w http.ResponseWriter, r *http.Request) {
+	if profile, ok := h.myProfile(w, r, models.ProfileHolds); ok {
+		writeJSON(w, r, http.StatusOK, profile.Holds)
+	}
+}
+
+func (h *Handler) GetMyFines(w http.ResponseWriter, r *http.Request) {
+	if profile, ok := h.myProfile(w, r, models.ProfileFines); ok {
+		writeJSON(w, r, http.StatusOK, profile.Fines)
+	}
+}
+
+// RenewMyLoan renews one of the linked student's own loans.
+func (h *Handler) RenewMyLoan(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := h.linkedStudent(w, r)
+	if !ok {
+		return
+	}
+
+	var req renewRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.BorrowRepo.Renew(r.Context(), req.BorrowID, studentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "loan not found")
+			return
+		}
+		writeRepositoryError(w, r, "failed to renew loan", err)
+		return
+	}
+
+	h.writeBorrowRecord(w, r, http.StatusOK, req.BorrowID)
+}
+
+// linkedStudent returns the student the signed-in account is linked to.
+func (h *Handler) linkedStudent(w http.ResponseWriter, r *http.Request) (int, bool) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return 0, false
+	}
+	if user.StudentID == nil {
+		writeProblem(w, r, problem.New(http.StatusForbidden, problem.CodeAccountNotLinked, "account is not linked to a student"))
+		return 0, false
+	}
+	return *user.StudentID, true
+}
+
+func (h *Handler) myProfile(w http.ResponseWriter, r *http.Request, section string) (*models.StudentProfile, bool) {
+	studentID, ok := h.linkedStudent(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	profile, err := h.StudentRepo.GetProfile(r.Context(), studentID, models.ProfileQuery{Fields: []string{section}, Page: 1, PageSize: defaultProfilePageSize})
+	if err != nil {
+		writeRepositoryError(w, r, "failed to fetch "+section, err)
+		return nil, false
+	}
+	return profile, true
+}
+
+// studentForEmail finds the one student an account's email address can be
+// verified against.
+func (h *Handler) studentForEmail(w http.ResponseWriter, r *http.Request, email string) (*models.Student, bool) {
+	student, err := h.StudentRepo.GetByEmail(r.Context(), email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "no student has this account's email address")
+		return nil, false
+	case errors.Is(err, repository.ErrAmbiguousEmail):
+		writeError(w, http.StatusConflict, "more than one student has this email address; ask library staff to link the account")
+		return nil, false
+	case err != nil:
+		writeServerError(w, r, "failed to fetch student", err)
+		return nil, false
+	}
+	return student, true
+}
+
+func (h *Handler) writeUser(w http.ResponseWriter, r *http.Request, id int64) {
+	user, err := h.UserRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepositoryError(w, r, "failed to fetch user", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newUserResponse(user))
+}
+
+func writeLinkError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, repository.ErrStudentAlreadyLinked) {
+		writeProblem(w, r, problem.New(http.StatusConflict, problem.CodeAlreadyExists, "the student is already linked to another account"))
+		return
+	}
+	writeRepositoryError(w, r, "failed to link student", err)
+}
+
+// verificationCode returns six random digits.
+func verificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// accountLimiter allows each account a fixed number of events per window.
+// Its counts live in this process, so each API instance keeps its own.
+type accountLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	windows map[int64]limitWindow
+}
+
+type limitWindow struct {
+	start time.Time
+	count int
+}
+
+func newAccountLimiter(limit int, window time.Duration) *accountLimiter {
+	return &accountLimiter{limit: limit, window: window, now: time.Now, windows: make(map[int64]limitWindow)}
+}
+
+// allow records an event for the account and reports whether it is within
+// the limit, or else how long until the account's window resets.
+func (l *accountLimiter) allow(id int64) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.window {
+			delete(l.windows, key)
+		}
+	}
+
+	w, ok := l.windows[id]
+	if !ok {
+		w = limitWindow{start: now}
+	}
+	if w.count >= l.limit {
+		return w.start.Add(l.window).Sub(now), false
+	}
+	w.count++
+	l.windows[id] = w
+	return 0, true
+}
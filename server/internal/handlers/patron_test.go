@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+)
+
+type capturedCode struct{ email, code string }
+
+func (c *capturedCode) SendVerificationCode(_ context.Context, email, code string) error {
+	c.email, c.code = email, code
+	return nil
+}
+
+// newPatron registers a patron account and returns its ID.
+func newPatron(t *testing.T, h *Handler, email string) int64 {
+	t.Helper()
+
+	user := &models.User{Email: email, PasswordHash: "hash", Role: models.RolePatron}
+	if err := h.UserRepo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	return user.ID
+}
+
+// asUser signs the request in as the stored user, as AuthMiddleware does.
+func asUser(t *testing.T, h *Handler, r *http.Request, id int64) *http.Request {
+	t.Helper()
+
+	user, err := h.UserRepo.GetByID(r.Context(), id)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	return r.WithContext(context.WithValue(r.Context(), userContextKey, user))
+}
+
+func serve(handler http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler(rec, r)
+	return rec
+}
+
+func withID(r *http.Request, id string) *http.Request {
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", id)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+}
+
+func linkPatron(t *testing.T, h *Handler, userID int64, studentID int) {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPut, "/api/users/{id}/student", strings.NewReader(`{"student_id":`+strconv.Itoa(studentID)+`}`))
+	if rec := serve(h.LinkUserStudent, asAdmin(withID(r, strconv.FormatInt(userID, 10)))); rec.Code != http.StatusOK {
+		t.Fatalf("expected link to succeed, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPatronLinksByVerifiedEmail(t *testing.T) {
+	h := newMemoryHandler()
+	sender := &capturedCode{}
+	h.Verifier = sender
+	id := newPatron(t, h, "james.white@example.com")
+
+	requestCode := func() {
+		t.Helper()
+		rec := serve(h.RequestStudentLink, asUser(t, h, httptest.NewRequest(http.MethodPost, "/api/me/link", nil), id))
+		if rec.Code != http.StatusAccepted || sender.email != "james.white@example.com" || len(sender.code) != 6 {
+			t.Fatalf("expected a code to be sent, got %d: %s", rec.Code, rec.Body)
+		}
+	}
+	verify := func(code string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/me/link/verify", strings.NewReader(`{"code":"`+code+`"}`))
+		return serve(h.VerifyStudentLink, asUser(t, h, r, id))
+	}
+
+	requestCode()
+	wrong := "000000"
+	if sender.code == wrong {
+		wrong = "111111"
+	}
+	if rec := verify(wrong); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a wrong code to be rejected, got %d", rec.Code)
+	}
+	if rec := verify(sender.code); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a wrong guess to use up the code, got %d", rec.Code)
+	}
+
+	requestCode()
+	rec := verify(sender.code)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var user userResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.StudentID == nil || *user.StudentID != 9 {
+		t.Fatalf("expected the account to be linked to student 9, got %+v", user)
+	}
+
+	if rec := serve(h.RequestStudentLink, asUser(t, h, httptest.NewRequest(http.MethodPost, "/api/me/link", nil), id)); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a linked account, got %d", rec.Code)
+	}
+}
+
+func TestPatronLinkRequestsAreThrottled(t *testing.T) {
+	h := newMemoryHandler()
+	h.Verifier = &capturedCode{}
+	now := time.Date(2025, 10, 8, 9, 0, 0, 0, time.UTC)
+	h.linkRequests.now = func() time.Time { return now }
+
+	first := newPatron(t, h, "james.white@example.com")
+	second := newPatron(t, h, "emma.brown@example.com")
+	request := func(id int64) *httptest.ResponseRecorder {
+		return serve(h.RequestStudentLink, asUser(t, h, httptest.NewRequest(http.MethodPost, "/api/me/link", nil), id))
+	}
+
+	for i := 0; i < linkRequestLimit; i++ {
+		if rec := request(first); rec.Code != http.StatusAccepted {
+			t.Fatalf("request %d: expected 202, got %d: %s", i+1, rec.Code, rec.Body)
+		}
+	}
+
+	now = now.Add(10 * time.Minute)
+	rec := request(first)
+	if p := decodeProblem(t, rec); rec.Code != http.StatusTooManyRequests || p.Code != problem.CodeTooManyRequests {
+		t.Fatalf("expected 429 TOO_MANY_REQUESTS, got %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "3000" {
+		t.Fatalf("expected Retry-After to count down the window, got %q", got)
+	}
+
+	if rec := request(second); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected other accounts to be unaffected, got %d: %s", rec.Code, rec.Body)
+	}
+
+	now = now.Add(linkRequestWindow)
+	if rec := request(first); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected a new window to allow requests again, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPatronLinkNeedsMatchingStudent(t *testing.T) {
+	h := newMemoryHandler()
+	id := newPatron(t, h, "stranger@example.com")
+
+	rec := serve(h.RequestStudentLink, asUser(t, h, httptest.NewRequest(http.MethodPost, "/api/me/link", nil), id))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPatronSeesOnlyOwnRecords(t *testing.T) {
+	h := newMemoryHandler()
+	id := newPatron(t, h, "patron@example.com")
+	linkPatron(t, h, id, 3)
+
+	rec := serve(h.GetBorrowRecords, asUser(t, h, httptest.NewRequest(http.MethodGet, "/api/borrows", nil), id))
+	var records []models.BorrowRecord
+	if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	// Student 3 holds three books in the sample data.
+	if len(records) != 3 {
+		t.Fatalf("expected the patron's 3 loans, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.StudentID != 3 {
+			t.Fatalf("patron saw a loan of student %d", record.StudentID)
+		}
+	}
+
+	rec = serve(h.GetStudents, asUser(t, h, httptest.NewRequest(http.MethodGet, "/api/students", nil), id))
+	var students []models.Student
+	if err := json.Unmarshal(rec.Body.Bytes(), &students); err != nil {
+		t.Fatal(err)
+	}
+	if len(students) != 1 || students[0].ID != 3 {
+		t.Fatalf("expected only student 3, got %+v", students)
+	}
+
+	for studentID, want := range map[string]int{"3": http.StatusOK, "1": http.StatusNotFound} {
+		r := withID(httptest.NewRequest(http.MethodGet, "/api/students/"+studentID, nil), studentID)
+		if rec := serve(h.GetStudentByID, asUser(t, h, r, id)); rec.Code != want {
+			t.Fatalf("student %s: expected %d, got %d", studentID, want, rec.Code)
+		}
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	rec = httptest.NewRecorder()
+	h.RequireStaff(next).ServeHTTP(rec, asUser(t, h, httptest.NewRequest(http.MethodGet, "/api/staff", nil), id))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected staff routes to be forbidden, got %d", rec.Code)
+	}
+}
+
+func TestRenewMyLoan(t *testing.T) {
+	h := newMemoryHandler()
+	id := newPatron(t, h, "patron@example.com")
+
+	renew := func(borrowID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/me/renew", strings.NewReader(`{"borrow_id":`+borrowID+`}`))
+		return serve(h.RenewMyLoan, asUser(t, h, r, id))
+	}
+
+	rec := renew("2")
+	if p := decodeProblem(t, rec); rec.Code != http.StatusForbidden || p.Code != problem.CodeAccountNotLinked {
+		t.Fatalf("expected 403 %s, got %d %s", problem.CodeAccountNotLinked, rec.Code, p.Code)
+	}
+
+	linkPatron(t, h, id, 3)
+
+	// Loan 2 of student 3 was due on 2025-09-30.
+	for i := 0; i < 2; i++ {
+		rec := renew("2")
+		var record models.BorrowRecord
+		if err := json.Unmarshal(rec.Body.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusOK || record.DueDate <= "2025-09-30" {
+			t.Fatalf("expected the due date to move, got %d: %s", rec.Code, rec.Body)
+		}
+	}
+
+	rec = renew("2")
+	if p := decodeProblem(t, rec); rec.Code != http.StatusConflict || p.Code != problem.CodeRenewalLimit {
+		t.Fatalf("expected 409 %s, got %d %s", problem.CodeRenewalLimit, rec.Code, p.Code)
+	}
+
+	// Loan 4 belongs to student 2.
+	if rec := renew("4"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected another student's loan to be hidden, got %d", rec.Code)
+	}
+}
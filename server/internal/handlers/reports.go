@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+	"github.com/karanm6505/dbms/server/internal/redact"
+	"github.com/karanm6505/dbms/server/internal/reports"
+	"github.com/karanm6505/dbms/server/internal/repository"
+	"github.com/karanm6505/dbms/server/internal/validate"
+)
+
+type reportRequest struct {
+	Slug         string                   `json:"slug" validate:"required,max=100"`
+	Title        string                   `json:"title" validate:"required,max=200"`
+	Description  string                   `json:"description" validate:"max=1000"`
+	SQL          string                   `json:"sql" validate:"required"`
+	Parameters   []models.ReportParameter `json:"parameters"`
+	RequiredRole models.Role              `json:"required_role"`
+}
+
+func (h *Handler) GetReports(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	saved, err := h.SavedQueryRepo.List(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to fetch reports", err)
+		return
+	}
+
+	visible := make([]models.SavedQuery, 0, len(saved))
+	for _, report := range saved {
+		if !user.Role.Allows(report.RequiredRole) {
+			continue
+		}
+		if !user.IsAdmin() {
+			report.SQL = ""
+		}
+		visible = append(visible, report)
+	}
+
+	writeJSON(w, r, http.StatusOK, visible)
+}
+
+func (h *Handler) RunReport(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	report, err := h.SavedQueryRepo.GetBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	if !user.Role.Allows(report.RequiredRole) {
+		writeError(w, http.StatusForbidden, "insufficient permissions to run this report")
+		return
+	}
+
+	format, err := resolveResultFormat(r, "")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	statement, args, err := reports.Bind(*report, r.URL.Query())
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	started := time.Now()
+	results := &redactedResults{resultWriter: newResultWriter(w, format, report.Slug), policy: redact.For(user.Role)}
+
+	count, truncated, err := h.ConsoleRepo.QueryReadOnly(
+		r.Context(),
+		statement,
+		args,
+		h.consoleConfig.MaxRows,
+		h.consoleConfig.Timeout,
+		results.columns,
+		results.row,
+	)
+	if err != nil && !results.started() {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	results.finish(count, truncated, time.Since(started), err)
+}
+
+// redactedResults applies a role's policy to report rows, which do not pass
+// through writeJSON, so personal columns are masked as they are elsewhere.
+type redactedResults struct {
+	resultWriter
+	policy  redact.Policy
+	classes []string
+}
+
+func (r *redactedResults) columns(columns []string) error {
+	r.classes = make([]string, len(columns))
+	for i, column := range columns {
+		r.classes[i] = redact.ColumnClass(column)
+	}
+	return r.resultWriter.columns(columns)
+}
+
+func (r *redactedResults) row(values []any) error {
+	r.policy.Row(r.classes, values)
+	return r.resultWriter.row(values)
+}
+
+func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := decodeReportRequest(w, r)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+	report.CreatedBy = user.ID
+
+	if err := h.SavedQueryRepo.Create(r.Context(), report); err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	created, err := h.SavedQueryRepo.GetBySlug(r.Context(), report.Slug)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, created)
+}
+
+func (h *Handler) UpdateReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	report, err := decodeReportRequest(w, r)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	if err := h.SavedQueryRepo.Update(r.Context(), chi.URLParam(r, "slug"), report); err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	updated, err := h.SavedQueryRepo.GetBySlug(r.Context(), report.Slug)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, updated)
+}
+
+func (h *Handler) DeleteReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	if err := h.SavedQueryRepo.Delete(r.Context(), chi.URLParam(r, "slug")); err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeReportRequest(w http.ResponseWriter, r *http.Request) (*models.SavedQuery, error) {
+	var req reportRequest
+	if err := validate.Decode(w, r, &req, validate.DefaultMaxBodyBytes); err != nil {
+		return nil, err
+	}
+
+	report := &models.SavedQuery{
+		Slug:         req.Slug,
+		Title:        req.Title,
+		Description:  req.Description,
+		SQL:          req.SQL,
+		Parameters:   req.Parameters,
+		RequiredRole: req.RequiredRole,
+	}
+	if report.Parameters == nil {
+		report.Parameters = make([]models.ReportParameter, 0)
+	}
+
+	if err := reports.Validate(report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func writeReportError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		validationErr *reports.ValidationError
+		requestErr    *validate.Error
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		writeValidationError(w, r, validationErr.Problems)
+	case errors.As(err, &requestErr), errors.Is(err, validate.ErrEmptyBody), errors.Is(err, validate.ErrBodyTooLarge):
+		writeDecodeError(w, r, err)
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "report not found")
+	case errors.Is(err, repository.ErrSavedQueryExists):
+		writeProblem(w, r, problem.New(http.StatusConflict, problem.CodeAlreadyExists, err.Error()))
+	default:
+		writeRepositoryError(w, r, "failed to process report", err)
+	}
+}
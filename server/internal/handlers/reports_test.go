@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/repository"
+)
+
+// studentRows answers every query with one student's ID and email.
+type studentRows struct {
+	repository.ConsoleStore
+}
+
+func (studentRows) QueryReadOnly(_ context.Context, _ string, _ []any, _ int, _ time.Duration, onColumns func([]string) error, onRow func([]any) error) (int, bool, error) {
+	if err := onColumns([]string{"Student_ID", "Student_Email"}); err != nil {
+		return 0, false, err
+	}
+	return 1, false, onRow([]any{int64(1), "james.white@example.com"})
+}
+
+func TestRunReportMasksPersonalColumns(t *testing.T) {
+	h := newMemoryHandler()
+	h.ConsoleRepo = studentRows{}
+
+	report := &models.SavedQuery{Slug: "student-contacts", Title: "Student contacts", SQL: "SELECT Student_ID, Email AS Student_Email FROM student", Parameters: []models.ReportParameter{}, RequiredRole: models.RoleViewer}
+	if err := h.SavedQueryRepo.Create(context.Background(), report); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	run := func(role models.Role, format string) string {
+		t.Helper()
+
+		r := httptest.NewRequest(http.MethodGet, "/api/reports/student-contacts/run?format="+format, nil)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("slug", report.Slug)
+		ctx := context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx)
+		ctx = context.WithValue(ctx, userContextKey, &models.User{ID: 2, Role: role})
+
+		rec := serve(h.RunReport, r.WithContext(ctx))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected the report to run, got %d: %s", role, rec.Code, rec.Body)
+		}
+		return rec.Body.String()
+	}
+
+	for _, format := range []string{"json", "csv"} {
+		if body := run(models.RoleViewer, format); !strings.Contains(body, "j***@example.com") || strings.Contains(body, "james.white") {
+			t.Fatalf("expected a viewer to get a masked email as %s, got %s", format, body)
+		}
+		if body := run(models.RoleLibrarian, format); !strings.Contains(body, "james.white@example.com") {
+			t.Fatalf("expected a librarian to get the full email as %s, got %s", format, body)
+		}
+	}
+}
@@ -5,9 +5,9 @@ import "net/http"
 func (h *Handler) GetStaff(w http.ResponseWriter, r *http.Request) {
 	staff, err := h.StaffRepo.GetAll(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch staff")
+		writeServerError(w, r, "failed to fetch staff", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, staff)
+	writeJSON(w, r, http.StatusOK, staff)
 }
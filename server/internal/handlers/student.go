@@ -2,39 +2,86 @@ package handlers
 
 import (
 	"database/sql"
-	"encoding/json"
 	"net/http"
-	"net/mail"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/karanm6505/dbms/server/internal/library"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
-type errorResponse struct {
-	Error string `json:"error"`
+type createStudentRequest struct {
+	FirstName string `json:"first_name" validate:"required,max=50"`
+	LastName  string `json:"last_name" validate:"required,max=50"`
+	Email     string `json:"email" validate:"required,max=100,email"`
+	Status    string `json:"status" validate:"oneof=Active|Inactive"`
 }
 
-type createStudentRequest struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Status    string `json:"status"`
+// updateStudentRequest replaces a student with PUT. Reason and
+// EffectiveDate describe a status change; Override lets an admin deactivate
+// a student who still has open loans or unpaid fines.
+type updateStudentRequest struct {
+	FirstName     string `json:"first_name" validate:"required,max=50"`
+	LastName      string `json:"last_name" validate:"required,max=50"`
+	Email         string `json:"email" validate:"required,max=100,email"`
+	Status        string `json:"status" validate:"required,oneof=Active|Suspended|Inactive|Graduated"`
+	Reason        string `json:"reason" validate:"max=255"`
+	EffectiveDate string `json:"effective_date" validate:"date"`
+	Override      bool   `json:"override"`
+}
+
+// patchStudentRequest is updateStudentRequest for PATCH, where empty fields
+// keep their current value.
+type patchStudentRequest struct {
+	FirstName     string `json:"first_name" validate:"max=50"`
+	LastName      string `json:"last_name" validate:"max=50"`
+	Email         string `json:"email" validate:"max=100,email"`
+	Status        string `json:"status" validate:"oneof=Active|Suspended|Inactive|Graduated"`
+	Reason        string `json:"reason" validate:"max=255"`
+	EffectiveDate string `json:"effective_date" validate:"date"`
+	Override      bool   `json:"override"`
 }
 
+// GetStudents lists every student for staff and only the linked student for
+// a patron.
 func (h *Handler) GetStudents(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
 	students, err := h.StudentRepo.GetAll(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to fetch students")
+		writeServerError(w, r, "failed to fetch students", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, students)
+	if !user.IsStaff() {
+		visible := make([]models.Student, 0, 1)
+		for _, student := range students {
+			if canReadStudent(user, student.ID) {
+				visible = append(visible, student)
+			}
+		}
+		students = visible
+	}
+
+	writeJSON(w, r, http.StatusOK, students)
 }
 
 func (h *Handler) GetStudentByID(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
 	idParam := chi.URLParam(r, "id")
 	studentID, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -42,42 +89,112 @@ func (h *Handler) GetStudentByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Other students are hidden from a patron rather than forbidden, so it
+	// cannot probe which IDs exist.
+	if !canReadStudent(user, studentID) {
+		writeError(w, http.StatusNotFound, "student not found")
+		return
+	}
+
 	student, err := h.StudentRepo.GetByID(r.Context(), studentID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			writeError(w, http.StatusNotFound, "student not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "failed to fetch student")
+		writeServerError(w, r, "failed to fetch student", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, student)
+	writeJSON(w, r, http.StatusOK, student)
 }
 
-func (h *Handler) CreateStudent(w http.ResponseWriter, r *http.Request) {
-	if _, ok := h.requireAdmin(w, r); !ok {
+// The borrowing history in a student profile is paged, defaulting to
+// defaultProfilePageSize loans per page.
+const (
+	defaultProfilePageSize = 20
+	maxProfilePageSize     = 100
+)
+
+// GetStudentProfile returns the sections of a student's profile listed in
+// ?fields= (all of them when it is absent), so a client needs one request
+// rather than one per section. ?page= and ?page_size= page the borrowing
+// history.
+func (h *Handler) GetStudentProfile(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
 		return
 	}
 
-	var req createStudentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid JSON body")
+	studentID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid student id")
 		return
 	}
 
-	req.FirstName = strings.TrimSpace(req.FirstName)
-	req.LastName = strings.TrimSpace(req.LastName)
-	req.Email = strings.TrimSpace(req.Email)
-	req.Status = strings.TrimSpace(req.Status)
+	if !canReadStudent(user, studentID) {
+		writeError(w, http.StatusNotFound, "student not found")
+		return
+	}
 
-	if req.FirstName == "" || req.LastName == "" || req.Email == "" {
-		writeError(w, http.StatusBadRequest, "first_name, last_name, and email are required")
+	query, fields := parseProfileQuery(r.URL.Query())
+	if len(fields) > 0 {
+		writeValidationError(w, r, fields)
 		return
 	}
 
-	if _, err := mail.ParseAddress(req.Email); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid email address")
+	profile, err := h.StudentRepo.GetProfile(r.Context(), studentID, query)
+	if err != nil {
+		writeRepositoryError(w, r, "failed to fetch student profile", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, profile)
+}
+
+func parseProfileQuery(values url.Values) (models.ProfileQuery, []models.FieldError) {
+	query := models.ProfileQuery{Fields: models.ProfileSections, Page: 1, PageSize: defaultProfilePageSize}
+	fields := make([]models.FieldError, 0)
+
+	if raw := strings.TrimSpace(values.Get("fields")); raw != "" {
+		query.Fields = nil
+		for _, field := range strings.Split(raw, ",") {
+			field = strings.ToLower(strings.TrimSpace(field))
+			if !slices.Contains(models.ProfileSections, field) {
+				fields = append(fields, models.FieldError{Field: "fields", Code: "oneof", Message: "fields must be a comma-separated list of " + strings.Join(models.ProfileSections, ", ")})
+				break
+			}
+			query.Fields = append(query.Fields, field)
+		}
+	}
+
+	if raw := values.Get("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			fields = append(fields, models.FieldError{Field: "page", Code: "min", Message: "page must be a whole number of at least 1"})
+		}
+		query.Page = page
+	}
+
+	if raw := values.Get("page_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 || size > maxProfilePageSize {
+			fields = append(fields, models.FieldError{Field: "page_size", Code: "max", Message: "page_size must be between 1 and " + strconv.Itoa(maxProfilePageSize)})
+		}
+		query.PageSize = size
+	}
+
+	return query, fields
+}
+
+func (h *Handler) CreateStudent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req createStudentRequest
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -93,9 +210,102 @@ func (h *Handler) CreateStudent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.StudentRepo.Create(r.Context(), student); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to create student")
+		writeRepositoryError(w, r, "failed to create student", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, student)
+}
+
+func (h *Handler) UpdateStudent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req updateStudentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	h.saveStudent(w, r, patchStudentRequest(req))
+}
+
+func (h *Handler) PatchStudent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req patchStudentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	h.saveStudent(w, r, req)
+}
+
+// saveStudent applies change to the stored student. The store decides
+// whether a status change is allowed; the reason and effective date are only
+// accepted alongside one.
+func (h *Handler) saveStudent(w http.ResponseWriter, r *http.Request, change patchStudentRequest) {
+	studentID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid student id")
+		return
+	}
+
+	student, err := h.StudentRepo.GetByID(r.Context(), studentID)
+	if err != nil {
+		writeRepositoryError(w, r, "failed to fetch student", err)
+		return
+	}
+
+	if change.FirstName != "" {
+		student.FirstName = change.FirstName
+	}
+	if change.LastName != "" {
+		student.LastName = change.LastName
+	}
+	if change.Email != "" {
+		student.Email = change.Email
+	}
+
+	current, _ := library.NormalizeStudentStatus(student.Status)
+	today := time.Now().Format(time.DateOnly)
+	fields := make([]models.FieldError, 0)
+
+	if change.Status != "" && change.Status != current {
+		if change.Reason == "" {
+			fields = append(fields, models.FieldError{Field: "reason", Code: "required", Message: "reason is required when the status changes"})
+		}
+		if change.EffectiveDate == "" {
+			change.EffectiveDate = today
+		}
+		// Dates are YYYY-MM-DD, so they compare as strings.
+		if change.EffectiveDate > today {
+			fields = append(fields, models.FieldError{Field: "effective_date", Code: "future", Message: "effective_date must not be in the future"})
+		}
+
+		student.Status = change.Status
+		student.StatusReason = change.Reason
+		student.StatusEffectiveDate = change.EffectiveDate
+	} else {
+		if change.Reason != "" {
+			fields = append(fields, models.FieldError{Field: "reason", Code: "status_unchanged", Message: "reason only applies to a status change"})
+		}
+		if change.EffectiveDate != "" {
+			fields = append(fields, models.FieldError{Field: "effective_date", Code: "status_unchanged", Message: "effective_date only applies to a status change"})
+		}
+	}
+
+	if len(fields) > 0 {
+		writeValidationError(w, r, fields)
+		return
+	}
+
+	if err := h.StudentRepo.Update(r.Context(), student, change.Override); err != nil {
+		writeRepositoryError(w, r, "failed to update student", err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, student)
+	writeJSON(w, r, http.StatusOK, student)
 }
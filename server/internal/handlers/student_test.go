@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+)
+
+func sendStudent(h *Handler, method, id, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/api/students/"+id, strings.NewReader(body))
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+
+	rec := httptest.NewRecorder()
+	if method == http.MethodPut {
+		h.UpdateStudent(rec, asAdmin(r))
+	} else {
+		h.PatchStudent(rec, asAdmin(r))
+	}
+	return rec
+}
+
+func TestPatchStudentSuspensionBlocksCheckout(t *testing.T) {
+	h := newMemoryHandler()
+
+	rec := sendStudent(h, http.MethodPatch, "9", `{"status":"Suspended","reason":"library rules","effective_date":"2025-01-15"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var student models.Student
+	if err := json.Unmarshal(rec.Body.Bytes(), &student); err != nil {
+		t.Fatal(err)
+	}
+	if student.Status != "Suspended" || student.StatusReason != "library rules" || student.StatusEffectiveDate != "2025-01-15" || student.FirstName != "James" {
+		t.Fatalf("unexpected student %+v", student)
+	}
+
+	rec = postCheckout(h, `{"student_id":9,"book_id":1,"staff_id":1}`)
+	if p := decodeProblem(t, rec); rec.Code != http.StatusConflict || p.Code != problem.CodeStudentIneligible {
+		t.Fatalf("expected 409 %s, got %d %s", problem.CodeStudentIneligible, rec.Code, p.Code)
+	}
+}
+
+func TestUpdateStudentRejectsInvalidTransition(t *testing.T) {
+	h := newMemoryHandler()
+
+	body := `{"first_name":"James","last_name":"White","email":"james.white@example.com","status":"Graduated","reason":"finished"}`
+	if rec := sendStudent(h, http.MethodPut, "9", body); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec := sendStudent(h, http.MethodPatch, "9", `{"status":"Active","reason":"came back"}`)
+	if p := decodeProblem(t, rec); rec.Code != http.StatusConflict || p.Code != problem.CodeInvalidTransition {
+		t.Fatalf("expected 409 %s, got %d %s", problem.CodeInvalidTransition, rec.Code, p.Code)
+	}
+}
+
+func TestPatchStudentDeactivationNeedsOverrideWithOpenLoans(t *testing.T) {
+	h := newMemoryHandler()
+
+	// Student 3 holds three books in the sample data.
+	rec := sendStudent(h, http.MethodPatch, "3", `{"status":"Inactive","reason":"left"}`)
+	if p := decodeProblem(t, rec); rec.Code != http.StatusConflict || p.Code != problem.CodeOutstandingObligations {
+		t.Fatalf("expected 409 %s, got %d %s", problem.CodeOutstandingObligations, rec.Code, p.Code)
+	}
+
+	if rec := sendStudent(h, http.MethodPatch, "3", `{"status":"Inactive","reason":"left","override":true}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected override to succeed, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPatchStudentValidatesStatusChange(t *testing.T) {
+	h := newMemoryHandler()
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"missing reason", `{"status":"Suspended"}`, "reason"},
+		{"future date", `{"status":"Suspended","reason":"x","effective_date":"2999-01-01"}`, "effective_date"},
+		{"reason without change", `{"email":"new@example.com","reason":"x"}`, "reason"},
+		{"unknown status", `{"status":"Expelled","reason":"x"}`, "status"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := sendStudent(h, http.MethodPatch, "1", tc.body)
+			p := decodeProblem(t, rec)
+			if rec.Code != http.StatusBadRequest || len(p.Errors) == 0 || p.Errors[0].Field != tc.field {
+				t.Fatalf("expected a %s field error, got %d %+v", tc.field, rec.Code, p.Errors)
+			}
+		})
+	}
+}
+
+func TestPatchStudentUnknownID(t *testing.T) {
+	h := newMemoryHandler()
+
+	if rec := sendStudent(h, http.MethodPatch, "999", `{"first_name":"Nobody"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func getStudentProfile(h *Handler, id, query string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/api/students/"+id+"/profile?"+query, nil)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+
+	rec := httptest.NewRecorder()
+	h.GetStudentProfile(rec, asAdmin(r))
+	return rec
+}
+
+func TestGetStudentProfileSelectsFields(t *testing.T) {
+	h := newMemoryHandler()
+
+	rec := getStudentProfile(h, "3", "fields=loans,history,borrowed_count&page=2&page_size=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != 3 {
+		t.Fatalf("expected only the selected sections, got %s", rec.Body)
+	}
+
+	var profile models.StudentProfile
+	if err := json.Unmarshal(rec.Body.Bytes(), &profile); err != nil {
+		t.Fatal(err)
+	}
+	// Student 3 holds three books in the sample data.
+	if len(*profile.Loans) != 3 || *profile.BorrowedCount != 3 {
+		t.Fatalf("unexpected loans %+v", profile)
+	}
+	if profile.History.Total != 3 || profile.History.Page != 2 || len(profile.History.Items) != 1 || profile.History.Items[0].ID != 2 {
+		t.Fatalf("unexpected history %+v", profile.History)
+	}
+
+	rec = getStudentProfile(h, "6", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || len(body) != len(models.ProfileSections) || string(body["loans"]) != "[]" {
+		t.Fatalf("expected every section by default, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestGetStudentProfileValidatesQuery(t *testing.T) {
+	h := newMemoryHandler()
+
+	tests := []struct {
+		query string
+		field string
+	}{
+		{"fields=loans,grades", "fields"},
+		{"page=0", "page"},
+		{"page_size=500", "page_size"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			rec := getStudentProfile(h, "1", tc.query)
+			p := decodeProblem(t, rec)
+			if rec.Code != http.StatusBadRequest || len(p.Errors) == 0 || p.Errors[0].Field != tc.field {
+				t.Fatalf("expected a %s field error, got %d %+v", tc.field, rec.Code, p.Errors)
+			}
+		})
+	}
+
+	if rec := getStudentProfile(h, "999", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestStudentEmailsMaskedForViewers(t *testing.T) {
+	h := newMemoryHandler()
+
+	as := func(role models.Role, r *http.Request) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), userContextKey, &models.User{ID: 2, Role: role}))
+	}
+
+	for role, masked := range map[models.Role]bool{models.RoleViewer: true, models.RoleLibrarian: false, models.RoleAdmin: false} {
+		rec := serve(h.GetStudents, as(role, httptest.NewRequest(http.MethodGet, "/api/students", nil)))
+		var students []models.Student
+		if err := json.Unmarshal(rec.Body.Bytes(), &students); err != nil {
+			t.Fatal(err)
+		}
+		if len(students) == 0 {
+			t.Fatalf("%s: expected students", role)
+		}
+		for _, student := range students {
+			if got := strings.Contains(student.Email, "***@"); got != masked {
+				t.Fatalf("%s: expected masked=%v, got email %q", role, masked, student.Email)
+			}
+		}
+
+		rec = serve(h.GetStaff, as(role, httptest.NewRequest(http.MethodGet, "/api/staff", nil)))
+		var staff []map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &staff); err != nil {
+			t.Fatal(err)
+		}
+		if _, hasStatus := staff[0]["status"]; hasStatus == masked {
+			t.Fatalf("%s: expected status present=%v, got %v", role, !masked, staff[0])
+		}
+	}
+}
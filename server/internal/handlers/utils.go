@@ -2,15 +2,86 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/problem"
+	"github.com/karanm6505/dbms/server/internal/redact"
+	"github.com/karanm6505/dbms/server/internal/validate"
 )
 
-func writeJSON(w http.ResponseWriter, status int, data any) {
+// writeJSON encodes data after applying the field policy of the signed-in
+// user's role, so personal details are masked the same way in every
+// response.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, data any) {
+	var role models.Role
+	if user, ok := r.Context().Value(userContextKey).(*models.User); ok {
+		role = user.Role
+	}
+	data = redact.For(role).Apply(data)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
 }
 
+// writeError responds with a problem document whose code is derived from the
+// status. Use writeProblem when a more specific code applies.
 func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, errorResponse{Error: message})
+	writeProblem(w, nil, problem.ForStatus(status, message))
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, p *problem.Problem) {
+	problem.Write(w, r, p)
+}
+
+func writeValidationError(w http.ResponseWriter, r *http.Request, errors []models.FieldError) {
+	writeProblem(w, r, problem.Validation(errors))
+}
+
+// decodeRequest decodes and validates a JSON body into dst. When the body is
+// rejected the problem has already been written and it returns false.
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if err := validate.Decode(w, r, dst, validate.DefaultMaxBodyBytes); err != nil {
+		writeDecodeError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *validate.Error
+	switch {
+	case errors.As(err, &validationErr):
+		writeValidationError(w, r, validationErr.Fields)
+	case errors.Is(err, validate.ErrBodyTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+	case errors.Is(err, validate.ErrEmptyBody):
+		writeValidationError(w, r, []models.FieldError{{Field: "body", Code: "required", Message: "request body is required"}})
+	default:
+		writeError(w, http.StatusBadRequest, "invalid request body")
+	}
+}
+
+func writeText(w http.ResponseWriter, status int, contentType string, body string) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+// writeServerError responds with a 500 and records the underlying error next
+// to the client-facing message in the request's access log entry.
+func writeServerError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	logging.RecordError(r.Context(), message, err)
+	writeProblem(w, r, problem.ForStatus(http.StatusInternalServerError, message))
+}
+
+// writeRepositoryError translates a database error into its problem code, so
+// trigger signals and constraint violations reach the client as something it
+// can act on. Unrecognised errors become a 500 carrying message.
+func writeRepositoryError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	logging.RecordError(r.Context(), message, err)
+	writeProblem(w, r, problem.FromError(err, message))
 }
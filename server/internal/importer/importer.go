@@ -0,0 +1,322 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/library"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/validate"
+)
+
+// BatchSize is how many rows are saved per transaction.
+const BatchSize = 100
+
+// Kinds of import.
+const (
+	KindBooks    = "books"
+	KindStudents = "students"
+)
+
+// Mapping names the file column each field is read from. Fields it leaves
+// out are read from the column with the field's name, ignoring case, spaces
+// and dashes.
+type Mapping map[string]string
+
+// bookRow and studentRow declare the fields of each kind and their rules;
+// the rules match the column sizes in ddl_dml.sql. Imported books are always
+// available, since they have no loans yet.
+type bookRow struct {
+	Title         string `json:"title" validate:"required,max=100"`
+	Author        string `json:"author" validate:"required,max=100"`
+	Publisher     string `json:"publisher" validate:"max=100"`
+	YearPublished int    `json:"year_published" validate:"required,year"`
+	Genre         string `json:"genre" validate:"max=50"`
+}
+
+type studentRow struct {
+	FirstName string `json:"first_name" validate:"required,max=50"`
+	LastName  string `json:"last_name" validate:"required,max=50"`
+	Email     string `json:"email" validate:"required,max=100,email"`
+	Status    string `json:"status" validate:"oneof=Active|Inactive"`
+}
+
+// Save writes one batch, returning the indexes of rows skipped as
+// duplicates. It is a BookStore or StudentStore Import method.
+type Save[T any] func(ctx context.Context, batch []T, dryRun bool) ([]int, error)
+
+// Plan is a parsed file ready to be imported: the rows that passed
+// validation and the errors of those that did not.
+type Plan[T any] struct {
+	Columns map[string]string
+	Rows    []Row[T]
+	Errors  []models.ImportRowError
+	// duplicate describes a row skipped because the record exists.
+	duplicate models.FieldError
+	// repeats counts the rows repeating an earlier row of the file.
+	repeats int
+}
+
+type Row[T any] struct {
+	Line  int
+	Value T
+}
+
+// PlanBooks validates every row of a book file. Rows repeating an earlier
+// row's title and author are rejected as duplicates.
+func PlanBooks(table *Table, mapping Mapping) (*Plan[models.Book], []models.FieldError) {
+	columns, problems := resolve[bookRow](table.Header, mapping)
+	if problems != nil {
+		return nil, problems
+	}
+
+	plan := &Plan[models.Book]{
+		Columns:   columnNames(table.Header, columns),
+		duplicate: models.FieldError{Field: "title", Code: "duplicate", Message: "a book with this title and author already exists"},
+	}
+	seen := make(map[string]int)
+	for _, record := range table.Rows {
+		row, ok := plan.parse(record, columns, new(bookRow))
+		if !ok {
+			continue
+		}
+
+		book := row.(*bookRow)
+		key := strings.ToLower(book.Title) + "\x00" + strings.ToLower(book.Author)
+		if plan.repeated(record.Line, seen, key) {
+			continue
+		}
+		plan.Rows = append(plan.Rows, Row[models.Book]{Line: record.Line, Value: models.Book{
+			Title:         book.Title,
+			Author:        book.Author,
+			Publisher:     book.Publisher,
+			YearPublished: book.YearPublished,
+			Genre:         book.Genre,
+			Status:        library.BookAvailable,
+		}})
+	}
+	return plan, nil
+}
+
+// PlanStudents validates every row of a student file. Rows repeating an
+// earlier row's email address are rejected as duplicates.
+func PlanStudents(table *Table, mapping Mapping) (*Plan[models.Student], []models.FieldError) {
+	columns, problems := resolve[studentRow](table.Header, mapping)
+	if problems != nil {
+		return nil, problems
+	}
+
+	plan := &Plan[models.Student]{
+		Columns:   columnNames(table.Header, columns),
+		duplicate: models.FieldError{Field: "email", Code: "duplicate", Message: "a student with this email address already exists"},
+	}
+	seen := make(map[string]int)
+	for _, record := range table.Rows {
+		row, ok := plan.parse(record, columns, new(studentRow))
+		if !ok {
+			continue
+		}
+
+		student := row.(*studentRow)
+		if plan.repeated(record.Line, seen, strings.ToLower(student.Email)) {
+			continue
+		}
+		if student.Status == "" {
+			student.Status = library.StudentActive
+		}
+		plan.Rows = append(plan.Rows, Row[models.Student]{Line: record.Line, Value: models.Student{
+			FirstName: student.FirstName,
+			LastName:  student.LastName,
+			Email:     student.Email,
+			Status:    student.Status,
+		}})
+	}
+	return plan, nil
+}
+
+// parse fills dst, a pointer to a row struct, from the record's mapped
+// columns and validates it. Failures are added to the plan's errors.
+func (p *Plan[T]) parse(record Record, columns map[string]int, dst any) (any, bool) {
+	value := reflect.ValueOf(dst).Elem()
+	failures := make([]models.FieldError, 0)
+
+	for i := 0; i < value.NumField(); i++ {
+		name := fieldName(value.Type().Field(i))
+		column, ok := columns[name]
+		if !ok || column >= len(record.Values) {
+			continue
+		}
+
+		cell := record.Values[column]
+		field := value.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(cell)
+		case reflect.Int:
+			if cell == "" {
+				continue
+			}
+			// Spreadsheets store whole numbers as "2019" or "2019.0".
+			n, err := strconv.ParseFloat(cell, 64)
+			if err != nil || n != float64(int64(n)) {
+				failures = append(failures, models.FieldError{Field: name, Code: "integer", Message: name + " must be a whole number"})
+				continue
+			}
+			field.SetInt(int64(n))
+		}
+	}
+
+	var invalid *validate.Error
+	if err := validate.Struct(dst); errors.As(err, &invalid) {
+		failures = append(failures, invalid.Fields...)
+	}
+	if len(failures) > 0 {
+		for _, failure := range dedupeFields(failures) {
+			p.Errors = append(p.Errors, models.ImportRowError{Row: record.Line, FieldError: failure})
+		}
+		return nil, false
+	}
+	return dst, true
+}
+
+// repeated reports whether an earlier row of the file had the same key, and
+// rejects the row if so.
+func (p *Plan[T]) repeated(line int, seen map[string]int, key string) bool {
+	first, ok := seen[key]
+	if !ok {
+		seen[key] = line
+		return false
+	}
+
+	failure := p.duplicate
+	failure.Message = fmt.Sprintf("repeats row %d", first)
+	p.Errors = append(p.Errors, models.ImportRowError{Row: line, FieldError: failure})
+	p.repeats++
+	return true
+}
+
+// Run saves the plan's rows in batches of BatchSize, each in its own
+// transaction, recording progress on job. It stops at the first batch that
+// fails; earlier batches stay saved.
+func (p *Plan[T]) Run(ctx context.Context, job *Job, save Save[T]) error {
+	job.update(func(state *models.ImportJob) {
+		state.Errors = append(state.Errors, p.Errors...)
+		state.Invalid = len(p.Errors) - p.repeats
+		state.Duplicates = p.repeats
+		state.Processed = len(p.Errors)
+	})
+
+	for start := 0; start < len(p.Rows); start += BatchSize {
+		rows := p.Rows[start:min(start+BatchSize, len(p.Rows))]
+		batch := make([]T, len(rows))
+		for i, row := range rows {
+			batch[i] = row.Value
+		}
+
+		duplicates, err := save(ctx, batch, job.DryRun())
+		if err != nil {
+			job.update(func(state *models.ImportJob) {
+				state.Error = fmt.Sprintf("rows %d to %d could not be saved; %d earlier rows were imported", rows[0].Line, rows[len(rows)-1].Line, state.Imported)
+			})
+			return err
+		}
+
+		job.update(func(state *models.ImportJob) {
+			for _, i := range duplicates {
+				state.Errors = append(state.Errors, models.ImportRowError{Row: rows[i].Line, FieldError: p.duplicate})
+			}
+			state.Duplicates += len(duplicates)
+			state.Imported += len(rows) - len(duplicates)
+			state.Processed += len(rows)
+		})
+	}
+	return nil
+}
+
+// resolve finds the column of every field of the row struct R. Unknown
+// fields or columns in the mapping, and required fields without a column,
+// are reported against "mapping".
+func resolve[R any](header []string, mapping Mapping) (map[string]int, []models.FieldError) {
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, ok := byName[normalize(name)]; !ok {
+			byName[normalize(name)] = i
+		}
+	}
+
+	rowType := reflect.TypeOf((*R)(nil)).Elem()
+	fields := make(map[string]bool, rowType.NumField())
+	columns := make(map[string]int, rowType.NumField())
+	problems := make([]models.FieldError, 0)
+
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		name := fieldName(field)
+		fields[name] = true
+
+		column, mapped := mapping[name]
+		if !mapped {
+			column = name
+		}
+		if index, ok := byName[normalize(column)]; ok {
+			columns[name] = index
+			continue
+		}
+
+		switch {
+		case mapped:
+			problems = append(problems, models.FieldError{Field: "mapping", Code: "unknown_column", Message: fmt.Sprintf("%s is mapped to %q, which is not a column of the file", name, column)})
+		case strings.Contains(field.Tag.Get("validate"), "required"):
+			problems = append(problems, models.FieldError{Field: "mapping", Code: "required", Message: fmt.Sprintf("no column for required field %s", name)})
+		}
+	}
+
+	for name := range mapping {
+		if !fields[name] {
+			problems = append(problems, models.FieldError{Field: "mapping", Code: "unknown_field", Message: fmt.Sprintf("%s is not a field that can be imported", name)})
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return columns, nil
+}
+
+// normalize lets "Year Published", "year-published" and "year_published"
+// name the same column.
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+func columnNames(header []string, columns map[string]int) map[string]string {
+	names := make(map[string]string, len(columns))
+	for field, index := range columns {
+		names[field] = header[index]
+	}
+	return names
+}
+
+// dedupeFields keeps the first failure of each field; a cell that is not a
+// number also fails validation as missing.
+func dedupeFields(failures []models.FieldError) []models.FieldError {
+	seen := make(map[string]bool, len(failures))
+	kept := failures[:0]
+	for _, failure := range failures {
+		if !seen[failure.Field] {
+			seen[failure.Field] = true
+			kept = append(kept, failure)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// xlsxFile builds a workbook whose first sheet has a shared-string header,
+// an inline-string cell and a number, with column B left empty.
+func xlsxFile(t *testing.T) []byte {
+	t.Helper()
+
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Books" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId7" Type="worksheet" Target="worksheets/books.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>Book Title</t></si><si><r><t>Wri</t></r><r><t>ter</t></r></si><si><t>Year Published</t></si></sst>`,
+		"xl/worksheets/books.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c><c r="D1" t="s"><v>2</v></c></row>
+			<row r="3"><c r="A3" t="inlineStr"><is><t>Dune</t></is></c><c r="C3" t="str"><v>Frank Herbert</v></c><c r="D3"><v>1965</v></c></row>
+		</sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	table, err := Read("books.xlsx", xlsxFile(t))
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	if got := table.Header; len(got) != 4 || got[0] != "Book Title" || got[1] != "" || got[2] != "Writer" {
+		t.Fatalf("unexpected header %q", got)
+	}
+	if len(table.Rows) != 1 || table.Rows[0].Line != 3 {
+		t.Fatalf("expected one row on line 3, got %+v", table.Rows)
+	}
+	if got := table.Rows[0].Values; got[0] != "Dune" || got[2] != "Frank Herbert" || got[3] != "1965" {
+		t.Fatalf("unexpected values %q", got)
+	}
+
+	plan, problems := PlanBooks(table, Mapping{"title": "Book Title", "author": "Writer"})
+	if problems != nil {
+		t.Fatalf("PlanBooks returned %+v", problems)
+	}
+	if len(plan.Rows) != 1 || plan.Rows[0].Value.YearPublished != 1965 || plan.Rows[0].Value.Status != "Available" {
+		t.Fatalf("unexpected plan %+v", plan.Rows)
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want error
+	}{
+		{"unknown extension", "books.pdf", "title\nDune\n", ErrUnsupportedFormat},
+		{"empty", "books.csv", "\n , \n", ErrNoHeader},
+		{"header only", "books.csv", "title,author\n", ErrNoRows},
+		{"broken zip", "books.xlsx", "PK\x03\x04", errInvalidXLSX},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(tt.file, []byte(tt.data)); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPlanStudents(t *testing.T) {
+	csv := "\xef\xbb\xbfFirst Name,Last-Name,E-mail,Status\n" +
+		"Ada,Lovelace,ada@example.com,\n" +
+		"\n" +
+		"Alan,Turing,not-an-email,Active\n" +
+		"Grace,Hopper,grace@example.com,Retired\n" +
+		"Ada,Byron,ADA@example.com,Active\n"
+
+	table, err := Read("students.csv", []byte(csv))
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	plan, problems := PlanStudents(table, Mapping{"email": "E-mail"})
+	if problems != nil {
+		t.Fatalf("PlanStudents returned %+v", problems)
+	}
+
+	if len(plan.Rows) != 1 || plan.Rows[0].Line != 2 || plan.Rows[0].Value.Status != "Active" {
+		t.Fatalf("expected only row 2 to pass, got %+v", plan.Rows)
+	}
+
+	want := []models.ImportRowError{
+		{Row: 4, FieldError: models.FieldError{Field: "email", Code: "email"}},
+		{Row: 5, FieldError: models.FieldError{Field: "status", Code: "oneof"}},
+		{Row: 6, FieldError: models.FieldError{Field: "email", Code: "duplicate"}},
+	}
+	if len(plan.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), plan.Errors)
+	}
+	for i, got := range plan.Errors {
+		if got.Row != want[i].Row || got.Field != want[i].Field || got.Code != want[i].Code {
+			t.Errorf("error %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+}
+
+func TestPlanRejectsBadMapping(t *testing.T) {
+	table := &Table{Header: []string{"Name", "Writer"}, Rows: []Record{{Line: 2, Values: []string{"Dune", "Frank Herbert"}}}}
+
+	_, problems := PlanBooks(table, Mapping{"title": "Title", "isbn": "ISBN"})
+
+	codes := make(map[string]bool)
+	for _, problem := range problems {
+		codes[problem.Code] = true
+	}
+	// title is mapped to a missing column, isbn is not a field, and author
+	// and year_published have no column.
+	if len(problems) != 4 || !codes["unknown_column"] || !codes["unknown_field"] || !codes["required"] {
+		t.Fatalf("unexpected problems %+v", problems)
+	}
+}
+
+func TestRunSavesInBatches(t *testing.T) {
+	plan := &Plan[int]{duplicate: models.FieldError{Field: "email", Code: "duplicate"}}
+	for i := 0; i < BatchSize*2+5; i++ {
+		plan.Rows = append(plan.Rows, Row[int]{Line: i + 2, Value: i})
+	}
+
+	var batches []int
+	save := func(_ context.Context, batch []int, dryRun bool) ([]int, error) {
+		batches = append(batches, len(batch))
+		if len(batches) == 3 {
+			return nil, errors.New("connection lost")
+		}
+		return []int{0}, nil
+	}
+
+	job := NewJobs().Start(context.Background(), KindStudents, false, nil, len(plan.Rows), func(ctx context.Context, job *Job) error {
+		return plan.Run(ctx, job, save)
+	})
+	<-job.Done()
+
+	state := job.Snapshot()
+	if len(batches) != 3 || batches[0] != BatchSize || batches[2] != 5 {
+		t.Fatalf("unexpected batches %v", batches)
+	}
+	if state.Status != models.ImportFailed || state.Imported != 2*(BatchSize-1) || state.Duplicates != 2 || state.Processed != 2*BatchSize {
+		t.Fatalf("unexpected job %+v", state)
+	}
+	if state.Error == "" || state.FinishedAt == nil {
+		t.Fatalf("expected the failure to be reported, got %+v", state)
+	}
+}
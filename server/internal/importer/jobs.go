@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// jobRetention is how long a finished job can still be polled.
+const jobRetention = time.Hour
+
+// Job is one import running in the background. Its methods are safe for
+// concurrent use.
+type Job struct {
+	mu    sync.Mutex
+	state models.ImportJob
+	done  chan struct{}
+}
+
+// Snapshot returns the job's current state.
+func (j *Job) Snapshot() models.ImportJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	state := j.state
+	state.Errors = slices.Clone(j.state.Errors)
+	return state
+}
+
+// Done is closed when the job stops.
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+func (j *Job) DryRun() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state.DryRun
+}
+
+func (j *Job) update(change func(state *models.ImportJob)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	change(&j.state)
+}
+
+// Jobs keeps the imports started by this process. Nothing is persisted, so
+// jobs are lost on restart.
+type Jobs struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+	now  func() time.Time
+}
+
+func NewJobs() *Jobs {
+	return &Jobs{jobs: make(map[string]*Job), now: time.Now}
+}
+
+// Start runs work in the background as a new job. work reports its progress
+// on the job; the error it returns fails the job. Finished jobs older than
+// an hour are dropped.
+func (js *Jobs) Start(ctx context.Context, kind string, dryRun bool, columns map[string]string, total int, work func(ctx context.Context, job *Job) error) *Job {
+	job := &Job{
+		state: models.ImportJob{
+			ID:        newJobID(),
+			Kind:      kind,
+			DryRun:    dryRun,
+			Status:    models.ImportQueued,
+			Columns:   columns,
+			Total:     total,
+			Errors:    make([]models.ImportRowError, 0),
+			CreatedAt: js.now().UTC(),
+		},
+		done: make(chan struct{}),
+	}
+
+	js.mu.Lock()
+	for id, old := range js.jobs {
+		if state := old.Snapshot(); state.FinishedAt != nil && js.now().Sub(*state.FinishedAt) > jobRetention {
+			delete(js.jobs, id)
+		}
+	}
+	js.jobs[job.state.ID] = job
+	js.mu.Unlock()
+
+	go func() {
+		defer close(job.done)
+
+		job.update(func(state *models.ImportJob) { state.Status = models.ImportRunning })
+		err := work(ctx, job)
+
+		job.update(func(state *models.ImportJob) {
+			slices.SortStableFunc(state.Errors, func(a, b models.ImportRowError) int { return a.Row - b.Row })
+			finished := js.now().UTC()
+			state.FinishedAt = &finished
+			state.Status = models.ImportCompleted
+			if err != nil {
+				state.Status = models.ImportFailed
+				if state.Error == "" {
+					state.Error = "import failed"
+				}
+			}
+		})
+	}()
+
+	return job
+}
+
+// Get returns a job by ID.
+func (js *Jobs) Get(id string) (*Job, bool) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	job, ok := js.jobs[id]
+	return job, ok
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
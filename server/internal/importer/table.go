@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("file must be CSV or XLSX")
+	ErrNoRows            = errors.New("file has a header but no rows")
+	ErrNoHeader          = errors.New("file is empty")
+)
+
+// Table is a file's header and the rows below it.
+type Table struct {
+	Header []string
+	Rows   []Record
+}
+
+// Record is one row of a file. Line counts the header as line 1, matching
+// the row numbers a spreadsheet shows.
+type Record struct {
+	Line   int
+	Values []string
+}
+
+// Read parses a CSV or XLSX file. The format comes from the file's name and
+// contents; an XLSX file is a ZIP archive. Blank rows are skipped.
+func Read(name string, data []byte) (*Table, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	zipped := bytes.HasPrefix(data, []byte("PK\x03\x04"))
+
+	var (
+		table *Table
+		err   error
+	)
+	switch {
+	case ext == ".xlsx" || (zipped && ext != ".csv"):
+		table, err = readXLSX(data)
+	case ext == ".csv" || ext == ".txt" || ext == "":
+		table, err = readCSV(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(table.Header) == 0 {
+		return nil, ErrNoHeader
+	}
+	if len(table.Rows) == 0 {
+		return nil, ErrNoRows
+	}
+	return table, nil
+}
+
+func readCSV(data []byte) (*Table, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	table := &Table{}
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		table.add(line, values)
+	}
+	return table, nil
+}
+
+// add appends a row, or sets the header when there is none yet.
+func (t *Table) add(line int, values []string) {
+	blank := true
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+		if values[i] != "" {
+			blank = false
+		}
+	}
+	if blank {
+		return
+	}
+
+	if t.Header == nil {
+		t.Header = values
+		return
+	}
+	t.Rows = append(t.Rows, Record{Line: line, Values: values})
+}
@@ -0,0 +1,77 @@
+// Package library holds the lending rules every store must enforce, whether
+// the database does it in triggers or the store does it in Go.
+package library
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxActiveLoans is the number of books a student may hold at once. MySQL
+// enforces it in the before_borrow_limit trigger.
+const MaxActiveLoans = 3
+
+// LoanPeriod is the default time between checkout and due date.
+const LoanPeriod = 14 * 24 * time.Hour
+
+// MaxRenewals is the number of times a loan may be renewed.
+const MaxRenewals = 2
+
+const (
+	BookAvailable = "Available"
+	BookIssued    = "Issued"
+	// BookBorrowed is used by older rows for a book that is out on loan.
+	BookBorrowed = "Borrowed"
+
+	LoanIssued   = "Issued"
+	LoanReturned = "Returned"
+
+	HoldPending   = "Pending"
+	HoldReady     = "Ready"
+	HoldFulfilled = "Fulfilled"
+	HoldCancelled = "Cancelled"
+)
+
+var (
+	// ErrBorrowLimitExceeded carries the same text as the trigger's SIGNAL so
+	// both backends report it identically.
+	ErrBorrowLimitExceeded = errors.New("Cannot borrow more than 3 books at a time")
+	ErrBookUnavailable     = errors.New("book is not available for checkout")
+	ErrAlreadyReturned     = errors.New("loan has already been returned")
+	ErrUnknownReference    = errors.New("a referenced record does not exist")
+	ErrRenewalLimit        = errors.New("loan has already been renewed the maximum number of times")
+	ErrBookOnHold          = errors.New("another student is waiting for this book")
+)
+
+// IsAvailable reports whether a book with this status can be checked out.
+func IsAvailable(status string) bool {
+	return strings.EqualFold(strings.TrimSpace(status), BookAvailable)
+}
+
+// IsOnLoan reports whether a book with this status is out with a student.
+func IsOnLoan(status string) bool {
+	status = strings.TrimSpace(status)
+	return strings.EqualFold(status, BookIssued) || strings.EqualFold(status, BookBorrowed)
+}
+
+// IsOpenHold reports whether a hold with this status is still waiting for
+// its book or ready to be collected.
+func IsOpenHold(status string) bool {
+	status = strings.TrimSpace(status)
+	return strings.EqualFold(status, HoldPending) || strings.EqualFold(status, HoldReady)
+}
+
+// DueDate returns the default due date for a loan issued on day.
+func DueDate(issued time.Time) time.Time {
+	return issued.Add(LoanPeriod)
+}
+
+// RenewedDueDate returns the due date of a loan renewed on day: a full loan
+// period from then, or from the current due date if that is later.
+func RenewedDueDate(due, day time.Time) time.Time {
+	if due.After(day) {
+		return due.Add(LoanPeriod)
+	}
+	return day.Add(LoanPeriod)
+}
@@ -0,0 +1,81 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Student statuses. A student moves Active → Suspended → Inactive →
+// Graduated; suspension and inactivity can be lifted, graduation is final.
+const (
+	StudentActive    = "Active"
+	StudentSuspended = "Suspended"
+	StudentInactive  = "Inactive"
+	StudentGraduated = "Graduated"
+)
+
+// StudentStatuses lists every status in lifecycle order.
+var StudentStatuses = []string{StudentActive, StudentSuspended, StudentInactive, StudentGraduated}
+
+var studentTransitions = map[string][]string{
+	StudentActive:    {StudentSuspended, StudentInactive, StudentGraduated},
+	StudentSuspended: {StudentActive, StudentInactive},
+	StudentInactive:  {StudentActive, StudentGraduated},
+	StudentGraduated: {},
+}
+
+var (
+	ErrStudentIneligible      = errors.New("student is not active and cannot borrow books")
+	ErrInvalidTransition      = errors.New("student status change is not allowed")
+	ErrOutstandingObligations = errors.New("student has open loans or unpaid fines")
+)
+
+// NormalizeStudentStatus maps a stored status onto one of the lifecycle
+// statuses regardless of case. Older rows hold free text, so ok is false for
+// anything else.
+func NormalizeStudentStatus(status string) (normalized string, ok bool) {
+	status = strings.TrimSpace(status)
+	for _, known := range StudentStatuses {
+		if strings.EqualFold(status, known) {
+			return known, true
+		}
+	}
+	return status, false
+}
+
+// CanBorrow reports whether a student with this status may check out books.
+func CanBorrow(status string) bool {
+	normalized, _ := NormalizeStudentStatus(status)
+	return normalized == StudentActive
+}
+
+// CheckTransition returns an error wrapping ErrInvalidTransition unless a
+// student may move from one status to the other. A status that is not part
+// of the lifecycle may move to any status, so older rows can be cleaned up.
+func CheckTransition(from, to string) error {
+	from, known := NormalizeStudentStatus(from)
+	to, _ = NormalizeStudentStatus(to)
+	if !known || from == to {
+		return nil
+	}
+
+	for _, allowed := range studentTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+}
+
+// Deactivates reports whether moving to status ends a student's membership,
+// which is refused while the student has open loans or unpaid fines.
+func Deactivates(status string) bool {
+	normalized, _ := NormalizeStudentStatus(status)
+	return normalized == StudentInactive || normalized == StudentGraduated
+}
+
+// OutstandingError reports what stops a student from being deactivated.
+func OutstandingError(openLoans, unpaidFines int) error {
+	return fmt.Errorf("%w: %d open loans, %d unpaid fines", ErrOutstandingObligations, openLoans, unpaidFines)
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+// New builds the process logger and installs it as the slog default, which
+// also routes the standard library log package through it.
+func New(cfg config.LogConfig, w io.Writer) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected json or text)", cfg.Format)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
+	return logger, nil
+}
+
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", value)
+	}
+	return level, nil
+}
+
+type contextKey string
+
+const stateKey contextKey = "logging:state"
+
+// requestState is shared between the access log middleware and the handlers
+// further down the chain, which only see derived contexts.
+type requestState struct {
+	requestID string
+	traceID   string
+	userID    int64
+	message   string
+	err       error
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	state, _ := ctx.Value(stateKey).(*requestState)
+	return state
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	if state := stateFrom(ctx); state != nil {
+		return state.requestID
+	}
+	return ""
+}
+
+// SetUser attaches the authenticated user to the request's access log entry.
+func SetUser(ctx context.Context, userID int64) {
+	if state := stateFrom(ctx); state != nil {
+		state.userID = userID
+	}
+}
+
+// SetTraceID links the access log entry to the request's trace.
+func SetTraceID(ctx context.Context, traceID string) {
+	if state := stateFrom(ctx); state != nil {
+		state.traceID = traceID
+	}
+}
+
+// RecordError keeps the underlying error of a failed request next to the
+// message returned to the client. Outside the middleware it logs directly.
+func RecordError(ctx context.Context, message string, err error) {
+	state := stateFrom(ctx)
+	if state == nil {
+		slog.ErrorContext(ctx, message, "error", err)
+		return
+	}
+
+	state.message = message
+	state.err = err
+}
+
+// FromContext returns the default logger annotated with the request ID.
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestIDFrom(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
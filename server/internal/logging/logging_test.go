@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+func TestMiddlewareEchoesRequestIDAndLogsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	router := chi.NewRouter()
+	router.Use(Middleware(logger))
+	router.Get("/api/books/{id}", func(w http.ResponseWriter, r *http.Request) {
+		SetUser(r.Context(), 42)
+		RecordError(r.Context(), "failed to fetch books", errors.New("dial tcp: connection refused"))
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/3", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "abc-123" {
+		t.Fatalf("expected request ID to be echoed, got %q", got)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log line %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"level":      "ERROR",
+		"request_id": "abc-123",
+		"route":      "/api/books/{id}",
+		"status":     float64(500),
+		"user_id":    float64(42),
+		"message":    "failed to fetch books",
+		"error":      "dial tcp: connection refused",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+}
+
+func TestMiddlewareReplacesMalformedRequestID(t *testing.T) {
+	handler := Middleware(slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); len(got) != 32 {
+		t.Fatalf("expected a generated request ID, got %q", got)
+	}
+}
+
+func TestNewRejectsUnknownLevel(t *testing.T) {
+	if _, err := New(config.LogConfig{Level: "verbose"}, &bytes.Buffer{}); err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if _, err := New(config.LogConfig{Level: "debug", Format: "yaml"}, &bytes.Buffer{}); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/config"
+)
+
+// relay is a plain SMTP server that accepts one message and records the
+// envelope and data it was given.
+type relay struct {
+	addr string
+	got  chan []string
+}
+
+func startRelay(t *testing.T) *relay {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	r := &relay{addr: ln.Addr().String(), got: make(chan []string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		in := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 relay ready")
+		for data := false; ; {
+			line, err := in.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+
+			switch {
+			case data && line == ".":
+				data = false
+				reply("250 queued")
+			case data:
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 relay")
+			case line == "DATA":
+				data = true
+				reply("354 go ahead")
+			case line == "QUIT":
+				reply("221 bye")
+				r.got <- lines
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return r
+}
+
+func TestSendVerificationCode(t *testing.T) {
+	r := startRelay(t)
+	host, port, _ := net.SplitHostPort(r.addr)
+
+	sender := NewSMTP(config.MailConfig{Sender: config.MailSenderSMTP, From: "Library <library@example.com>", Host: host, Port: port})
+	if err := sender.SendVerificationCode(context.Background(), "james.white@example.com", "123456"); err != nil {
+		t.Fatalf("SendVerificationCode returned error: %v", err)
+	}
+
+	session := strings.Join(<-r.got, "\n")
+	for _, want := range []string{
+		"MAIL FROM:<library@example.com>",
+		"RCPT TO:<james.white@example.com>",
+		"To: <james.white@example.com>",
+		"verification code is 123456",
+	} {
+		if !strings.Contains(session, want) {
+			t.Errorf("expected %q in the SMTP session:\n%s", want, session)
+		}
+	}
+}
+
+func TestSendRejectsHeaderInjection(t *testing.T) {
+	sender := NewSMTP(config.MailConfig{From: "library@example.com", Host: "127.0.0.1", Port: "1"})
+	err := sender.SendVerificationCode(context.Background(), "a@example.com\r\nBcc: b@example.com", "123456")
+	if err == nil || !strings.Contains(err.Error(), "recipient") {
+		t.Fatalf("expected the address to be rejected before dialing, got %v", err)
+	}
+}
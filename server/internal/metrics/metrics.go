@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+const namespace = "library"
+
+// circulationTimeout bounds the borrow table counts made on every scrape.
+const circulationTimeout = 2 * time.Second
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, chi route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, chi route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_query_duration_seconds",
+		Help:      "Repository call latency by query name and outcome.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"query", "outcome"})
+
+	authAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_attempts_total",
+		Help:      "Authentication attempts by action (login, register, token) and outcome.",
+	}, []string{"action", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		queryDuration,
+		authAttempts,
+	)
+}
+
+type CirculationSource interface {
+	GetCirculationStats(ctx context.Context) (models.CirculationStats, error)
+}
+
+// RegisterDatabase exposes connection pool statistics and circulation totals
+// read from the borrow table at scrape time.
+func RegisterDatabase(db *sql.DB, source CirculationSource) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "library"))
+	RegisterCirculation(source)
+}
+
+// RegisterCirculation exposes circulation totals on their own, for stores
+// without a connection pool.
+func RegisterCirculation(source CirculationSource) {
+	Registry.MustRegister(newCirculationCollector(source))
+}
+
+// RegisterReplica exports connection pool statistics for the read replica.
+func RegisterReplica(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "library_replica"))
+}
+
+// Middleware records request counts and latency. It must be installed on
+// the router itself so the route pattern is known once the request is served.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := prometheus.Labels{
+			"method": r.Method,
+			"route":  routePattern(r),
+			"status": strconv.Itoa(status),
+		}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(started).Seconds())
+	})
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unmatched"
+}
+
+// Handler serves the registry in the Prometheus text format. When token is
+// set, scrapers must present it as a bearer token.
+func Handler(token string) http.Handler {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func ObserveQuery(name string, duration time.Duration, err error) {
+	queryDuration.WithLabelValues(name, outcome(err == nil)).Observe(duration.Seconds())
+}
+
+func AuthAttempt(action string, success bool) {
+	authAttempts.WithLabelValues(action, outcome(success)).Inc()
+}
+
+func outcome(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
+
+type circulationCollector struct {
+	source    CirculationSource
+	checkouts *prometheus.Desc
+	returns   *prometheus.Desc
+	open      *prometheus.Desc
+	overdue   *prometheus.Desc
+	up        *prometheus.Desc
+}
+
+func newCirculationCollector(source CirculationSource) *circulationCollector {
+	return &circulationCollector{
+		source:    source,
+		checkouts: prometheus.NewDesc(namespace+"_checkouts_total", "Books checked out, from the borrow table.", nil, nil),
+		returns:   prometheus.NewDesc(namespace+"_returns_total", "Books returned, from the borrow table.", nil, nil),
+		open:      prometheus.NewDesc(namespace+"_loans_open", "Loans that have not been returned.", nil, nil),
+		overdue:   prometheus.NewDesc(namespace+"_loans_overdue", "Open loans past their due date.", nil, nil),
+		up:        prometheus.NewDesc(namespace+"_circulation_scrape_success", "Whether circulation figures could be read.", nil, nil),
+	}
+}
+
+func (c *circulationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.checkouts
+	ch <- c.returns
+	ch <- c.open
+	ch <- c.overdue
+	ch <- c.up
+}
+
+func (c *circulationCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), circulationTimeout)
+	defer cancel()
+
+	stats, err := c.source.GetCirculationStats(ctx)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(c.checkouts, prometheus.CounterValue, float64(stats.Checkouts))
+	ch <- prometheus.MustNewConstMetric(c.returns, prometheus.CounterValue, float64(stats.Returns))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.Open))
+	ch <- prometheus.MustNewConstMetric(c.overdue, prometheus.GaugeValue, float64(stats.Overdue))
+}
@@ -0,0 +1,520 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+)
+
+// The MySQL migrations live in migrations/ and their PostgreSQL and SQLite
+// ports, with the same versions and names, in migrations/postgres/ and
+// migrations/sqlite/.
+//
+//go:embed migrations/*.sql migrations/postgres/*.sql migrations/sqlite/*.sql
+var embeddedMigrations embed.FS
+
+var fileNameRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrDirty            = errors.New("database is in a dirty migration state")
+	ErrChecksumMismatch = errors.New("applied migration checksum does not match")
+	ErrIrreversible     = errors.New("migration has no down script")
+	ErrLockTimeout      = errors.New("timed out waiting for migration lock")
+)
+
+const (
+	StateApplied          = "applied"
+	StatePending          = "pending"
+	StateDirty            = "dirty"
+	StateChecksumMismatch = "checksum_mismatch"
+	StateMissing          = "missing"
+)
+
+const lockName = "schema_migrations"
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Version   int64  `json:"version"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	AppliedAt string `json:"applied_at,omitempty"`
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	dirty     bool
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	db          *sql.DB
+	dialect     dialect.Dialect
+	migrations  []Migration
+	lockTimeout time.Duration
+}
+
+// New returns a migrator for the embedded MySQL migrations.
+func New(db *sql.DB) (*Migrator, error) {
+	return NewForDialect(db, dialect.MySQL)
+}
+
+// NewForDialect returns a migrator for the embedded migrations written for d.
+func NewForDialect(db *sql.DB, d dialect.Dialect) (*Migrator, error) {
+	sub, err := fs.Sub(embeddedMigrations, embeddedDir(d))
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := NewFromFS(db, sub)
+	if err != nil {
+		return nil, err
+	}
+	migrator.dialect = d
+	return migrator, nil
+}
+
+// NewFromFS returns a MySQL migrator for the migrations in fsys.
+func NewFromFS(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect.MySQL, migrations: migrations, lockTimeout: 30 * time.Second}, nil
+}
+
+// Embedded returns the MySQL migrations compiled into the binary.
+func Embedded() ([]Migration, error) {
+	sub, err := fs.Sub(embeddedMigrations, embeddedDir(dialect.MySQL))
+	if err != nil {
+		return nil, err
+	}
+	return Load(sub)
+}
+
+func embeddedDir(d dialect.Dialect) string {
+	switch d {
+	case dialect.Postgres:
+		return "migrations/postgres"
+	case dialect.SQLite:
+		return "migrations/sqlite"
+	default:
+		return "migrations"
+	}
+}
+
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := fileNameRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Clean(entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	conn, release, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	applied, err := loadApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	ran := make([]Migration, 0)
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		const insertQuery = `
+			INSERT INTO schema_migrations (version, name, checksum, dirty)
+			VALUES (?, ?, ?, TRUE)
+		`
+		if _, err := conn.ExecContext(ctx, m.dialect.Rebind(insertQuery), migration.Version, migration.Name, migration.Checksum); err != nil {
+			return ran, err
+		}
+
+		if err := m.execScript(ctx, conn, migration.Up); err != nil {
+			return ran, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		const cleanQuery = `UPDATE schema_migrations SET dirty = FALSE WHERE version = ?`
+		if _, err := conn.ExecContext(ctx, m.dialect.Rebind(cleanQuery), migration.Version); err != nil {
+			return ran, err
+		}
+
+		ran = append(ran, migration)
+	}
+
+	return ran, nil
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, nil
+	}
+
+	conn, release, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	applied, err := loadApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	reverted := make([]Migration, 0, steps)
+
+	for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if migration.Down == "" {
+			return reverted, fmt.Errorf("%w: %04d_%s", ErrIrreversible, migration.Version, migration.Name)
+		}
+
+		const dirtyQuery = `UPDATE schema_migrations SET dirty = TRUE WHERE version = ?`
+		if _, err := conn.ExecContext(ctx, m.dialect.Rebind(dirtyQuery), migration.Version); err != nil {
+			return reverted, err
+		}
+
+		if err := m.execScript(ctx, conn, migration.Down); err != nil {
+			return reverted, fmt.Errorf("rollback of %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		const deleteQuery = `DELETE FROM schema_migrations WHERE version = ?`
+		if _, err := conn.ExecContext(ctx, m.dialect.Rebind(deleteQuery), migration.Version); err != nil {
+			return reverted, err
+		}
+
+		reverted = append(reverted, migration)
+	}
+
+	return reverted, nil
+}
+
+// Status reports the state of every migration. It only reads, so the
+// readiness probe can call it without DDL privileges; a database without the
+// schema_migrations table has every migration pending.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	exists, err := m.tableExists(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]appliedMigration)
+	if exists {
+		if applied, err = loadApplied(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := Status{Version: migration.Version, Name: migration.Name, State: StatePending}
+
+		if record, ok := applied[migration.Version]; ok {
+			status.AppliedAt = record.appliedAt.UTC().Format(time.RFC3339)
+			switch {
+			case record.dirty:
+				status.State = StateDirty
+			case record.checksum != migration.Checksum:
+				status.State = StateChecksumMismatch
+			default:
+				status.State = StateApplied
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for version, record := range applied {
+		if known[version] {
+			continue
+		}
+		statuses = append(statuses, Status{
+			Version:   version,
+			Name:      record.name,
+			State:     StateMissing,
+			AppliedAt: record.appliedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+func (m *Migrator) verify(applied map[int64]appliedMigration) error {
+	for version, record := range applied {
+		if record.dirty {
+			return fmt.Errorf("%w: version %d (%s) must be repaired manually", ErrDirty, version, record.name)
+		}
+	}
+
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if ok && record.checksum != migration.Checksum {
+			return fmt.Errorf("%w: %04d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) acquire(ctx context.Context) (*sql.Conn, func(), error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lock, unlock := m.lockQueries()
+	if err := m.lock(ctx, conn, lock); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	release := func() {
+		if unlock != "" {
+			_, _ = conn.ExecContext(context.Background(), unlock, lockName)
+		}
+		conn.Close()
+	}
+
+	if err := ensureTable(ctx, conn); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	return conn, release, nil
+}
+
+// lockQueries returns the statements that take and release the migration
+// lock. SQLite has none: an embedded database belongs to one process, and its
+// own file lock serialises writers.
+func (m *Migrator) lockQueries() (lock, unlock string) {
+	switch m.dialect {
+	case dialect.Postgres:
+		return "SELECT pg_advisory_lock(hashtext($1))", "SELECT pg_advisory_unlock(hashtext($1))"
+	case dialect.SQLite:
+		return "", ""
+	default:
+		return "SELECT GET_LOCK(?, ?)", "SELECT RELEASE_LOCK(?)"
+	}
+}
+
+// lock takes the session-level migration lock. MySQL's GET_LOCK has its own
+// timeout; PostgreSQL's advisory lock waits until the context gives up.
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn, query string) error {
+	if query == "" {
+		return nil
+	}
+	if m.dialect == dialect.Postgres {
+		ctx, cancel := context.WithTimeout(ctx, m.lockTimeout)
+		defer cancel()
+
+		if _, err := conn.ExecContext(ctx, query, lockName); err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrLockTimeout
+			}
+			return err
+		}
+		return nil
+	}
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, query, lockName, int(m.lockTimeout.Seconds())).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return ErrLockTimeout
+	}
+	return nil
+}
+
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	const query = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	_, err := conn.ExecContext(ctx, query)
+	return err
+}
+
+// tableExists reports whether the schema_migrations table has been created.
+func (m *Migrator) tableExists(ctx context.Context, conn *sql.Conn) (bool, error) {
+	var query string
+	switch m.dialect {
+	case dialect.Postgres:
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'schema_migrations'"
+	case dialect.SQLite:
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'"
+	default:
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'"
+	}
+
+	var count int
+	if err := conn.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func loadApplied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	const query = `
+		SELECT version, name, checksum, dirty, applied_at
+		FROM schema_migrations
+		ORDER BY version
+	`
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+
+	for rows.Next() {
+		var (
+			version int64
+			record  appliedMigration
+		)
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.dirty, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = record
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	if m.dialect == dialect.Postgres || m.dialect == dialect.SQLite {
+		// PL/pgSQL bodies are dollar-quoted and SQLite trigger bodies end
+		// each statement with a semicolon, so neither can be split here.
+		// Both drivers run a multi-statement script in one call.
+		_, err := conn.ExecContext(ctx, script)
+		return err
+	}
+
+	for _, statement := range SplitStatements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
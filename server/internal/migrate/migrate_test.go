@@ -0,0 +1,342 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "modernc.org/sqlite"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+)
+
+func TestLoadOrdersAndChecksumsMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_second.up.sql":   {Data: []byte("SELECT 2;")},
+		"0001_first.up.sql":    {Data: []byte("SELECT 1;")},
+		"0001_first.down.sql":  {Data: []byte("SELECT -1;")},
+		"README.md":            {Data: []byte("ignored")},
+		"0003_bad-name.up.sql": {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+
+	if migrations[0].Version != 1 || migrations[1].Version != 2 {
+		t.Fatalf("migrations are not ordered: %+v", migrations)
+	}
+
+	if migrations[0].Down != "SELECT -1;" {
+		t.Fatalf("expected down script to be attached, got %q", migrations[0].Down)
+	}
+
+	if len(migrations[0].Checksum) != 64 {
+		t.Fatalf("expected sha256 checksum, got %q", migrations[0].Checksum)
+	}
+}
+
+func TestLoadRejectsMissingUpScript(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_first.down.sql": {Data: []byte("SELECT 1;")},
+	}
+
+	if _, err := Load(fsys); err == nil {
+		t.Fatal("expected error for migration without up script")
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := Embedded()
+	if err != nil {
+		t.Fatalf("failed to load embedded migrations: %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	for _, migration := range migrations {
+		if migration.Down == "" {
+			t.Fatalf("migration %04d_%s has no down script", migration.Version, migration.Name)
+		}
+		if len(SplitStatements(migration.Up)) == 0 {
+			t.Fatalf("migration %04d_%s has no statements", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestMigratorUpAppliesPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewFromFS(db, fstest.MapFS{
+		"0001_first.up.sql":  {Data: []byte("CREATE TABLE a (id INT);")},
+		"0002_second.up.sql": {Data: []byte("CREATE TABLE b (id INT);")},
+	})
+	if err != nil {
+		t.Fatalf("NewFromFS returned error: %v", err)
+	}
+
+	first := migrator.Migrations()[0]
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "dirty", "applied_at"}).
+			AddRow(int64(1), "first", first.Checksum, false, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations")).
+		WithArgs(int64(2), "second", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b (id INT)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE schema_migrations SET dirty = FALSE")).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("expected only migration 2 to be applied, got %+v", applied)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestDialectMigrationsMirrorMySQL(t *testing.T) {
+	mysqlMigrations, err := Embedded()
+	if err != nil {
+		t.Fatalf("failed to load MySQL migrations: %v", err)
+	}
+
+	for _, d := range []dialect.Dialect{dialect.Postgres, dialect.SQLite} {
+		migrator, err := NewForDialect(nil, d)
+		if err != nil {
+			t.Fatalf("failed to load %s migrations: %v", d, err)
+		}
+		migrations := migrator.Migrations()
+
+		if len(migrations) != len(mysqlMigrations) {
+			t.Fatalf("expected %d %s migrations, got %d", len(mysqlMigrations), d, len(migrations))
+		}
+		for i, migration := range migrations {
+			if migration.Version != mysqlMigrations[i].Version || migration.Name != mysqlMigrations[i].Name {
+				t.Fatalf("%s migration %04d_%s does not match MySQL %04d_%s",
+					d, migration.Version, migration.Name, mysqlMigrations[i].Version, mysqlMigrations[i].Name)
+			}
+			if migration.Down == "" {
+				t.Fatalf("%s migration %04d_%s has no down script", d, migration.Version, migration.Name)
+			}
+		}
+	}
+}
+
+func TestPostgresMigratorRunsScriptsWhole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	script := "CREATE FUNCTION f() RETURNS int LANGUAGE plpgsql AS $$ BEGIN RETURN 1; END; $$;"
+	migrator, err := NewFromFS(db, fstest.MapFS{"0001_first.up.sql": {Data: []byte(script)}})
+	if err != nil {
+		t.Fatalf("NewFromFS returned error: %v", err)
+	}
+	migrator.dialect = dialect.Postgres
+
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock(hashtext($1))")).
+		WithArgs(lockName).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "dirty", "applied_at"}))
+	mock.ExpectExec(regexp.QuoteMeta("VALUES ($1, $2, $3, TRUE)")).
+		WithArgs(int64(1), "first", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(script)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE schema_migrations SET dirty = FALSE WHERE version = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock(hashtext($1))")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestSQLiteMigrationsUpAndDown(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewForDialect(db, dialect.SQLite)
+	if err != nil {
+		t.Fatalf("NewForDialect returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+	if len(applied) != len(migrator.Migrations()) {
+		t.Fatalf("expected %d migrations to be applied, got %d", len(migrator.Migrations()), len(applied))
+	}
+
+	// Student 3 already has three open loans in the sample data.
+	_, err = db.ExecContext(ctx, `INSERT INTO borrow (Borrow_ID, Student_ID, Book_ID, Staff_ID, Issue_Date, Due_Date, Status)
+		VALUES (11, 3, 1, 1, '2025-10-08', '2025-10-22', 'Issued')`)
+	if err == nil || !strings.Contains(err.Error(), "Cannot borrow more than 3 books at a time") {
+		t.Fatalf("expected the borrow limit trigger to fire, got %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO borrow (Borrow_ID, Student_ID, Book_ID, Staff_ID, Issue_Date, Due_Date, Status)
+		VALUES (11, 2, 1, 1, '2025-10-08', '2025-10-22', 'Issued')`); err != nil {
+		t.Fatalf("checkout failed: %v", err)
+	}
+	var status string
+	if err := db.QueryRowContext(ctx, "SELECT Status FROM book WHERE Book_ID = 1").Scan(&status); err != nil || status != "Issued" {
+		t.Fatalf("expected book 1 to be Issued, got %q (%v)", status, err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM borrow WHERE Borrow_ID = 11"); err != nil {
+		t.Fatalf("failed to remove the test loan: %v", err)
+	}
+
+	reverted, err := migrator.Down(ctx, len(applied))
+	if err != nil {
+		t.Fatalf("Down returned error: %v", err)
+	}
+	if len(reverted) != len(applied) {
+		t.Fatalf("expected %d migrations to be reverted, got %d", len(applied), len(reverted))
+	}
+
+	var tables int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name <> 'schema_migrations' AND name NOT LIKE 'sqlite_%'").Scan(&tables); err != nil {
+		t.Fatalf("failed to count tables: %v", err)
+	}
+	if tables != 0 {
+		t.Fatalf("expected every table to be dropped, %d remain", tables)
+	}
+}
+
+func TestSQLiteSeedDownKeepsLaterGenreCounts(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewForDialect(db, dialect.SQLite)
+	if err != nil {
+		t.Fatalf("NewForDialect returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+
+	// One book in a seeded genre and one in a new genre, both counted by the
+	// after_book_insert trigger.
+	if _, err := db.ExecContext(ctx, `INSERT INTO book (Title, Author, Publisher, Year_Published, Genre, Status) VALUES
+		('Compilers', 'Aho', 'Pearson', 2006, 'Computer Science', 'Available'),
+		('Leaves of Grass', 'Whitman', 'Penguin', 1855, 'Poetry', 'Available')`); err != nil {
+		t.Fatalf("failed to add books: %v", err)
+	}
+
+	if _, err := migrator.Down(ctx, len(applied)-1); err != nil {
+		t.Fatalf("Down returned error: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT Genre, Count FROM genre_count ORDER BY Genre")
+	if err != nil {
+		t.Fatalf("failed to read genre counts: %v", err)
+	}
+	defer rows.Close()
+
+	got := map[string]int{}
+	for rows.Next() {
+		var genre string
+		var count int
+		if err := rows.Scan(&genre, &count); err != nil {
+			t.Fatal(err)
+		}
+		got[genre] = count
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["Computer Science"] != 1 || got["Poetry"] != 1 {
+		t.Fatalf("expected only the books added after seeding to be counted, got %v", got)
+	}
+}
+
+func TestStatusDoesNotCreateTable(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewForDialect(db, dialect.SQLite)
+	if err != nil {
+		t.Fatalf("NewForDialect returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if len(statuses) != len(migrator.Migrations()) {
+		t.Fatalf("expected %d statuses, got %d", len(migrator.Migrations()), len(statuses))
+	}
+	for _, status := range statuses {
+		if status.State != StatePending {
+			t.Fatalf("expected every migration to be pending, got %+v", status)
+		}
+	}
+
+	var tables int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master").Scan(&tables); err != nil {
+		t.Fatalf("failed to count tables: %v", err)
+	}
+	if tables != 0 {
+		t.Fatalf("expected Status to leave the database empty, found %d objects", tables)
+	}
+}
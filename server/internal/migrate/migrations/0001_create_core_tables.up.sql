@@ -0,0 +1,67 @@
+-- Core library tables. Mirrors ddl_dml.sql without the destructive DROP TABLE
+-- statements so that the migration is safe to run against an existing database.
+
+CREATE TABLE IF NOT EXISTS users (
+    user_id INT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role ENUM('admin', 'viewer') NOT NULL DEFAULT 'viewer',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS student (
+    Student_ID INT PRIMARY KEY,
+    First_Name VARCHAR(50),
+    Last_Name VARCHAR(50),
+    Email VARCHAR(100),
+    Status VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS staff (
+    Staff_ID INT PRIMARY KEY,
+    First_Name VARCHAR(50),
+    Last_Name VARCHAR(50),
+    Position VARCHAR(50),
+    Status VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS book (
+    Book_ID INT PRIMARY KEY,
+    Title VARCHAR(100),
+    Author VARCHAR(100),
+    Publisher VARCHAR(100),
+    Year_Published YEAR,
+    Genre VARCHAR(50),
+    Status VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS genre_count (
+    Genre VARCHAR(50) PRIMARY KEY,
+    Count INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS computer (
+    Computer_ID INT PRIMARY KEY,
+    Location VARCHAR(50),
+    OS VARCHAR(50),
+    Model VARCHAR(100),
+    Status VARCHAR(20),
+    Assigned_Student_ID INT,
+    Assigned_Staff_ID INT,
+    FOREIGN KEY (Assigned_Student_ID) REFERENCES student(Student_ID),
+    FOREIGN KEY (Assigned_Staff_ID) REFERENCES staff(Staff_ID)
+);
+
+CREATE TABLE IF NOT EXISTS borrow (
+    Borrow_ID INT PRIMARY KEY,
+    Student_ID INT,
+    Book_ID INT,
+    Staff_ID INT,
+    Issue_Date DATE,
+    Due_Date DATE,
+    Status VARCHAR(20),
+    FOREIGN KEY (Student_ID) REFERENCES student(Student_ID),
+    FOREIGN KEY (Book_ID) REFERENCES book(Book_ID),
+    FOREIGN KEY (Staff_ID) REFERENCES staff(Staff_ID)
+);
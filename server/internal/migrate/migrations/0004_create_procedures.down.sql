@@ -0,0 +1,8 @@
+DROP PROCEDURE IF EXISTS list_triggers;
+DROP PROCEDURE IF EXISTS list_procedures;
+DROP PROCEDURE IF EXISTS list_functions;
+DROP PROCEDURE IF EXISTS get_currently_borrowed_books;
+DROP PROCEDURE IF EXISTS get_books_borrowed_with_overdue;
+DROP PROCEDURE IF EXISTS get_books_borrowed_by_student;
+DROP PROCEDURE IF EXISTS get_active_staff_list;
+DROP PROCEDURE IF EXISTS add_new_book;
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS before_borrow_limit;
+DROP TRIGGER IF EXISTS after_staff_insert;
+DROP TRIGGER IF EXISTS before_book_delete;
+DROP TRIGGER IF EXISTS after_book_insert;
+DROP TRIGGER IF EXISTS after_borrow_return;
+DROP TRIGGER IF EXISTS after_borrow_insert;
@@ -0,0 +1,16 @@
+-- Audit trail for the admin read-only SQL console.
+
+CREATE TABLE IF NOT EXISTS query_log (
+    query_log_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    user_email VARCHAR(100) NOT NULL,
+    statement TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status ENUM('ok', 'error', 'rejected') NOT NULL,
+    row_count INT NOT NULL DEFAULT 0,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    error_message VARCHAR(1000),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_query_log_user (user_id),
+    INDEX idx_query_log_created_at (created_at)
+);
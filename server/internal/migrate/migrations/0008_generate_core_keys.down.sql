@@ -0,0 +1,8 @@
+SET FOREIGN_KEY_CHECKS = 0;
+
+ALTER TABLE borrow MODIFY Borrow_ID INT NOT NULL;
+ALTER TABLE book MODIFY Book_ID INT NOT NULL;
+ALTER TABLE staff MODIFY Staff_ID INT NOT NULL;
+ALTER TABLE student MODIFY Student_ID INT NOT NULL;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,14 @@
+-- Let the server allocate student, staff, book and borrow IDs. They used to be
+-- computed as MAX(id) + 1 before the insert, which hands the same ID to
+-- concurrent writers. AUTO_INCREMENT starts after the highest existing ID.
+-- MySQL refuses to change a column another table references while foreign key
+-- checks are on; the column type is unchanged, so the references stay valid.
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+ALTER TABLE student MODIFY Student_ID INT NOT NULL AUTO_INCREMENT;
+ALTER TABLE staff MODIFY Staff_ID INT NOT NULL AUTO_INCREMENT;
+ALTER TABLE book MODIFY Book_ID INT NOT NULL AUTO_INCREMENT;
+ALTER TABLE borrow MODIFY Borrow_ID INT NOT NULL AUTO_INCREMENT;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,21 @@
+-- Student status lifecycle. The reason and effective date of the latest
+-- status change are kept on the student row; the transitions themselves are
+-- checked by the API (internal/library).
+
+ALTER TABLE student
+    ADD COLUMN Status_Reason VARCHAR(255),
+    ADD COLUMN Status_Effective_Date DATE;
+
+-- Fines owed by students, optionally for a specific loan. A fine is unpaid
+-- until Paid_Date is set; unpaid fines block deactivating the student.
+CREATE TABLE IF NOT EXISTS fine (
+    Fine_ID INT AUTO_INCREMENT PRIMARY KEY,
+    Student_ID INT NOT NULL,
+    Borrow_ID INT,
+    Amount DECIMAL(8,2) NOT NULL,
+    Reason VARCHAR(255),
+    Issued_Date DATE NOT NULL,
+    Paid_Date DATE,
+    FOREIGN KEY (Student_ID) REFERENCES student(Student_ID),
+    FOREIGN KEY (Borrow_ID) REFERENCES borrow(Borrow_ID)
+);
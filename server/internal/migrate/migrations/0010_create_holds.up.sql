@@ -0,0 +1,11 @@
+-- Holds place a student in the queue for a book. A hold is open while it is
+-- Pending or Ready; Fulfilled and Cancelled holds are kept as history.
+CREATE TABLE IF NOT EXISTS hold (
+    Hold_ID INT AUTO_INCREMENT PRIMARY KEY,
+    Student_ID INT NOT NULL,
+    Book_ID INT NOT NULL,
+    Placed_Date DATE NOT NULL,
+    Status VARCHAR(20) NOT NULL DEFAULT 'Pending',
+    FOREIGN KEY (Student_ID) REFERENCES student(Student_ID),
+    FOREIGN KEY (Book_ID) REFERENCES book(Book_ID)
+);
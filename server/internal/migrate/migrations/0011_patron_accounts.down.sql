@@ -0,0 +1,14 @@
+ALTER TABLE borrow
+    DROP COLUMN Renew_Count;
+
+UPDATE users SET role = 'viewer' WHERE role = 'patron';
+
+ALTER TABLE users
+    DROP FOREIGN KEY fk_users_student;
+
+ALTER TABLE users
+    DROP COLUMN verification_expires_at,
+    DROP COLUMN verification_hash,
+    DROP COLUMN email_verified_at,
+    DROP COLUMN student_id,
+    MODIFY role ENUM('admin', 'viewer') NOT NULL DEFAULT 'viewer';
@@ -0,0 +1,16 @@
+-- Patron accounts. A user can be linked to the student they are, either by
+-- confirming a code sent to the student's email address or by an admin, and
+-- a patron only sees its own student's records. The verification columns
+-- hold the SHA-256 of the pending code and when it expires.
+
+ALTER TABLE users
+    MODIFY role ENUM('admin', 'viewer', 'patron') NOT NULL DEFAULT 'viewer',
+    ADD COLUMN student_id INT UNIQUE,
+    ADD COLUMN email_verified_at TIMESTAMP NULL,
+    ADD COLUMN verification_hash CHAR(64),
+    ADD COLUMN verification_expires_at TIMESTAMP NULL,
+    ADD CONSTRAINT fk_users_student FOREIGN KEY (student_id) REFERENCES student(Student_ID);
+
+-- Patrons can renew their own loans a limited number of times.
+ALTER TABLE borrow
+    ADD COLUMN Renew_Count INT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+UPDATE users SET role = 'viewer' WHERE role = 'librarian';
+
+ALTER TABLE users
+    MODIFY role ENUM('admin', 'viewer', 'patron') NOT NULL DEFAULT 'viewer';
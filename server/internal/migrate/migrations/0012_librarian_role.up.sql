@@ -0,0 +1,5 @@
+-- Librarians see students' personal details in full; viewers see them
+-- masked.
+
+ALTER TABLE users
+    MODIFY role ENUM('admin', 'librarian', 'viewer', 'patron') NOT NULL DEFAULT 'viewer';
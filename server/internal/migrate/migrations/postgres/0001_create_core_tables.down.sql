@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS borrow;
+DROP TABLE IF EXISTS computer;
+DROP TABLE IF EXISTS genre_count;
+DROP TABLE IF EXISTS book;
+DROP TABLE IF EXISTS staff;
+DROP TABLE IF EXISTS student;
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS set_updated_at;
@@ -0,0 +1,5 @@
+DROP FUNCTION IF EXISTS total_books_in_genre;
+DROP FUNCTION IF EXISTS overdueby;
+DROP FUNCTION IF EXISTS is_book_available;
+DROP FUNCTION IF EXISTS borrowed_count;
+DROP FUNCTION IF EXISTS active_staff_count;
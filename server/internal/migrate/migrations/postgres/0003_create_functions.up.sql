@@ -0,0 +1,79 @@
+-- PL/pgSQL ports of ../0003_create_functions.up.sql. MySQL compares strings
+-- case-insensitively, so status checks go through lower() to match.
+
+-- Function: active_staff_count
+-- Returns the total number of staff members whose status is 'active'
+CREATE OR REPLACE FUNCTION active_staff_count()
+RETURNS INT
+LANGUAGE plpgsql STABLE AS $$
+BEGIN
+    RETURN (
+        SELECT COUNT(*)
+        FROM staff
+        WHERE lower(status) = 'active'
+    );
+END;
+$$;
+
+-- Function: borrowed_count
+-- Returns the number of books currently issued to a given student
+-- Parameter: stu_id (INT) - ID of the student
+CREATE OR REPLACE FUNCTION borrowed_count(stu_id INT)
+RETURNS INT
+LANGUAGE plpgsql STABLE AS $$
+DECLARE
+    cnt INT;
+BEGIN
+    SELECT COUNT(*) INTO cnt
+    FROM borrow
+    WHERE Student_ID = stu_id
+      AND lower(Status) = 'issued';
+
+    RETURN cnt;
+END;
+$$;
+
+-- Function: is_book_available
+-- Checks if a specific book is available
+-- Parameter: bookid (INT) - ID of the book
+-- Returns TRUE if the book status is 'Available', FALSE otherwise
+CREATE OR REPLACE FUNCTION is_book_available(bookid INT)
+RETURNS BOOLEAN
+LANGUAGE plpgsql STABLE AS $$
+DECLARE
+    s VARCHAR(20);
+BEGIN
+    SELECT Status INTO s
+    FROM book
+    WHERE Book_ID = bookid
+    LIMIT 1;
+
+    RETURN COALESCE(lower(s) = 'available', FALSE);
+END;
+$$;
+
+-- Function: overdueby
+-- Returns the number of days a book is overdue
+-- Parameter: due_date (DATE) - the due date of the book
+CREATE OR REPLACE FUNCTION overdueby(due_date DATE)
+RETURNS INT
+LANGUAGE plpgsql STABLE AS $$
+BEGIN
+    RETURN CURRENT_DATE - due_date;
+END;
+$$;
+
+-- Function: total_books_in_genre
+-- Returns the total number of books in a given genre
+-- Parameter: genre_name (VARCHAR) - name of the genre
+CREATE OR REPLACE FUNCTION total_books_in_genre(genre_name VARCHAR(50))
+RETURNS INT
+LANGUAGE plpgsql STABLE AS $$
+BEGIN
+    RETURN (
+        SELECT COUNT(*)
+        FROM book
+        WHERE lower(genre) = lower(genre_name)
+    );
+END;
+$$;
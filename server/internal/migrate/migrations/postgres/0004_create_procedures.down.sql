@@ -0,0 +1,8 @@
+DROP FUNCTION IF EXISTS list_triggers;
+DROP FUNCTION IF EXISTS list_procedures;
+DROP FUNCTION IF EXISTS list_functions;
+DROP FUNCTION IF EXISTS get_currently_borrowed_books;
+DROP FUNCTION IF EXISTS get_books_borrowed_with_overdue;
+DROP FUNCTION IF EXISTS get_books_borrowed_by_student;
+DROP FUNCTION IF EXISTS get_active_staff_list;
+DROP PROCEDURE IF EXISTS add_new_book;
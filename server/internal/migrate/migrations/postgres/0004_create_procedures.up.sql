@@ -0,0 +1,140 @@
+-- PL/pgSQL ports of ../0004_create_procedures.up.sql. PostgreSQL procedures
+-- cannot return rows, so the MySQL procedures that do are set-returning
+-- functions here. The API lists and runs them as procedures all the same.
+
+-- Procedure: add_new_book
+-- Adds a new book to the book table with status 'available'
+-- Parameters: p_title, p_author, p_publisher, p_year_published, p_genre
+-- book.Book_ID has no default, so the next free ID is taken here.
+CREATE OR REPLACE PROCEDURE add_new_book(
+    p_title VARCHAR(255),
+    p_author VARCHAR(255),
+    p_publisher VARCHAR(255),
+    p_year_published INT,
+    p_genre VARCHAR(50)
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    LOCK TABLE book IN SHARE ROW EXCLUSIVE MODE;
+
+    INSERT INTO book (Book_ID, Title, Author, Publisher, Year_Published, Genre, Status)
+    SELECT COALESCE(MAX(Book_ID), 0) + 1, p_title, p_author, p_publisher, p_year_published, p_genre, 'available'
+    FROM book;
+END;
+$$;
+
+-- Procedure: get_active_staff_list
+-- Returns the list of active staff members
+CREATE OR REPLACE FUNCTION get_active_staff_list()
+RETURNS TABLE (staff_id INT, first_name VARCHAR, last_name VARCHAR, "position" VARCHAR)
+LANGUAGE sql STABLE AS $$
+    SELECT s.staff_id, s.first_name, s.last_name, s.position
+    FROM staff s
+    WHERE lower(s.status) = 'active';
+$$;
+
+-- Procedure: get_books_borrowed_by_student
+-- Returns all books borrowed by a specific student
+-- Parameter: studentId - ID of the student
+CREATE OR REPLACE FUNCTION get_books_borrowed_by_student(studentId INT)
+RETURNS TABLE (
+    book_id INT,
+    title VARCHAR,
+    author VARCHAR,
+    publisher VARCHAR,
+    year_published SMALLINT,
+    genre VARCHAR,
+    issue_date DATE,
+    due_date DATE,
+    borrow_status VARCHAR
+)
+LANGUAGE sql STABLE AS $$
+    SELECT
+        B.Book_ID,
+        B.Title,
+        B.Author,
+        B.Publisher,
+        B.Year_Published,
+        B.Genre,
+        Br.Issue_Date,
+        Br.Due_Date,
+        Br.Status
+    FROM book B
+    JOIN borrow Br ON B.Book_ID = Br.Book_ID
+    WHERE Br.Student_ID = studentId
+      AND lower(TRIM(Br.Status)) IN ('not returned', 'issued', 'borrowed');
+$$;
+
+-- Procedure: get_books_borrowed_with_overdue
+-- Returns borrowed books for a student along with overdue days
+-- Parameter: studentId - ID of the student
+CREATE OR REPLACE FUNCTION get_books_borrowed_with_overdue(studentId INT)
+RETURNS TABLE (book_title VARCHAR, overdue INT)
+LANGUAGE sql STABLE AS $$
+    SELECT
+        B.Title,
+        overdueby(Br.Due_Date)
+    FROM book B
+    JOIN borrow Br ON B.Book_ID = Br.Book_ID
+    WHERE Br.Student_ID = studentId
+      AND lower(TRIM(Br.Status)) IN ('not returned', 'issued', 'borrowed');
+$$;
+
+-- Procedure: get_currently_borrowed_books
+-- Returns all books that are currently issued
+CREATE OR REPLACE FUNCTION get_currently_borrowed_books()
+RETURNS TABLE (
+    book_id INT,
+    title VARCHAR,
+    author VARCHAR,
+    genre VARCHAR,
+    student_id INT,
+    staff_id INT,
+    issue_date DATE,
+    due_date DATE,
+    status VARCHAR
+)
+LANGUAGE sql STABLE AS $$
+    SELECT b.Book_ID, b.Title, b.Author, b.Genre,
+           br.Student_ID, br.Staff_ID, br.Issue_Date, br.Due_Date, br.Status
+    FROM book b
+    JOIN borrow br ON b.Book_ID = br.Book_ID
+    WHERE lower(br.Status) = 'issued';
+$$;
+
+-- Procedure: list_functions
+-- Lists all functions in the current schema
+CREATE OR REPLACE FUNCTION list_functions()
+RETURNS TABLE (routine_name NAME)
+LANGUAGE sql STABLE AS $$
+    SELECT p.proname
+    FROM pg_proc p
+    WHERE p.pronamespace = current_schema()::regnamespace
+      AND p.prokind = 'f'
+      AND NOT p.proretset
+      AND p.prorettype <> 'trigger'::regtype
+    ORDER BY p.proname;
+$$;
+
+-- Procedure: list_procedures
+-- Lists all stored procedures in the current schema, including the
+-- set-returning functions that stand in for MySQL procedures
+CREATE OR REPLACE FUNCTION list_procedures()
+RETURNS TABLE (routine_name NAME)
+LANGUAGE sql STABLE AS $$
+    SELECT p.proname
+    FROM pg_proc p
+    WHERE p.pronamespace = current_schema()::regnamespace
+      AND (p.prokind = 'p' OR (p.prokind = 'f' AND p.proretset))
+    ORDER BY p.proname;
+$$;
+
+-- Procedure: list_triggers
+-- Lists all triggers in the current schema
+CREATE OR REPLACE FUNCTION list_triggers()
+RETURNS TABLE (trigger_name NAME, event TEXT, table_name NAME, timing TEXT)
+LANGUAGE sql STABLE AS $$
+    SELECT t.trigger_name::NAME, t.event_manipulation::TEXT, t.event_object_table::NAME, t.action_timing::TEXT
+    FROM information_schema.triggers t
+    WHERE t.trigger_schema = current_schema();
+$$;
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS before_borrow_limit ON borrow;
+DROP TRIGGER IF EXISTS after_staff_insert ON staff;
+DROP TRIGGER IF EXISTS before_book_delete ON book;
+DROP TRIGGER IF EXISTS after_book_insert ON book;
+DROP TRIGGER IF EXISTS after_borrow_return ON borrow;
+DROP TRIGGER IF EXISTS after_borrow_insert ON borrow;
+DROP FUNCTION IF EXISTS before_borrow_limit;
+DROP FUNCTION IF EXISTS after_staff_insert;
+DROP FUNCTION IF EXISTS before_book_delete;
+DROP FUNCTION IF EXISTS after_book_insert;
+DROP FUNCTION IF EXISTS after_borrow_return;
+DROP FUNCTION IF EXISTS after_borrow_insert;
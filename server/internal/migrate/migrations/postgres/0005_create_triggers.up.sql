@@ -0,0 +1,124 @@
+-- PL/pgSQL ports of ../0005_create_triggers.up.sql. Each MySQL trigger body
+-- becomes a trigger function of the same name. SIGNAL SQLSTATE '45000'
+-- becomes RAISE EXCEPTION (SQLSTATE P0001) with the same message, so the API
+-- maps both to the same problem.
+
+-- Trigger: after_borrow_insert
+-- Updates the book status to 'Issued' when a new borrow record is inserted
+CREATE OR REPLACE FUNCTION after_borrow_insert() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE book
+    SET Status = 'Issued'
+    WHERE Book_ID = NEW.Book_ID;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS after_borrow_insert ON borrow;
+CREATE TRIGGER after_borrow_insert
+AFTER INSERT ON borrow
+FOR EACH ROW
+EXECUTE FUNCTION after_borrow_insert();
+
+-- Trigger: after_borrow_return
+-- Updates the book status to 'Available' when a borrowed book is returned (status changes)
+CREATE OR REPLACE FUNCTION after_borrow_return() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF lower(NEW.Status) <> 'issued' THEN
+        UPDATE book
+        SET Status = 'Available'
+        WHERE Book_ID = NEW.Book_ID;
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS after_borrow_return ON borrow;
+CREATE TRIGGER after_borrow_return
+AFTER UPDATE ON borrow
+FOR EACH ROW
+EXECUTE FUNCTION after_borrow_return();
+
+-- Trigger: after_book_insert
+-- Updates genre_count table whenever a new book is inserted
+CREATE OR REPLACE FUNCTION after_book_insert() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO genre_count (Genre, Count)
+    VALUES (NEW.Genre, 1)
+    ON CONFLICT (Genre) DO UPDATE SET Count = genre_count.Count + 1;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS after_book_insert ON book;
+CREATE TRIGGER after_book_insert
+AFTER INSERT ON book
+FOR EACH ROW
+EXECUTE FUNCTION after_book_insert();
+
+-- Trigger: before_book_delete
+-- Prevents deletion of a book if it is currently issued or borrowed
+CREATE OR REPLACE FUNCTION before_book_delete() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF lower(OLD.Status) IN ('issued', 'borrowed') THEN
+        RAISE EXCEPTION 'Cannot delete a book that is currently issued or borrowed';
+    END IF;
+    RETURN OLD;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS before_book_delete ON book;
+CREATE TRIGGER before_book_delete
+BEFORE DELETE ON book
+FOR EACH ROW
+EXECUTE FUNCTION before_book_delete();
+
+-- Trigger: after_staff_insert
+-- Ensures new staff have 'Active' status if none provided
+CREATE OR REPLACE FUNCTION after_staff_insert() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF NEW.Status IS NULL OR NEW.Status = '' THEN
+        UPDATE staff
+        SET Status = 'Active'
+        WHERE Staff_ID = NEW.Staff_ID;
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS after_staff_insert ON staff;
+CREATE TRIGGER after_staff_insert
+AFTER INSERT ON staff
+FOR EACH ROW
+EXECUTE FUNCTION after_staff_insert();
+
+-- Trigger: before_borrow_limit
+-- Prevents a student from borrowing more than 3 books at a time
+CREATE OR REPLACE FUNCTION before_borrow_limit() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+    borrow_count INT;
+BEGIN
+    SELECT COUNT(*)
+    INTO borrow_count
+    FROM borrow
+    WHERE Student_ID = NEW.Student_ID
+      AND lower(Status) = 'issued';
+
+    IF borrow_count >= 3 THEN
+        RAISE EXCEPTION 'Cannot borrow more than 3 books at a time';
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS before_borrow_limit ON borrow;
+CREATE TRIGGER before_borrow_limit
+BEFORE INSERT ON borrow
+FOR EACH ROW
+EXECUTE FUNCTION before_borrow_limit();
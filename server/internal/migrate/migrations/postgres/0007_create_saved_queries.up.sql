@@ -0,0 +1,42 @@
+-- Named, parameterised read-only reports managed by administrators.
+
+CREATE TABLE IF NOT EXISTS saved_queries (
+    saved_query_id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(200) NOT NULL,
+    description VARCHAR(1000),
+    query_text TEXT NOT NULL,
+    parameters JSONB NOT NULL,
+    required_role VARCHAR(10) NOT NULL DEFAULT 'viewer' CHECK (required_role IN ('admin', 'viewer')),
+    created_by INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS saved_queries_set_updated_at ON saved_queries;
+CREATE TRIGGER saved_queries_set_updated_at
+BEFORE UPDATE ON saved_queries
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- The reports run through the console, so they use portable SQL.
+INSERT INTO saved_queries (slug, title, description, query_text, parameters, required_role) VALUES
+('books-by-genre',
+ 'Books by genre',
+ 'Number of books and available copies per genre.',
+ 'SELECT Genre, COUNT(*) AS Total, SUM(CASE WHEN Status = ''Available'' THEN 1 ELSE 0 END) AS Available FROM book WHERE (CAST(:genre AS VARCHAR) IS NULL OR Genre = :genre) GROUP BY Genre ORDER BY Total DESC',
+ '[{"name":"genre","type":"string","description":"Only include this genre"}]',
+ 'viewer'),
+('staff-on-leave',
+ 'Staff on leave',
+ 'Staff members whose status is currently on leave.',
+ 'SELECT Staff_ID, First_Name, Last_Name, Position FROM staff WHERE Status = ''On leave'' ORDER BY Last_Name, First_Name',
+ '[]',
+ 'viewer'),
+('most-active-students',
+ 'Most active students',
+ 'Students ranked by the number of books borrowed since a given date.',
+ 'SELECT s.Student_ID, s.First_Name, s.Last_Name, COUNT(b.Borrow_ID) AS Borrow_Count FROM student s JOIN borrow b ON b.Student_ID = s.Student_ID WHERE b.Issue_Date >= CAST(:since AS DATE) GROUP BY s.Student_ID, s.First_Name, s.Last_Name ORDER BY Borrow_Count DESC LIMIT :limit',
+ '[{"name":"since","type":"date","required":true,"description":"Earliest issue date to count"},{"name":"limit","type":"int","default":"10","min":1,"max":100,"description":"Number of students to return"}]',
+ 'viewer')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,22 @@
+-- Restores the add_new_book from 0004, which computes the next ID itself.
+CREATE OR REPLACE PROCEDURE add_new_book(
+    p_title VARCHAR(255),
+    p_author VARCHAR(255),
+    p_publisher VARCHAR(255),
+    p_year_published INT,
+    p_genre VARCHAR(50)
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    LOCK TABLE book IN SHARE ROW EXCLUSIVE MODE;
+
+    INSERT INTO book (Book_ID, Title, Author, Publisher, Year_Published, Genre, Status)
+    SELECT COALESCE(MAX(Book_ID), 0) + 1, p_title, p_author, p_publisher, p_year_published, p_genre, 'available'
+    FROM book;
+END;
+$$;
+
+ALTER TABLE borrow ALTER COLUMN Borrow_ID DROP IDENTITY IF EXISTS;
+ALTER TABLE book ALTER COLUMN Book_ID DROP IDENTITY IF EXISTS;
+ALTER TABLE staff ALTER COLUMN Staff_ID DROP IDENTITY IF EXISTS;
+ALTER TABLE student ALTER COLUMN Student_ID DROP IDENTITY IF EXISTS;
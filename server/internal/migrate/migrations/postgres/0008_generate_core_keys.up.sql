@@ -0,0 +1,27 @@
+-- PostgreSQL port of ../0008_generate_core_keys.up.sql. The keys become
+-- identity columns whose sequences start after the highest existing ID, and
+-- add_new_book no longer takes the table lock it needed to compute one.
+
+ALTER TABLE student ALTER COLUMN Student_ID ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE staff ALTER COLUMN Staff_ID ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE book ALTER COLUMN Book_ID ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE borrow ALTER COLUMN Borrow_ID ADD GENERATED BY DEFAULT AS IDENTITY;
+
+SELECT setval(pg_get_serial_sequence('student', 'student_id'), COALESCE(MAX(Student_ID), 0) + 1, false) FROM student;
+SELECT setval(pg_get_serial_sequence('staff', 'staff_id'), COALESCE(MAX(Staff_ID), 0) + 1, false) FROM staff;
+SELECT setval(pg_get_serial_sequence('book', 'book_id'), COALESCE(MAX(Book_ID), 0) + 1, false) FROM book;
+SELECT setval(pg_get_serial_sequence('borrow', 'borrow_id'), COALESCE(MAX(Borrow_ID), 0) + 1, false) FROM borrow;
+
+CREATE OR REPLACE PROCEDURE add_new_book(
+    p_title VARCHAR(255),
+    p_author VARCHAR(255),
+    p_publisher VARCHAR(255),
+    p_year_published INT,
+    p_genre VARCHAR(50)
+)
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO book (Title, Author, Publisher, Year_Published, Genre, Status)
+    VALUES (p_title, p_author, p_publisher, p_year_published, p_genre, 'available');
+END;
+$$;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS fine;
+
+ALTER TABLE student
+    DROP COLUMN Status_Effective_Date,
+    DROP COLUMN Status_Reason;
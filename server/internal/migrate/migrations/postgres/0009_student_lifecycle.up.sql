@@ -0,0 +1,15 @@
+-- PostgreSQL port of ../0009_student_lifecycle.up.sql.
+
+ALTER TABLE student
+    ADD COLUMN Status_Reason VARCHAR(255),
+    ADD COLUMN Status_Effective_Date DATE;
+
+CREATE TABLE IF NOT EXISTS fine (
+    Fine_ID INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    Student_ID INT NOT NULL REFERENCES student(Student_ID),
+    Borrow_ID INT REFERENCES borrow(Borrow_ID),
+    Amount NUMERIC(8,2) NOT NULL,
+    Reason VARCHAR(255),
+    Issued_Date DATE NOT NULL,
+    Paid_Date DATE
+);
@@ -0,0 +1,9 @@
+-- PostgreSQL port of ../0010_create_holds.up.sql.
+
+CREATE TABLE IF NOT EXISTS hold (
+    Hold_ID INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    Student_ID INT NOT NULL REFERENCES student(Student_ID),
+    Book_ID INT NOT NULL REFERENCES book(Book_ID),
+    Placed_Date DATE NOT NULL,
+    Status VARCHAR(20) NOT NULL DEFAULT 'Pending'
+);
@@ -0,0 +1,13 @@
+ALTER TABLE borrow
+    DROP COLUMN Renew_Count;
+
+UPDATE users SET role = 'viewer' WHERE role = 'patron';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users
+    DROP COLUMN verification_expires_at,
+    DROP COLUMN verification_hash,
+    DROP COLUMN email_verified_at,
+    DROP COLUMN student_id,
+    ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'viewer'));
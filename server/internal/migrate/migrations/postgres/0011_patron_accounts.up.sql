@@ -0,0 +1,14 @@
+-- PostgreSQL port of ../0011_patron_accounts.up.sql. The role column is
+-- checked by the constraint PostgreSQL named when 0001 created it.
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'viewer', 'patron')),
+    ADD COLUMN student_id INT UNIQUE REFERENCES student(Student_ID),
+    ADD COLUMN email_verified_at TIMESTAMP,
+    ADD COLUMN verification_hash CHAR(64),
+    ADD COLUMN verification_expires_at TIMESTAMP;
+
+ALTER TABLE borrow
+    ADD COLUMN Renew_Count INT NOT NULL DEFAULT 0;
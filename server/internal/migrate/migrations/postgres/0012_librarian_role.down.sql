@@ -0,0 +1,6 @@
+UPDATE users SET role = 'viewer' WHERE role = 'librarian';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'viewer', 'patron'));
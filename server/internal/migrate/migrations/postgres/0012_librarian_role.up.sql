@@ -0,0 +1,6 @@
+-- PostgreSQL port of ../0012_librarian_role.up.sql.
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'librarian', 'viewer', 'patron'));
@@ -0,0 +1,74 @@
+-- Core library tables for SQLite. Mirrors ../0001_create_core_tables.up.sql;
+-- MySQL's ENUM and AUTO_INCREMENT become CHECK constraints and an INTEGER
+-- rowid key, and ON UPDATE CURRENT_TIMESTAMP becomes a trigger. Foreign keys
+-- are enforced because the driver opens every connection with
+-- PRAGMA foreign_keys = ON.
+
+CREATE TABLE IF NOT EXISTS users (
+    user_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL DEFAULT 'viewer' CHECK (role IN ('admin', 'viewer')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS student (
+    Student_ID INT PRIMARY KEY,
+    First_Name VARCHAR(50),
+    Last_Name VARCHAR(50),
+    Email VARCHAR(100),
+    Status VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS staff (
+    Staff_ID INT PRIMARY KEY,
+    First_Name VARCHAR(50),
+    Last_Name VARCHAR(50),
+    Position VARCHAR(50),
+    Status VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS book (
+    Book_ID INT PRIMARY KEY,
+    Title VARCHAR(100),
+    Author VARCHAR(100),
+    Publisher VARCHAR(100),
+    Year_Published SMALLINT,
+    Genre VARCHAR(50),
+    Status VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS genre_count (
+    Genre VARCHAR(50) PRIMARY KEY,
+    Count INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS computer (
+    Computer_ID INT PRIMARY KEY,
+    Location VARCHAR(50),
+    OS VARCHAR(50),
+    Model VARCHAR(100),
+    Status VARCHAR(20),
+    Assigned_Student_ID INT REFERENCES student(Student_ID),
+    Assigned_Staff_ID INT REFERENCES staff(Staff_ID)
+);
+
+CREATE TABLE IF NOT EXISTS borrow (
+    Borrow_ID INT PRIMARY KEY,
+    Student_ID INT REFERENCES student(Student_ID),
+    Book_ID INT REFERENCES book(Book_ID),
+    Staff_ID INT REFERENCES staff(Staff_ID),
+    Issue_Date DATE,
+    Due_Date DATE,
+    Status VARCHAR(20)
+);
+
+-- Stands in for MySQL's ON UPDATE CURRENT_TIMESTAMP. Recursive triggers are
+-- off, so the inner UPDATE does not fire it again.
+CREATE TRIGGER IF NOT EXISTS users_set_updated_at
+AFTER UPDATE ON users
+FOR EACH ROW
+BEGIN
+    UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE user_id = NEW.user_id;
+END;
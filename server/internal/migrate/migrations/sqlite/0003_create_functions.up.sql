@@ -0,0 +1,4 @@
+-- SQLite has no stored functions. The version is kept so that the migration
+-- history lines up with ../0003_create_functions.up.sql; the metadata API
+-- reports function execution as unsupported on this driver.
+SELECT 1;
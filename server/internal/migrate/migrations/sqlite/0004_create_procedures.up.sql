@@ -0,0 +1,4 @@
+-- SQLite has no stored procedures. The version is kept so that the migration
+-- history lines up with ../0004_create_procedures.up.sql; the metadata API
+-- reports procedure execution as unsupported on this driver.
+SELECT 1;
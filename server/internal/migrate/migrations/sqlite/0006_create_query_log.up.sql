@@ -0,0 +1,17 @@
+-- Audit trail for the admin read-only SQL console.
+
+CREATE TABLE IF NOT EXISTS query_log (
+    query_log_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INT NOT NULL,
+    user_email VARCHAR(100) NOT NULL,
+    statement TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('ok', 'error', 'rejected')),
+    row_count INT NOT NULL DEFAULT 0,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    error_message VARCHAR(1000),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_query_log_user ON query_log (user_id);
+CREATE INDEX IF NOT EXISTS idx_query_log_created_at ON query_log (created_at);
@@ -0,0 +1,43 @@
+-- Named, parameterised read-only reports managed by administrators.
+
+CREATE TABLE IF NOT EXISTS saved_queries (
+    saved_query_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(200) NOT NULL,
+    description VARCHAR(1000),
+    query_text TEXT NOT NULL,
+    parameters TEXT NOT NULL,
+    required_role VARCHAR(10) NOT NULL DEFAULT 'viewer' CHECK (required_role IN ('admin', 'viewer')),
+    created_by INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS saved_queries_set_updated_at
+AFTER UPDATE ON saved_queries
+FOR EACH ROW
+BEGIN
+    UPDATE saved_queries SET updated_at = CURRENT_TIMESTAMP WHERE saved_query_id = NEW.saved_query_id;
+END;
+
+-- The reports run through the console. SQLite gives DATE numeric affinity, so
+-- the date parameter goes through date() rather than CAST.
+INSERT OR IGNORE INTO saved_queries (slug, title, description, query_text, parameters, required_role) VALUES
+('books-by-genre',
+ 'Books by genre',
+ 'Number of books and available copies per genre.',
+ 'SELECT Genre, COUNT(*) AS Total, SUM(CASE WHEN Status = ''Available'' THEN 1 ELSE 0 END) AS Available FROM book WHERE (CAST(:genre AS TEXT) IS NULL OR Genre = :genre) GROUP BY Genre ORDER BY Total DESC',
+ '[{"name":"genre","type":"string","description":"Only include this genre"}]',
+ 'viewer'),
+('staff-on-leave',
+ 'Staff on leave',
+ 'Staff members whose status is currently on leave.',
+ 'SELECT Staff_ID, First_Name, Last_Name, Position FROM staff WHERE Status = ''On leave'' ORDER BY Last_Name, First_Name',
+ '[]',
+ 'viewer'),
+('most-active-students',
+ 'Most active students',
+ 'Students ranked by the number of books borrowed since a given date.',
+ 'SELECT s.Student_ID, s.First_Name, s.Last_Name, COUNT(b.Borrow_ID) AS Borrow_Count FROM student s JOIN borrow b ON b.Student_ID = s.Student_ID WHERE b.Issue_Date >= date(:since) GROUP BY s.Student_ID, s.First_Name, s.Last_Name ORDER BY Borrow_Count DESC LIMIT :limit',
+ '[{"name":"since","type":"date","required":true,"description":"Earliest issue date to count"},{"name":"limit","type":"int","default":"10","min":1,"max":100,"description":"Number of students to return"}]',
+ 'viewer');
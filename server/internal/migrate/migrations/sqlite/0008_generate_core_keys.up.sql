@@ -0,0 +1,151 @@
+-- SQLite port of ../0008_generate_core_keys.up.sql. SQLite cannot alter a
+-- column, so each table is rebuilt with an INTEGER PRIMARY KEY AUTOINCREMENT
+-- key, which SQLite allocates itself and never reuses, following the
+-- documented rebuild procedure. Dropping a table drops its triggers, so the
+-- triggers from 0005 are dropped first and created again afterwards.
+-- Foreign keys are switched off so the drops do not cascade to the rows that
+-- reference them; the IDs are copied unchanged.
+
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS after_borrow_insert;
+DROP TRIGGER IF EXISTS after_borrow_return;
+DROP TRIGGER IF EXISTS after_book_insert;
+DROP TRIGGER IF EXISTS before_book_delete;
+DROP TRIGGER IF EXISTS after_staff_insert;
+DROP TRIGGER IF EXISTS before_borrow_limit;
+
+CREATE TABLE student_rebuilt (
+    Student_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    First_Name VARCHAR(50),
+    Last_Name VARCHAR(50),
+    Email VARCHAR(100),
+    Status VARCHAR(20)
+);
+INSERT INTO student_rebuilt (Student_ID, First_Name, Last_Name, Email, Status)
+SELECT Student_ID, First_Name, Last_Name, Email, Status FROM student;
+DROP TABLE student;
+ALTER TABLE student_rebuilt RENAME TO student;
+
+CREATE TABLE staff_rebuilt (
+    Staff_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    First_Name VARCHAR(50),
+    Last_Name VARCHAR(50),
+    Position VARCHAR(50),
+    Status VARCHAR(20)
+);
+INSERT INTO staff_rebuilt (Staff_ID, First_Name, Last_Name, Position, Status)
+SELECT Staff_ID, First_Name, Last_Name, Position, Status FROM staff;
+DROP TABLE staff;
+ALTER TABLE staff_rebuilt RENAME TO staff;
+
+CREATE TABLE book_rebuilt (
+    Book_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Title VARCHAR(100),
+    Author VARCHAR(100),
+    Publisher VARCHAR(100),
+    Year_Published SMALLINT,
+    Genre VARCHAR(50),
+    Status VARCHAR(20)
+);
+INSERT INTO book_rebuilt (Book_ID, Title, Author, Publisher, Year_Published, Genre, Status)
+SELECT Book_ID, Title, Author, Publisher, Year_Published, Genre, Status FROM book;
+DROP TABLE book;
+ALTER TABLE book_rebuilt RENAME TO book;
+
+CREATE TABLE borrow_rebuilt (
+    Borrow_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Student_ID INT REFERENCES student(Student_ID),
+    Book_ID INT REFERENCES book(Book_ID),
+    Staff_ID INT REFERENCES staff(Staff_ID),
+    Issue_Date DATE,
+    Due_Date DATE,
+    Status VARCHAR(20)
+);
+INSERT INTO borrow_rebuilt (Borrow_ID, Student_ID, Book_ID, Staff_ID, Issue_Date, Due_Date, Status)
+SELECT Borrow_ID, Student_ID, Book_ID, Staff_ID, Issue_Date, Due_Date, Status FROM borrow;
+DROP TABLE borrow;
+ALTER TABLE borrow_rebuilt RENAME TO borrow;
+
+-- Trigger: after_borrow_insert
+-- Updates the book status to 'Issued' when a new borrow record is inserted
+DROP TRIGGER IF EXISTS after_borrow_insert;
+CREATE TRIGGER after_borrow_insert
+AFTER INSERT ON borrow
+FOR EACH ROW
+BEGIN
+    UPDATE book
+    SET Status = 'Issued'
+    WHERE Book_ID = NEW.Book_ID;
+END;
+
+-- Trigger: after_borrow_return
+-- Updates the book status to 'Available' when a borrowed book is returned (status changes)
+DROP TRIGGER IF EXISTS after_borrow_return;
+CREATE TRIGGER after_borrow_return
+AFTER UPDATE ON borrow
+FOR EACH ROW
+WHEN NEW.Status <> 'Issued'
+BEGIN
+    UPDATE book
+    SET Status = 'Available'
+    WHERE Book_ID = NEW.Book_ID;
+END;
+
+-- Trigger: after_book_insert
+-- Updates genre_count table whenever a new book is inserted
+DROP TRIGGER IF EXISTS after_book_insert;
+CREATE TRIGGER after_book_insert
+AFTER INSERT ON book
+FOR EACH ROW
+BEGIN
+    INSERT INTO genre_count (Genre, Count)
+    VALUES (NEW.Genre, 1)
+    ON CONFLICT (Genre) DO UPDATE SET Count = Count + 1;
+END;
+
+-- Trigger: before_book_delete
+-- Prevents deletion of a book if it is currently issued or borrowed
+DROP TRIGGER IF EXISTS before_book_delete;
+CREATE TRIGGER before_book_delete
+BEFORE DELETE ON book
+FOR EACH ROW
+WHEN OLD.Status = 'Issued' OR OLD.Status = 'Borrowed'
+BEGIN
+    SELECT RAISE(ABORT, 'Cannot delete a book that is currently issued or borrowed');
+END;
+
+-- Trigger: after_staff_insert
+-- Ensures new staff have 'Active' status if none provided
+DROP TRIGGER IF EXISTS after_staff_insert;
+CREATE TRIGGER after_staff_insert
+AFTER INSERT ON staff
+FOR EACH ROW
+WHEN NEW.Status IS NULL OR NEW.Status = ''
+BEGIN
+    UPDATE staff
+    SET Status = 'Active'
+    WHERE Staff_ID = NEW.Staff_ID;
+END;
+
+-- Trigger: before_borrow_limit
+-- Prevents a student from borrowing more than 3 books at a time
+DROP TRIGGER IF EXISTS before_borrow_limit;
+CREATE TRIGGER before_borrow_limit
+BEFORE INSERT ON borrow
+FOR EACH ROW
+WHEN (
+    SELECT COUNT(*)
+    FROM borrow
+    WHERE Student_ID = NEW.Student_ID
+      AND Status = 'Issued'
+) >= 3
+BEGIN
+    SELECT RAISE(ABORT, 'Cannot borrow more than 3 books at a time');
+END;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
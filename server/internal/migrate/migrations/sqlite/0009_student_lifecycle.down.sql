@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS fine;
+
+ALTER TABLE student DROP COLUMN Status_Effective_Date;
+ALTER TABLE student DROP COLUMN Status_Reason;
@@ -0,0 +1,15 @@
+-- SQLite port of ../0009_student_lifecycle.up.sql. SQLite adds one column per
+-- ALTER TABLE statement.
+
+ALTER TABLE student ADD COLUMN Status_Reason VARCHAR(255);
+ALTER TABLE student ADD COLUMN Status_Effective_Date DATE;
+
+CREATE TABLE IF NOT EXISTS fine (
+    Fine_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Student_ID INT NOT NULL REFERENCES student(Student_ID),
+    Borrow_ID INT REFERENCES borrow(Borrow_ID),
+    Amount DECIMAL(8,2) NOT NULL,
+    Reason VARCHAR(255),
+    Issued_Date DATE NOT NULL,
+    Paid_Date DATE
+);
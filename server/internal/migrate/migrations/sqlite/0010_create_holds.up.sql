@@ -0,0 +1,9 @@
+-- SQLite port of ../0010_create_holds.up.sql.
+
+CREATE TABLE IF NOT EXISTS hold (
+    Hold_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Student_ID INT NOT NULL REFERENCES student(Student_ID),
+    Book_ID INT NOT NULL REFERENCES book(Book_ID),
+    Placed_Date DATE NOT NULL,
+    Status VARCHAR(20) NOT NULL DEFAULT 'Pending'
+);
@@ -0,0 +1,22 @@
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+ALTER TABLE borrow DROP COLUMN Renew_Count;
+
+CREATE TABLE users_rebuilt (
+    user_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL DEFAULT 'viewer' CHECK (role IN ('admin', 'viewer')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO users_rebuilt (user_id, email, password_hash, role, created_at, updated_at)
+SELECT user_id, email, password_hash, CASE role WHEN 'patron' THEN 'viewer' ELSE role END, created_at, updated_at FROM users;
+DROP TABLE users;
+ALTER TABLE users_rebuilt RENAME TO users;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
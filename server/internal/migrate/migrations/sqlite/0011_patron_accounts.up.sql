@@ -0,0 +1,29 @@
+-- SQLite port of ../0011_patron_accounts.up.sql. A CHECK constraint cannot
+-- be altered, so users is rebuilt as in 0008 to accept the patron role.
+
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+CREATE TABLE users_rebuilt (
+    user_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL DEFAULT 'viewer' CHECK (role IN ('admin', 'viewer', 'patron')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    student_id INT UNIQUE REFERENCES student(Student_ID),
+    email_verified_at TIMESTAMP,
+    verification_hash CHAR(64),
+    verification_expires_at TIMESTAMP
+);
+INSERT INTO users_rebuilt (user_id, email, password_hash, role, created_at, updated_at)
+SELECT user_id, email, password_hash, role, created_at, updated_at FROM users;
+DROP TABLE users;
+ALTER TABLE users_rebuilt RENAME TO users;
+
+ALTER TABLE borrow ADD COLUMN Renew_Count INT NOT NULL DEFAULT 0;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
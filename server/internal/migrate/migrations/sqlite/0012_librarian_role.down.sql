@@ -0,0 +1,24 @@
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+CREATE TABLE users_rebuilt (
+    user_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL DEFAULT 'viewer' CHECK (role IN ('admin', 'viewer', 'patron')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    student_id INT UNIQUE REFERENCES student(Student_ID),
+    email_verified_at TIMESTAMP,
+    verification_hash CHAR(64),
+    verification_expires_at TIMESTAMP
+);
+INSERT INTO users_rebuilt (user_id, email, password_hash, role, created_at, updated_at, student_id, email_verified_at, verification_hash, verification_expires_at)
+SELECT user_id, email, password_hash, CASE role WHEN 'librarian' THEN 'viewer' ELSE role END, created_at, updated_at, student_id, email_verified_at, verification_hash, verification_expires_at FROM users;
+DROP TABLE users;
+ALTER TABLE users_rebuilt RENAME TO users;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
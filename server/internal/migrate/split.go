@@ -0,0 +1,119 @@
+package migrate
+
+import "strings"
+
+// SplitStatements mimics the mysql client: DELIMITER directives are honoured so
+// routine bodies stay intact, and comments other than /*! ... */ are dropped.
+func SplitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		delimiter  = ";"
+		lineStart  = true
+	)
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); {
+		if lineStart {
+			lineStart = false
+
+			lineEnd := strings.IndexByte(script[i:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(script) - i
+			}
+
+			if next, ok := parseDelimiter(script[i : i+lineEnd]); ok && strings.TrimSpace(current.String()) == "" {
+				delimiter = next
+				i += lineEnd
+				continue
+			}
+		}
+
+		c := script[i]
+
+		switch {
+		case c == '\n':
+			current.WriteByte(c)
+			lineStart = true
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(script, i)
+			current.WriteString(script[i:end])
+			i = end
+		case c == '#' || isDashComment(script, i):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end
+			}
+		case strings.HasPrefix(script[i:], "/*") && !strings.HasPrefix(script[i:], "/*!"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 4
+			}
+		case strings.HasPrefix(script[i:], delimiter):
+			flush()
+			i += len(delimiter)
+		default:
+			current.WriteByte(c)
+			i++
+		}
+	}
+
+	flush()
+
+	return statements
+}
+
+func parseDelimiter(line string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], "DELIMITER") {
+		return "", false
+	}
+	return fields[1], true
+}
+
+func isDashComment(script string, i int) bool {
+	if !strings.HasPrefix(script[i:], "--") {
+		return false
+	}
+	if i+2 == len(script) {
+		return true
+	}
+	switch script[i+2] {
+	case ' ', '\t', '\r', '\n':
+		return true
+	default:
+		return false
+	}
+}
+
+func skipQuoted(script string, start int) int {
+	quote := script[start]
+
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+
+	return len(script)
+}
@@ -0,0 +1,77 @@
+package migrate
+
+import "testing"
+
+func TestSplitStatementsDefaultDelimiter(t *testing.T) {
+	script := `
+-- leading comment
+CREATE TABLE a (id INT);
+INSERT INTO a VALUES (1); # trailing comment
+/* block comment */
+SELECT 'semi;colon', "double;quote", ` + "`odd;name`" + `;
+`
+
+	got := SplitStatements(script)
+	expect := []string{
+		"CREATE TABLE a (id INT)",
+		"INSERT INTO a VALUES (1)",
+		"SELECT 'semi;colon', \"double;quote\", `odd;name`",
+	}
+
+	assertStatements(t, got, expect)
+}
+
+func TestSplitStatementsDelimiterBlocks(t *testing.T) {
+	script := `DELIMITER //
+
+-- Function: overdueby
+DROP FUNCTION IF EXISTS overdueby//
+CREATE FUNCTION overdueby(due_date DATE)
+RETURNS INT
+DETERMINISTIC
+BEGIN
+    RETURN DATEDIFF(CURDATE(), due_date);
+END//
+
+DELIMITER ;
+SELECT 1;
+`
+
+	got := SplitStatements(script)
+	expect := []string{
+		"DROP FUNCTION IF EXISTS overdueby",
+		"CREATE FUNCTION overdueby(due_date DATE)\nRETURNS INT\nDETERMINISTIC\nBEGIN\n    RETURN DATEDIFF(CURDATE(), due_date);\nEND",
+		"SELECT 1",
+	}
+
+	assertStatements(t, got, expect)
+}
+
+func TestSplitStatementsEscapedQuotes(t *testing.T) {
+	got := SplitStatements(`INSERT INTO t VALUES ('it''s', 'back\'slash;');SELECT 2`)
+	expect := []string{
+		`INSERT INTO t VALUES ('it''s', 'back\'slash;')`,
+		"SELECT 2",
+	}
+
+	assertStatements(t, got, expect)
+}
+
+func TestSplitStatementsKeepsVersionComments(t *testing.T) {
+	got := SplitStatements("/*!40101 SET NAMES utf8 */;")
+	assertStatements(t, got, []string{"/*!40101 SET NAMES utf8 */"})
+}
+
+func assertStatements(t *testing.T, got, expect []string) {
+	t.Helper()
+
+	if len(got) != len(expect) {
+		t.Fatalf("expected %d statements, got %d: %q", len(expect), len(got), got)
+	}
+
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Fatalf("statement %d: expected %q, got %q", i, expect[i], got[i])
+		}
+	}
+}
@@ -8,3 +8,10 @@ type DashboardStats struct {
 	BorrowedBooks  int `json:"borrowed_books"`
 	TotalStaff     int `json:"total_staff"`
 }
+
+type CirculationStats struct {
+	Checkouts int `json:"checkouts"`
+	Returns   int `json:"returns"`
+	Open      int `json:"open"`
+	Overdue   int `json:"overdue"`
+}
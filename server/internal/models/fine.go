@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Fine is money a student owes, optionally for a specific loan. It is
+// unpaid while PaidDate is empty.
+type Fine struct {
+	ID         int    `json:"fine_id"`
+	StudentID  int    `json:"student_id"`
+	BorrowID   *int   `json:"borrow_id,omitempty"`
+	Amount     Money  `json:"amount"`
+	Reason     string `json:"reason,omitempty"`
+	IssuedDate string `json:"issued_date"`
+	PaidDate   string `json:"paid_date,omitempty"`
+}
+
+// Money is an amount in cents. It scans DECIMAL(8,2) columns exactly and
+// marshals to a JSON number with two decimals, so totals never pick up
+// binary rounding.
+type Money int64
+
+// ParseMoney reads a decimal amount with at most two decimals, such as
+// "2.5" or "-10.05".
+func ParseMoney(s string) (Money, error) {
+	digits, negative := strings.CutPrefix(strings.TrimSpace(s), "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if !isDigits(whole) || len(frac) > 2 || (frac != "" && !isDigits(frac)) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	cents, _ := strconv.ParseInt((frac + "00")[:2], 10, 64)
+
+	m := Money(units*100 + cents)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) String() string {
+	sign, cents := "", int64(m)
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseMoney(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan reads a DECIMAL column. MySQL and PostgreSQL return its text;
+// SQLite stores it as a REAL or an INTEGER.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return m.scanText(string(v))
+	case string:
+		return m.scanText(v)
+	case int64:
+		*m = Money(v * 100)
+	case float64:
+		*m = Money(math.Round(v * 100))
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+func (m *Money) scanText(text string) error {
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
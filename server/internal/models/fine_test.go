@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		ok   bool
+	}{
+		{"2.50", 250, true},
+		{"2.5", 250, true},
+		{"7", 700, true},
+		{"-10.05", -1005, true},
+		{"0.1", 10, true},
+		{"1.005", 0, false},
+		{"+1.00", 0, false},
+		{"1.+5", 0, false},
+		{".50", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, %v; want %d, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMoneyScanAndMarshal(t *testing.T) {
+	var total Money
+	for _, src := range []any{[]byte("0.10"), "0.20", 0.7, int64(2)} {
+		var m Money
+		if err := m.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) returned error: %v", src, err)
+		}
+		total += m
+	}
+
+	body, err := json.Marshal(map[string]Money{"total": total, "refund": -5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"refund":-0.05,"total":3.00}` {
+		t.Fatalf("unexpected JSON %s", body)
+	}
+
+	var back map[string]Money
+	if err := json.Unmarshal(body, &back); err != nil || back["total"] != 300 || back["refund"] != -5 {
+		t.Fatalf("round trip failed: %v %v", back, err)
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+// Hold queues a student for a book. It is open while its status is Pending
+// or Ready.
+type Hold struct {
+	ID         int    `json:"hold_id"`
+	StudentID  int    `json:"student_id"`
+	BookID     int    `json:"book_id"`
+	BookTitle  string `json:"book_title"`
+	PlacedDate string `json:"placed_date"`
+	Status     string `json:"status"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Import job statuses.
+const (
+	ImportQueued    = "queued"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// ImportJob reports the progress and outcome of a bulk import.
+type ImportJob struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"`
+	DryRun bool   `json:"dry_run"`
+	Status string `json:"status"`
+	// Columns maps each imported field to the file column it was read from.
+	Columns   map[string]string `json:"columns"`
+	Total     int               `json:"total_rows"`
+	Processed int               `json:"processed_rows"`
+	// Imported counts the rows written, or that would be written in a dry
+	// run.
+	Imported   int              `json:"imported"`
+	Duplicates int              `json:"duplicates"`
+	Invalid    int              `json:"invalid"`
+	Errors     []ImportRowError `json:"errors"`
+	// Error explains why a failed job stopped. Batches saved before the
+	// failure stay imported.
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ImportRowError is a rejected row. Row is the line in the file, counting
+// the header as line 1.
+type ImportRowError struct {
+	Row int `json:"row"`
+	FieldError
+}
+
+// Done reports whether the job has stopped.
+func (j ImportJob) Done() bool {
+	return j.Status == ImportCompleted || j.Status == ImportFailed
+}
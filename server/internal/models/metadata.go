@@ -1,5 +1,18 @@
 package models
 
+// SchemaCapabilities tells clients which schema features the configured
+// database supports, so they can hide what it cannot do.
+type SchemaCapabilities struct {
+	Driver            string `json:"driver"`
+	Routines          bool   `json:"routines"`
+	ExecuteProcedures bool   `json:"execute_procedures"`
+	ExecuteFunctions  bool   `json:"execute_functions"`
+	Triggers          bool   `json:"triggers"`
+	TableDefinitions  bool   `json:"table_definitions"`
+	SchemaDiagram     bool   `json:"schema_diagram"`
+	SchemaSnapshot    bool   `json:"schema_snapshot"`
+}
+
 type SchemaTable struct {
 	Name string `json:"name"`
 }
@@ -14,3 +27,114 @@ type DbTrigger struct {
 	TableName string `json:"table"`
 	Timing    string `json:"timing"`
 }
+
+type RoutineDefinition struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	Definer         string `json:"definer"`
+	SecurityType    string `json:"security_type"`
+	IsDeterministic bool   `json:"is_deterministic"`
+	SQLDataAccess   string `json:"sql_data_access"`
+	SQLMode         string `json:"sql_mode"`
+	Returns         string `json:"returns,omitempty"`
+	Body            string `json:"body"`
+	CreateStatement string `json:"create_statement"`
+	Comment         string `json:"comment"`
+	Created         string `json:"created"`
+	LastAltered     string `json:"last_altered"`
+}
+
+type TriggerDefinition struct {
+	Name            string `json:"name"`
+	Event           string `json:"event"`
+	TableName       string `json:"table"`
+	Timing          string `json:"timing"`
+	ActionOrder     int    `json:"action_order"`
+	Definer         string `json:"definer"`
+	SQLMode         string `json:"sql_mode"`
+	Body            string `json:"body"`
+	CreateStatement string `json:"create_statement"`
+	Created         string `json:"created"`
+}
+
+type TableDefinition struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	CreateStatement string `json:"create_statement"`
+}
+
+type SchemaDiagram struct {
+	Database      string                `json:"database"`
+	Tables        []DiagramTable        `json:"tables"`
+	Relationships []DiagramRelationship `json:"relationships"`
+}
+
+type DiagramTable struct {
+	Name    string          `json:"name"`
+	Columns []DiagramColumn `json:"columns"`
+}
+
+type DiagramColumn struct {
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	ColumnType string `json:"column_type"`
+	Nullable   bool   `json:"nullable"`
+	PrimaryKey bool   `json:"primary_key"`
+	ForeignKey bool   `json:"foreign_key"`
+}
+
+type DiagramRelationship struct {
+	Name              string   `json:"name"`
+	Table             string   `json:"table"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+	Nullable          bool     `json:"nullable"`
+}
+
+type SchemaSnapshot struct {
+	Tables   []SnapshotTable   `json:"tables"`
+	Routines []SnapshotRoutine `json:"routines"`
+	Triggers []SnapshotTrigger `json:"triggers"`
+}
+
+type SnapshotTable struct {
+	Name    string           `json:"name"`
+	Columns []SnapshotColumn `json:"columns"`
+}
+
+type SnapshotColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+type SnapshotRoutine struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
+type SnapshotTrigger struct {
+	Name   string `json:"name"`
+	Table  string `json:"table"`
+	Event  string `json:"event"`
+	Timing string `json:"timing"`
+	Body   string `json:"body"`
+}
+
+type DriftReport struct {
+	Database    string            `json:"database"`
+	InSync      bool              `json:"in_sync"`
+	CheckedAt   string            `json:"checked_at"`
+	Differences []DriftDifference `json:"differences"`
+}
+
+type DriftDifference struct {
+	ObjectType string `json:"object_type"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Field      string `json:"field,omitempty"`
+	Expected   string `json:"expected,omitempty"`
+	Actual     string `json:"actual,omitempty"`
+}
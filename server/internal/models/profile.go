@@ -0,0 +1,85 @@
+package models
+
+// Sections of a student profile, selectable with ?fields=.
+const (
+	ProfileStudent       = "student"
+	ProfileLoans         = "loans"
+	ProfileHistory       = "history"
+	ProfileOverdueCount  = "overdue_count"
+	ProfileBorrowedCount = "borrowed_count"
+	ProfileFines         = "fines"
+	ProfileHolds         = "holds"
+)
+
+// ProfileSections lists every section in response order.
+var ProfileSections = []string{
+	ProfileStudent,
+	ProfileLoans,
+	ProfileHistory,
+	ProfileOverdueCount,
+	ProfileBorrowedCount,
+	ProfileFines,
+	ProfileHolds,
+}
+
+// ProfileQuery selects the sections of a profile and the page of borrowing
+// history to return. Page counts from 1.
+type ProfileQuery struct {
+	Fields   []string
+	Page     int
+	PageSize int
+}
+
+// Has reports whether the section was asked for.
+func (q ProfileQuery) Has(section string) bool {
+	for _, field := range q.Fields {
+		if field == section {
+			return true
+		}
+	}
+	return false
+}
+
+// StudentProfile gathers what the frontend shows for one student. Sections
+// that were not asked for are nil and left out of the JSON; a selected
+// section is always present, even when empty.
+type StudentProfile struct {
+	Student       *Student       `json:"student,omitempty"`
+	Loans         *[]CurrentLoan `json:"loans,omitempty"`
+	History       *LoanHistory   `json:"history,omitempty"`
+	OverdueCount  *int           `json:"overdue_count,omitempty"`
+	BorrowedCount *int           `json:"borrowed_count,omitempty"`
+	Fines         *FineSummary   `json:"fines,omitempty"`
+	Holds         *[]Hold        `json:"holds,omitempty"`
+}
+
+// CurrentLoan is a row of the get_books_borrowed_by_student procedure with
+// the loan's ID and the days it is overdue, as reported by overdueby.
+// OverdueDays is negative while the loan is not yet due.
+type CurrentLoan struct {
+	BorrowID      int    `json:"borrow_id"`
+	BookID        int    `json:"book_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	Publisher     string `json:"publisher"`
+	YearPublished int    `json:"year_published"`
+	Genre         string `json:"genre"`
+	IssueDate     string `json:"issue_date"`
+	DueDate       string `json:"due_date"`
+	Status        string `json:"borrow_status"`
+	OverdueDays   int    `json:"overdue_days"`
+}
+
+// LoanHistory is one page of every loan the student has had, newest first.
+type LoanHistory struct {
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Total    int            `json:"total"`
+	Items    []BorrowRecord `json:"items"`
+}
+
+// FineSummary lists a student's unpaid fines and what they add up to.
+type FineSummary struct {
+	Outstanding Money  `json:"outstanding"`
+	Items       []Fine `json:"items"`
+}
@@ -0,0 +1,57 @@
+package models
+
+type QueryLogEntry struct {
+	ID           int64  `json:"query_log_id"`
+	UserID       int64  `json:"user_id"`
+	UserEmail    string `json:"user_email" pii:"email"`
+	Statement    string `json:"statement"`
+	Format       string `json:"format"`
+	Status       string `json:"status"`
+	RowCount     int    `json:"row_count"`
+	DurationMS   int64  `json:"duration_ms"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+const (
+	QueryStatusOK       = "ok"
+	QueryStatusError    = "error"
+	QueryStatusRejected = "rejected"
+)
+
+type NamedQuery struct {
+	Name       string `json:"name"`
+	SQL        string `json:"sql"`
+	Parameters int    `json:"parameters"`
+}
+
+type QueryPlan struct {
+	Name             string        `json:"name,omitempty"`
+	Query            string        `json:"query"`
+	Cost             float64       `json:"cost"`
+	Root             PlanNode      `json:"root"`
+	Warnings         []PlanWarning `json:"warnings"`
+	AnalyzeSupported bool          `json:"analyze_supported"`
+	Analyze          string        `json:"analyze,omitempty"`
+	Raw              any           `json:"raw"`
+}
+
+type PlanNode struct {
+	Operation    string     `json:"operation"`
+	Table        string     `json:"table,omitempty"`
+	AccessType   string     `json:"access_type,omitempty"`
+	Key          string     `json:"key,omitempty"`
+	PossibleKeys []string   `json:"possible_keys,omitempty"`
+	RowsExamined float64    `json:"rows_examined,omitempty"`
+	RowsProduced float64    `json:"rows_produced,omitempty"`
+	Filtered     float64    `json:"filtered,omitempty"`
+	Cost         float64    `json:"cost,omitempty"`
+	Condition    string     `json:"condition,omitempty"`
+	Flags        []string   `json:"flags,omitempty"`
+	Children     []PlanNode `json:"children,omitempty"`
+}
+
+type PlanWarning struct {
+	Kind    string `json:"kind"`
+	Table   string `json:"table,omitempty"`
+	Message string `json:"message"`
+}
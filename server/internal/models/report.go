@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	ParameterString = "string"
+	ParameterInt    = "int"
+	ParameterNumber = "number"
+	ParameterBool   = "bool"
+	ParameterDate   = "date"
+)
+
+type SavedQuery struct {
+	ID           int64             `json:"saved_query_id"`
+	Slug         string            `json:"slug"`
+	Title        string            `json:"title"`
+	Description  string            `json:"description"`
+	SQL          string            `json:"sql,omitempty"`
+	Parameters   []ReportParameter `json:"parameters"`
+	RequiredRole Role              `json:"required_role"`
+	CreatedBy    int64             `json:"created_by,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type ReportParameter struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
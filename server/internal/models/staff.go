@@ -5,5 +5,6 @@ type Staff struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Position  string `json:"position"`
-	Status    string `json:"status"`
+	// Status is employment detail that viewers do not see.
+	Status string `json:"status,omitempty" pii:"internal"`
 }
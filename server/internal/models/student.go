@@ -4,6 +4,9 @@ type Student struct {
 	ID        int    `json:"student_id"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
+	Email     string `json:"email" pii:"email"`
 	Status    string `json:"status"`
+	// StatusReason and StatusEffectiveDate describe the latest status change.
+	StatusReason        string `json:"status_reason,omitempty"`
+	StatusEffectiveDate string `json:"status_effective_date,omitempty"`
 }
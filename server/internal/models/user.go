@@ -7,20 +7,32 @@ type Role string
 const (
 	RoleAdmin  Role = "admin"
 	RoleViewer Role = "viewer"
+	// RoleLibrarian reads everything a viewer does, including the personal
+	// details that viewers see masked.
+	RoleLibrarian Role = "librarian"
+	// RolePatron is a student's own account. It sees only the records of
+	// the student it is linked to.
+	RolePatron Role = "patron"
 )
 
 type User struct {
-	ID           int64     `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Role         Role      `json:"role"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int64  `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+	Role         Role   `json:"role"`
+	// StudentID is the student this account belongs to, once linked.
+	StudentID       *int       `json:"student_id,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	// VerificationHash is the SHA-256 of the pending email verification code.
+	VerificationHash      string     `json:"-"`
+	VerificationExpiresAt *time.Time `json:"-"`
 }
 
 func (r Role) IsValid() bool {
 	switch r {
-	case RoleAdmin, RoleViewer:
+	case RoleAdmin, RoleViewer, RoleLibrarian, RolePatron:
 		return true
 	default:
 		return false
@@ -30,3 +42,15 @@ func (r Role) IsValid() bool {
 func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
+
+// IsStaff reports whether the user works for the library and may see every
+// student's records.
+func (u *User) IsStaff() bool {
+	return u.Role == RoleAdmin || u.Role == RoleLibrarian || u.Role == RoleViewer
+}
+
+// Allows reports whether a user with this role may access something
+// restricted to the required role. Librarians may do anything viewers may.
+func (r Role) Allows(required Role) bool {
+	return r == RoleAdmin || r == required || (r == RoleLibrarian && required == RoleViewer)
+}
@@ -0,0 +1,157 @@
+package problem
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+
+	"github.com/karanm6505/dbms/server/internal/library"
+)
+
+// MySQL server error numbers that map onto client-facing problems.
+const (
+	errDuplicateEntry      = 1062
+	errRowIsReferenced     = 1451
+	errNoReferencedRow     = 1452
+	errColumnCannotBeNull  = 1048
+	errDataTooLong         = 1406
+	errTruncatedValue      = 1292
+	errIncorrectValue      = 1366
+	errOutOfRange          = 1264
+	errRoutineDoesNotExist = 1305
+	errWrongArgumentCount  = 1318
+	errSignalException     = 1644
+	errQueryTimeout        = 3024
+	errLockWaitTimeout     = 1205
+	errDeadlock            = 1213
+)
+
+// signalSQLState is raised by SIGNAL statements in the library's triggers
+// and procedures.
+const signalSQLState = "45000"
+
+// signals maps the MESSAGE_TEXT of SIGNAL SQLSTATE '45000' statements in
+// the schema's triggers to problem codes. Matching is by prefix so messages can carry
+// extra detail.
+var signals = []struct {
+	prefix string
+	status int
+	code   Code
+}{
+	{"Cannot borrow more than", http.StatusConflict, CodeBorrowLimitExceeded},
+	{"Cannot delete a book that is currently issued", http.StatusConflict, CodeBookIssued},
+}
+
+// FromError maps repository errors onto problems. Errors that carry no
+// client-safe meaning become a 500 with the fallback detail, so raw driver
+// text never reaches the response.
+func FromError(err error, fallback string) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return New(http.StatusNotFound, CodeNotFound, "resource not found")
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return New(http.StatusGatewayTimeout, CodeTimeout, "the database did not respond in time")
+	}
+	if errors.Is(err, errors.ErrUnsupported) {
+		return New(http.StatusNotImplemented, CodeNotSupported, "this operation is not supported by the configured database")
+	}
+	if p := fromLibrary(err); p != nil {
+		return p
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if p := fromMySQL(mysqlErr); p != nil {
+			return p
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if p := fromPostgres(pgErr); p != nil {
+			return p
+		}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		if p := fromSQLite(sqliteErr); p != nil {
+			return p
+		}
+	}
+
+	return New(http.StatusInternalServerError, CodeInternal, fallback)
+}
+
+// fromLibrary maps the lending rules that stores enforce in Go onto the same
+// problems as the equivalent trigger signals.
+func fromLibrary(err error) *Problem {
+	switch {
+	case errors.Is(err, library.ErrBorrowLimitExceeded):
+		return New(http.StatusConflict, CodeBorrowLimitExceeded, library.ErrBorrowLimitExceeded.Error())
+	case errors.Is(err, library.ErrBookUnavailable):
+		return New(http.StatusConflict, CodeBookUnavailable, library.ErrBookUnavailable.Error())
+	case errors.Is(err, library.ErrAlreadyReturned):
+		return New(http.StatusConflict, CodeConflict, library.ErrAlreadyReturned.Error())
+	case errors.Is(err, library.ErrUnknownReference):
+		return New(http.StatusBadRequest, CodeReferenceViolation, "a referenced record does not exist")
+	case errors.Is(err, library.ErrStudentIneligible):
+		return New(http.StatusConflict, CodeStudentIneligible, library.ErrStudentIneligible.Error())
+	case errors.Is(err, library.ErrInvalidTransition):
+		return New(http.StatusConflict, CodeInvalidTransition, err.Error())
+	case errors.Is(err, library.ErrOutstandingObligations):
+		return New(http.StatusConflict, CodeOutstandingObligations, err.Error())
+	case errors.Is(err, library.ErrRenewalLimit):
+		return New(http.StatusConflict, CodeRenewalLimit, library.ErrRenewalLimit.Error())
+	case errors.Is(err, library.ErrBookOnHold):
+		return New(http.StatusConflict, CodeBookOnHold, library.ErrBookOnHold.Error())
+	}
+	return nil
+}
+
+// fromSignal maps a message raised by the schema's triggers and procedures.
+func fromSignal(message string) *Problem {
+	for _, signal := range signals {
+		if strings.HasPrefix(message, signal.prefix) {
+			return New(signal.status, signal.code, message)
+		}
+	}
+	// Signal text is written by us in the schema, so it is safe to show.
+	return New(http.StatusConflict, CodeBusinessRule, message)
+}
+
+func fromMySQL(err *mysql.MySQLError) *Problem {
+	if err.Number == errSignalException || string(err.SQLState[:]) == signalSQLState {
+		return fromSignal(err.Message)
+	}
+
+	switch err.Number {
+	case errDuplicateEntry:
+		return New(http.StatusConflict, CodeAlreadyExists, "a record with the same unique value already exists")
+	case errRowIsReferenced:
+		return New(http.StatusConflict, CodeReferenceViolation, "the record is still referenced by other records")
+	case errNoReferencedRow:
+		return New(http.StatusBadRequest, CodeReferenceViolation, "a referenced record does not exist")
+	case errColumnCannotBeNull, errDataTooLong, errTruncatedValue, errIncorrectValue, errOutOfRange, errWrongArgumentCount:
+		return New(http.StatusBadRequest, CodeValidationFailed, "one or more values were rejected by the database")
+	case errRoutineDoesNotExist:
+		return New(http.StatusNotFound, CodeNotFound, "routine not found")
+	case errQueryTimeout, errLockWaitTimeout:
+		return New(http.StatusGatewayTimeout, CodeTimeout, "the database did not respond in time")
+	case errDeadlock:
+		return New(http.StatusConflict, CodeConflict, "the request conflicted with a concurrent change, please retry")
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package problem
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL SQLSTATE codes that map onto client-facing problems.
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgStringTooLong        = "22001"
+	pgNumericOutOfRange    = "22003"
+	pgInvalidDatetime      = "22007"
+	pgDatetimeOverflow     = "22008"
+	pgInvalidTextValue     = "22P02"
+	pgUndefinedFunction    = "42883"
+	pgRaiseException       = "P0001"
+	pgQueryCanceled        = "57014"
+	pgLockNotAvailable     = "55P03"
+	pgDeadlockDetected     = "40P01"
+	pgSerializationFailure = "40001"
+)
+
+// pgReferencedParent starts the message of a foreign key violation raised
+// by deleting or updating a row that is still referenced.
+const pgReferencedParent = "update or delete on table"
+
+func fromPostgres(err *pgconn.PgError) *Problem {
+	// The schema's triggers RAISE EXCEPTION with the same text as the MySQL
+	// SIGNAL statements.
+	if err.Code == pgRaiseException || err.Code == signalSQLState {
+		return fromSignal(err.Message)
+	}
+
+	switch err.Code {
+	case pgUniqueViolation:
+		return New(http.StatusConflict, CodeAlreadyExists, "a record with the same unique value already exists")
+	case pgForeignKeyViolation:
+		// PostgreSQL uses one code for both directions; the message tells a
+		// referenced parent apart from a missing one.
+		if strings.HasPrefix(err.Message, pgReferencedParent) {
+			return New(http.StatusConflict, CodeReferenceViolation, "the record is still referenced by other records")
+		}
+		return New(http.StatusBadRequest, CodeReferenceViolation, "a referenced record does not exist")
+	case pgNotNullViolation, pgCheckViolation, pgStringTooLong, pgNumericOutOfRange, pgInvalidDatetime, pgDatetimeOverflow, pgInvalidTextValue:
+		return New(http.StatusBadRequest, CodeValidationFailed, "one or more values were rejected by the database")
+	case pgUndefinedFunction:
+		return New(http.StatusNotFound, CodeNotFound, "routine not found")
+	case pgQueryCanceled, pgLockNotAvailable:
+		return New(http.StatusGatewayTimeout, CodeTimeout, "the database did not respond in time")
+	case pgDeadlockDetected, pgSerializationFailure:
+		return New(http.StatusConflict, CodeConflict, "the request conflicted with a concurrent change, please retry")
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/logging"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+const ContentType = "application/problem+json"
+
+type Code string
+
+const (
+	CodeBadRequest             Code = "BAD_REQUEST"
+	CodeValidationFailed       Code = "VALIDATION_FAILED"
+	CodeUnauthorized           Code = "UNAUTHORIZED"
+	CodeForbidden              Code = "FORBIDDEN"
+	CodeNotFound               Code = "NOT_FOUND"
+	CodeConflict               Code = "CONFLICT"
+	CodeAlreadyExists          Code = "ALREADY_EXISTS"
+	CodeReferenceViolation     Code = "REFERENCE_VIOLATION"
+	CodeBorrowLimitExceeded    Code = "BORROW_LIMIT_EXCEEDED"
+	CodeBookIssued             Code = "BOOK_ISSUED"
+	CodeBookUnavailable        Code = "BOOK_UNAVAILABLE"
+	CodeStudentIneligible      Code = "STUDENT_INELIGIBLE"
+	CodeInvalidTransition      Code = "INVALID_STATUS_TRANSITION"
+	CodeOutstandingObligations Code = "OUTSTANDING_LOANS_OR_FINES"
+	CodeRenewalLimit           Code = "RENEWAL_LIMIT_REACHED"
+	CodeBookOnHold             Code = "BOOK_ON_HOLD"
+	CodeAccountNotLinked       Code = "ACCOUNT_NOT_LINKED"
+	CodeBusinessRule           Code = "BUSINESS_RULE_VIOLATION"
+	CodeQueryFailed            Code = "QUERY_FAILED"
+	CodePayloadTooLarge        Code = "PAYLOAD_TOO_LARGE"
+	CodeTooManyRequests        Code = "TOO_MANY_REQUESTS"
+	CodeTimeout                Code = "TIMEOUT"
+	CodeUnavailable            Code = "SERVICE_UNAVAILABLE"
+	CodeNotSupported           Code = "NOT_SUPPORTED"
+	CodeInternal               Code = "INTERNAL_ERROR"
+)
+
+// Problem is an RFC 7807 problem details document. Code and Errors are
+// extension members that clients can switch on.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      Code                `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []models.FieldError `json:"errors,omitempty"`
+}
+
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   TypeURI(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Validation reports every rejected field at once.
+func Validation(errors []models.FieldError) *Problem {
+	p := New(http.StatusBadRequest, CodeValidationFailed, "request validation failed")
+	p.Errors = errors
+	return p
+}
+
+// ForStatus picks the generic code for responses that have no more specific
+// meaning than their HTTP status.
+func ForStatus(status int, detail string) *Problem {
+	return New(status, codeForStatus(status), detail)
+}
+
+// Write sends p, filling in the request ID echoed by the logging middleware
+// and, when r is given, the request path as the instance.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	body := *p
+	body.RequestID = w.Header().Get(logging.RequestIDHeader)
+	if r != nil && body.Instance == "" {
+		body.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(body.Status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (p *Problem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Code, p.Detail)
+}
+
+// TypeURI is the relative URI reference identifying a problem type, e.g.
+// /problems/borrow-limit-exceeded.
+func TypeURI(code Code) string {
+	return "/problems/" + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+func codeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,139 @@
+package problem
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/karanm6505/dbms/server/internal/library"
+)
+
+func signal(message string) *mysql.MySQLError {
+	return &mysql.MySQLError{Number: 1644, SQLState: [5]byte{'4', '5', '0', '0', '0'}, Message: message}
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   Code
+		detail string
+	}{
+		{"no rows", sql.ErrNoRows, http.StatusNotFound, CodeNotFound, "resource not found"},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, CodeTimeout, ""},
+		{"borrow limit", signal("Cannot borrow more than 3 books at a time"), http.StatusConflict, CodeBorrowLimitExceeded, "Cannot borrow more than 3 books at a time"},
+		{"book issued", fmt.Errorf("delete: %w", signal("Cannot delete a book that is currently issued or borrowed")), http.StatusConflict, CodeBookIssued, ""},
+		{"other signal", signal("Fine already paid"), http.StatusConflict, CodeBusinessRule, "Fine already paid"},
+		{"duplicate", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'Email'"}, http.StatusConflict, CodeAlreadyExists, ""},
+		{"foreign key", &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, http.StatusBadRequest, CodeReferenceViolation, ""},
+		{"bad value", &mysql.MySQLError{Number: 1366, Message: "Incorrect integer value"}, http.StatusBadRequest, CodeValidationFailed, ""},
+		{"unknown mysql", &mysql.MySQLError{Number: 1146, Message: "Table 'library.secret' doesn't exist"}, http.StatusInternalServerError, CodeInternal, "fallback"},
+		{"pg borrow limit", &pgconn.PgError{Code: "P0001", Message: "Cannot borrow more than 3 books at a time"}, http.StatusConflict, CodeBorrowLimitExceeded, "Cannot borrow more than 3 books at a time"},
+		{"pg duplicate", fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", Message: `duplicate key value violates unique constraint "users_email_key"`}), http.StatusConflict, CodeAlreadyExists, ""},
+		{"pg missing parent", &pgconn.PgError{Code: "23503", Message: `insert or update on table "borrow" violates foreign key constraint`}, http.StatusBadRequest, CodeReferenceViolation, ""},
+		{"pg referenced parent", &pgconn.PgError{Code: "23503", Message: `update or delete on table "book" violates foreign key constraint`}, http.StatusConflict, CodeReferenceViolation, ""},
+		{"pg statement timeout", &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}, http.StatusGatewayTimeout, CodeTimeout, ""},
+		{"unknown pg", &pgconn.PgError{Code: "42P01", Message: `relation "secret" does not exist`}, http.StatusInternalServerError, CodeInternal, "fallback"},
+		{"library limit", fmt.Errorf("checkout: %w", library.ErrBorrowLimitExceeded), http.StatusConflict, CodeBorrowLimitExceeded, "Cannot borrow more than 3 books at a time"},
+		{"book unavailable", library.ErrBookUnavailable, http.StatusConflict, CodeBookUnavailable, ""},
+		{"unknown reference", library.ErrUnknownReference, http.StatusBadRequest, CodeReferenceViolation, ""},
+		{"ineligible student", library.ErrStudentIneligible, http.StatusConflict, CodeStudentIneligible, ""},
+		{"invalid transition", library.CheckTransition("Graduated", "Active"), http.StatusConflict, CodeInvalidTransition, "student status change is not allowed: Graduated to Active"},
+		{"outstanding", library.OutstandingError(2, 0), http.StatusConflict, CodeOutstandingObligations, "student has open loans or unpaid fines: 2 open loans, 0 unpaid fines"},
+		{"renewal limit", library.ErrRenewalLimit, http.StatusConflict, CodeRenewalLimit, ""},
+		{"book on hold", library.ErrBookOnHold, http.StatusConflict, CodeBookOnHold, ""},
+		{"unsupported", fmt.Errorf("explain: %w", errors.ErrUnsupported), http.StatusNotImplemented, CodeNotSupported, ""},
+		{"plain", errors.New("boom"), http.StatusInternalServerError, CodeInternal, "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FromError(tt.err, "fallback")
+			if p.Status != tt.status || p.Code != tt.code {
+				t.Fatalf("expected %d %s, got %d %s", tt.status, tt.code, p.Status, p.Code)
+			}
+			if tt.detail != "" && p.Detail != tt.detail {
+				t.Fatalf("expected detail %q, got %q", tt.detail, p.Detail)
+			}
+			if p.Type != TypeURI(tt.code) {
+				t.Fatalf("unexpected type %q", p.Type)
+			}
+		})
+	}
+}
+
+func TestFromErrorDoesNotLeakDriverText(t *testing.T) {
+	err := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'secret@example.com' for key 'Email'"}
+	if p := FromError(err, "failed"); p.Detail == err.Message {
+		t.Fatalf("driver message leaked into detail: %q", p.Detail)
+	}
+}
+
+func TestTypeURI(t *testing.T) {
+	if got := TypeURI(CodeBorrowLimitExceeded); got != "/problems/borrow-limit-exceeded" {
+		t.Fatalf("unexpected type URI %q", got)
+	}
+}
+
+// SQLite errors cannot be built outside the driver, so these come from an
+// in-memory database.
+func TestFromSQLiteError(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	setup := `
+		CREATE TABLE book (Book_ID INT PRIMARY KEY, Status TEXT NOT NULL);
+		CREATE TABLE borrow (Borrow_ID INT PRIMARY KEY, Book_ID INT REFERENCES book(Book_ID));
+		CREATE TRIGGER before_borrow_limit BEFORE INSERT ON borrow
+		WHEN NEW.Borrow_ID > 3
+		BEGIN
+			SELECT RAISE(ABORT, 'Cannot borrow more than 3 books at a time');
+		END;
+		INSERT INTO book VALUES (1, 'Available');
+	`
+	if _, err := db.Exec(setup); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		statement string
+		status    int
+		code      Code
+		detail    string
+	}{
+		{"trigger", "INSERT INTO borrow VALUES (4, 1)", http.StatusConflict, CodeBorrowLimitExceeded, "Cannot borrow more than 3 books at a time"},
+		{"duplicate", "INSERT INTO book VALUES (1, 'Available')", http.StatusConflict, CodeAlreadyExists, ""},
+		{"foreign key", "INSERT INTO borrow VALUES (1, 99)", http.StatusConflict, CodeReferenceViolation, ""},
+		{"not null", "INSERT INTO book VALUES (2, NULL)", http.StatusBadRequest, CodeValidationFailed, ""},
+		{"unknown", "SELECT * FROM secret", http.StatusInternalServerError, CodeInternal, "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Exec(tt.statement)
+			if err == nil {
+				t.Fatal("expected the statement to fail")
+			}
+
+			p := FromError(fmt.Errorf("exec: %w", err), "fallback")
+			if p.Status != tt.status || p.Code != tt.code {
+				t.Fatalf("expected %d %s, got %d %s (%v)", tt.status, tt.code, p.Status, p.Code, err)
+			}
+			if tt.detail != "" && p.Detail != tt.detail {
+				t.Fatalf("expected detail %q, got %q", tt.detail, p.Detail)
+			}
+		})
+	}
+}
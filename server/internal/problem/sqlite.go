@@ -0,0 +1,45 @@
+package problem
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+func fromSQLite(err *sqlite.Error) *Problem {
+	switch err.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_TRIGGER:
+		// RAISE(ABORT, ...) in the schema's triggers carries the same text
+		// as the MySQL SIGNAL statements.
+		return fromSignal(sqliteMessage(err))
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return New(http.StatusConflict, CodeAlreadyExists, "a record with the same unique value already exists")
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		// SQLite does not say which side of the reference is missing.
+		return New(http.StatusConflict, CodeReferenceViolation, "the change would break a reference between records")
+	case sqlite3.SQLITE_CONSTRAINT_NOTNULL, sqlite3.SQLITE_CONSTRAINT_CHECK:
+		return New(http.StatusBadRequest, CodeValidationFailed, "one or more values were rejected by the database")
+	}
+
+	// The remaining checks use the primary result code, which is the low
+	// byte of an extended one.
+	switch err.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED, sqlite3.SQLITE_INTERRUPT:
+		return New(http.StatusGatewayTimeout, CodeTimeout, "the database did not respond in time")
+	}
+
+	return nil
+}
+
+// sqliteMessage strips the driver's "<result text>: " prefix and " (<code>)"
+// suffix from an error, leaving the message SQLite reported.
+func sqliteMessage(err *sqlite.Error) string {
+	message := strings.TrimSuffix(err.Error(), " ("+strconv.Itoa(err.Code())+")")
+	if _, detail, ok := strings.Cut(message, ": "); ok {
+		return detail
+	}
+	return message
+}
@@ -0,0 +1,181 @@
+package queryplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+const (
+	WarningFullTableScan = "full_table_scan"
+	WarningFullIndexScan = "full_index_scan"
+	WarningMissingIndex  = "missing_index"
+	WarningFilesort      = "filesort"
+	WarningTemporary     = "temporary_table"
+)
+
+// planKeys are the EXPLAIN FORMAT=JSON members that contain nested plan
+// operations rather than attributes of the current one.
+var planKeys = map[string]bool{
+	"query_block":                true,
+	"table":                      true,
+	"nested_loop":                true,
+	"ordering_operation":         true,
+	"grouping_operation":         true,
+	"duplicates_removal":         true,
+	"windowing":                  true,
+	"buffer_result":              true,
+	"materialized_from_subquery": true,
+	"union_result":               true,
+	"query_specifications":       true,
+	"attached_subqueries":        true,
+	"optimized_away_subqueries":  true,
+	"select_list_subqueries":     true,
+	"having_subqueries":          true,
+	"order_by_subqueries":        true,
+	"group_by_subqueries":        true,
+}
+
+// Parse normalises the document returned by EXPLAIN FORMAT=JSON into a plan
+// tree and flags the operations that usually explain a slow query.
+func Parse(document []byte) (models.PlanNode, []models.PlanWarning, any, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(document, &raw); err != nil {
+		return models.PlanNode{}, nil, nil, fmt.Errorf("invalid plan document: %w", err)
+	}
+
+	warnings := make([]models.PlanWarning, 0)
+	children := collectChildren(raw, &warnings)
+
+	root := models.PlanNode{Operation: "plan", Children: children}
+	if len(children) == 1 {
+		root = children[0]
+	}
+
+	return root, warnings, raw, nil
+}
+
+func collectChildren(value map[string]any, warnings *[]models.PlanWarning) []models.PlanNode {
+	children := make([]models.PlanNode, 0)
+
+	for _, key := range sortedKeys(value) {
+		if !planKeys[key] {
+			continue
+		}
+
+		switch child := value[key].(type) {
+		case map[string]any:
+			children = append(children, buildNode(key, child, warnings))
+		case []any:
+			node := models.PlanNode{Operation: key}
+			for _, element := range child {
+				if m, ok := element.(map[string]any); ok {
+					node.Children = append(node.Children, collectChildren(m, warnings)...)
+				}
+			}
+			children = append(children, node)
+		}
+	}
+
+	return children
+}
+
+func buildNode(operation string, value map[string]any, warnings *[]models.PlanWarning) models.PlanNode {
+	node := models.PlanNode{Operation: operation}
+
+	if costInfo, ok := value["cost_info"].(map[string]any); ok {
+		if cost, ok := toFloat(costInfo["query_cost"]); ok {
+			node.Cost = cost
+		} else if cost, ok := toFloat(costInfo["prefix_cost"]); ok {
+			node.Cost = cost
+		} else if cost, ok := toFloat(costInfo["sort_cost"]); ok {
+			node.Cost = cost
+		}
+	}
+
+	if operation == "table" {
+		node.Table, _ = value["table_name"].(string)
+		node.AccessType, _ = value["access_type"].(string)
+		node.Key, _ = value["key"].(string)
+		node.Condition, _ = value["attached_condition"].(string)
+		node.RowsExamined, _ = toFloat(value["rows_examined_per_scan"])
+		node.RowsProduced, _ = toFloat(value["rows_produced_per_join"])
+		node.Filtered, _ = toFloat(value["filtered"])
+
+		if keys, ok := value["possible_keys"].([]any); ok {
+			for _, key := range keys {
+				if name, ok := key.(string); ok {
+					node.PossibleKeys = append(node.PossibleKeys, name)
+				}
+			}
+		}
+
+		flagTable(&node, warnings)
+	}
+
+	if flag, _ := value["using_filesort"].(bool); flag {
+		node.Flags = append(node.Flags, WarningFilesort)
+		*warnings = append(*warnings, models.PlanWarning{Kind: WarningFilesort, Message: "result is sorted with a filesort"})
+	}
+
+	if flag, _ := value["using_temporary_table"].(bool); flag {
+		node.Flags = append(node.Flags, WarningTemporary)
+		*warnings = append(*warnings, models.PlanWarning{Kind: WarningTemporary, Message: "query materialises a temporary table"})
+	}
+
+	node.Children = collectChildren(value, warnings)
+
+	return node
+}
+
+func flagTable(node *models.PlanNode, warnings *[]models.PlanWarning) {
+	switch node.AccessType {
+	case "ALL":
+		node.Flags = append(node.Flags, WarningFullTableScan)
+		*warnings = append(*warnings, models.PlanWarning{
+			Kind:    WarningFullTableScan,
+			Table:   node.Table,
+			Message: fmt.Sprintf("full scan of %s examines %.0f rows", node.Table, node.RowsExamined),
+		})
+	case "index":
+		node.Flags = append(node.Flags, WarningFullIndexScan)
+		*warnings = append(*warnings, models.PlanWarning{
+			Kind:    WarningFullIndexScan,
+			Table:   node.Table,
+			Message: fmt.Sprintf("full index scan of %s using %s", node.Table, node.Key),
+		})
+	}
+
+	if node.Key == "" && node.Condition != "" && len(node.PossibleKeys) == 0 {
+		node.Flags = append(node.Flags, WarningMissingIndex)
+		*warnings = append(*warnings, models.PlanWarning{
+			Kind:    WarningMissingIndex,
+			Table:   node.Table,
+			Message: fmt.Sprintf("no index is available for the condition on %s: %s", node.Table, node.Condition),
+		})
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func sortedKeys(value map[string]any) []string {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
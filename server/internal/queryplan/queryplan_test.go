@@ -0,0 +1,89 @@
+package queryplan
+
+import "testing"
+
+const borrowPlan = `{
+  "query_block": {
+    "select_id": 1,
+    "cost_info": {"query_cost": "12.75"},
+    "ordering_operation": {
+      "using_filesort": true,
+      "nested_loop": [
+        {
+          "table": {
+            "table_name": "b",
+            "access_type": "ALL",
+            "possible_keys": ["Student_ID", "Book_ID"],
+            "rows_examined_per_scan": 10,
+            "rows_produced_per_join": 10,
+            "filtered": "100.00",
+            "cost_info": {"prefix_cost": "1.25"}
+          }
+        },
+        {
+          "table": {
+            "table_name": "bk",
+            "access_type": "eq_ref",
+            "possible_keys": ["PRIMARY"],
+            "key": "PRIMARY",
+            "rows_examined_per_scan": 1,
+            "cost_info": {"prefix_cost": "4.75"}
+          }
+        },
+        {
+          "table": {
+            "table_name": "st",
+            "access_type": "ALL",
+            "rows_examined_per_scan": 5,
+            "attached_condition": "(st.Staff_ID = b.Staff_ID)",
+            "cost_info": {"prefix_cost": "12.75"}
+          }
+        }
+      ]
+    }
+  }
+}`
+
+func TestParseBuildsTreeAndWarnings(t *testing.T) {
+	root, warnings, raw, err := Parse([]byte(borrowPlan))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if raw == nil {
+		t.Fatal("expected raw document to be returned")
+	}
+
+	if root.Operation != "query_block" || root.Cost != 12.75 {
+		t.Fatalf("unexpected root %+v", root)
+	}
+
+	ordering := root.Children[0]
+	if ordering.Operation != "ordering_operation" || len(ordering.Flags) != 1 {
+		t.Fatalf("expected ordering operation with filesort flag, got %+v", ordering)
+	}
+
+	loop := ordering.Children[0]
+	if loop.Operation != "nested_loop" || len(loop.Children) != 3 {
+		t.Fatalf("expected nested loop with 3 tables, got %+v", loop)
+	}
+
+	if loop.Children[0].Table != "b" || loop.Children[0].Filtered != 100 {
+		t.Fatalf("unexpected first table %+v", loop.Children[0])
+	}
+
+	counts := make(map[string]int)
+	for _, warning := range warnings {
+		counts[warning.Kind]++
+	}
+
+	if counts[WarningFullTableScan] != 2 || counts[WarningMissingIndex] != 1 || counts[WarningFilesort] != 1 {
+		t.Fatalf("unexpected warnings %+v", warnings)
+	}
+}
+
+func TestParseRejectsInvalidDocument(t *testing.T) {
+	if _, _, _, err := Parse([]byte("not json")); err == nil {
+		t.Fatal("expected error for invalid plan")
+	}
+}
@@ -0,0 +1,233 @@
+package redact
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// Personal fields are declared with a `pii` struct tag naming their class,
+// e.g.
+//
+//	Email  string `json:"email" pii:"email"`
+//	Status string `json:"status,omitempty" pii:"internal"`
+//
+// A role's Policy says what happens to each class when a response is
+// written. Omitted fields are zeroed, so they should be tagged omitempty.
+
+// Field classes.
+const (
+	// Email is a contact address. Masking keeps the first letter and the
+	// domain.
+	Email = "email"
+	// Internal is staff administration detail that only librarians need.
+	Internal = "internal"
+)
+
+type Action int
+
+const (
+	Show Action = iota
+	Mask
+	Omit
+)
+
+// Policy maps field classes to what is done to them. Classes it does not
+// name are shown.
+type Policy map[string]Action
+
+// policies holds the roles that see less than everything. Patrons only ever
+// read their own student, so they see it in full.
+var policies = map[models.Role]Policy{
+	models.RoleAdmin:     nil,
+	models.RoleLibrarian: nil,
+	models.RolePatron:    nil,
+	models.RoleViewer:    {Email: Mask, Internal: Omit},
+}
+
+// For returns the policy of a role. Requests without a known role get the
+// viewer policy, the strictest one.
+func For(role models.Role) Policy {
+	if policy, ok := policies[role]; ok {
+		return policy
+	}
+	return policies[models.RoleViewer]
+}
+
+// Apply returns v with the policy applied. v itself is never modified: values
+// containing tagged fields are copied, anything else is returned as is.
+func (p Policy) Apply(v any) any {
+	if len(p) == 0 || v == nil {
+		return v
+	}
+
+	src := reflect.ValueOf(v)
+	if !tagged(src.Type()) {
+		return v
+	}
+
+	dst := reflect.New(src.Type()).Elem()
+	p.copy(dst, src)
+	return dst.Interface()
+}
+
+func (p Policy) copy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Type().Elem())
+		p.copy(elem.Elem(), src.Elem())
+		dst.Set(elem)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		p.copy(elem, src.Elem())
+		dst.Set(elem)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			p.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			p.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			elem := reflect.New(src.Type().Elem()).Elem()
+			p.copy(elem, iter.Value())
+			dst.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			field := src.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if class, ok := field.Tag.Lookup("pii"); ok {
+				p.field(dst.Field(i), class)
+			} else if tagged(field.Type) {
+				p.copy(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+func (p Policy) field(value reflect.Value, class string) {
+	switch p[class] {
+	case Mask:
+		if value.Kind() == reflect.String {
+			value.SetString(mask(class, value.String()))
+		}
+	case Omit:
+		value.SetZero()
+	}
+}
+
+func mask(class, value string) string {
+	if value == "" {
+		return ""
+	}
+	if class == Email {
+		if local, domain, ok := strings.Cut(value, "@"); ok && local != "" {
+			return local[:1] + "***@" + domain
+		}
+	}
+	return "***"
+}
+
+// ColumnClass returns the class of a query result column, whose rows carry no
+// struct tags, from its name: any column mentioning email is an Email. It
+// returns "" for columns that are not personal.
+func ColumnClass(name string) string {
+	if strings.Contains(strings.ToLower(name), "email") {
+		return Email
+	}
+	return ""
+}
+
+// Row applies the policy to a query result row in place. classes holds the
+// ColumnClass of each column.
+func (p Policy) Row(classes []string, row []any) {
+	if len(p) == 0 {
+		return
+	}
+
+	for i, class := range classes {
+		if class == "" || i >= len(row) || row[i] == nil {
+			continue
+		}
+		switch p[class] {
+		case Mask:
+			if value, ok := row[i].(string); ok {
+				row[i] = mask(class, value)
+			} else {
+				row[i] = "***"
+			}
+		case Omit:
+			row[i] = nil
+		}
+	}
+}
+
+// taggedTypes caches whether a type contains a pii field anywhere inside it.
+var (
+	taggedMu    sync.Mutex
+	taggedTypes = map[reflect.Type]bool{}
+)
+
+func tagged(t reflect.Type) bool {
+	taggedMu.Lock()
+	defer taggedMu.Unlock()
+
+	if known, ok := taggedTypes[t]; ok {
+		return known
+	}
+	found := reaches(t, map[reflect.Type]bool{})
+	taggedTypes[t] = found
+	return found
+}
+
+// reaches reports whether a pii field or an interface, whose dynamic value
+// may hold one, can be reached from t.
+func reaches(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return reaches(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if _, ok := field.Tag.Lookup("pii"); ok || reaches(field.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
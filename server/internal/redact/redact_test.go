@@ -0,0 +1,88 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+type node struct {
+	Email    string `pii:"email"`
+	Children []*node
+}
+
+func TestViewerPolicy(t *testing.T) {
+	students := []models.Student{{ID: 1, Email: "james.white@example.com"}, {ID: 2, Email: "not-an-address"}}
+	staff := models.Staff{ID: 1, FirstName: "Anna", Status: "Active"}
+	profile := &models.StudentProfile{Student: &students[0]}
+
+	policy := For(models.RoleViewer)
+
+	masked := policy.Apply(students).([]models.Student)
+	if masked[0].Email != "j***@example.com" || masked[1].Email != "***" {
+		t.Fatalf("expected masked emails, got %q and %q", masked[0].Email, masked[1].Email)
+	}
+	if students[0].Email != "james.white@example.com" {
+		t.Fatalf("Apply modified its argument: %q", students[0].Email)
+	}
+
+	if got := policy.Apply(staff).(models.Staff); got.Status != "" || got.FirstName != "Anna" {
+		t.Fatalf("expected only the staff status to be removed, got %+v", got)
+	}
+
+	if got := policy.Apply(profile).(*models.StudentProfile); got.Student.Email != "j***@example.com" || got == profile {
+		t.Fatalf("expected a masked copy of the profile, got %+v", got.Student)
+	}
+
+	wrapped := policy.Apply(map[string]any{"student": students[0]}).(map[string]any)
+	if got := wrapped["student"].(models.Student); got.Email != "j***@example.com" {
+		t.Fatalf("expected the email inside the map to be masked, got %q", got.Email)
+	}
+
+	tree := &node{Email: "root@example.com", Children: []*node{{Email: "leaf@example.com"}}}
+	if got := policy.Apply(tree).(*node); got.Children[0].Email != "l***@example.com" {
+		t.Fatalf("expected recursive values to be masked, got %q", got.Children[0].Email)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	student := models.Student{Email: "james.white@example.com"}
+
+	tests := []struct {
+		role models.Role
+		want string
+	}{
+		{models.RoleAdmin, "james.white@example.com"},
+		{models.RoleLibrarian, "james.white@example.com"},
+		{models.RolePatron, "james.white@example.com"},
+		{models.RoleViewer, "j***@example.com"},
+		{"", "j***@example.com"},
+		{"owner", "j***@example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := For(tt.role).Apply(student).(models.Student); got.Email != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.role, tt.want, got.Email)
+		}
+	}
+}
+
+func TestPolicyRow(t *testing.T) {
+	columns := []string{"Student_ID", "Email", "user_email"}
+	classes := make([]string, len(columns))
+	for i, column := range columns {
+		classes[i] = ColumnClass(column)
+	}
+
+	row := []any{int64(1), "james.white@example.com", nil}
+	For(models.RoleViewer).Row(classes, row)
+	if row[0] != int64(1) || row[1] != "j***@example.com" || row[2] != nil {
+		t.Fatalf("unexpected row %v", row)
+	}
+
+	row = []any{int64(1), "james.white@example.com", "ada@example.com"}
+	For(models.RoleAdmin).Row(classes, row)
+	if row[1] != "james.white@example.com" || row[2] != "ada@example.com" {
+		t.Fatalf("expected admins to see every column, got %v", row)
+	}
+}
@@ -0,0 +1,224 @@
+package reports
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/sqlguard"
+)
+
+var (
+	slugRegex      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	parameterRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// reservedParameters are query string keys consumed by the report endpoint
+// itself rather than bound into the statement.
+var reservedParameters = map[string]bool{
+	"format": true,
+}
+
+var parameterTypes = map[string]bool{
+	models.ParameterString: true,
+	models.ParameterInt:    true,
+	models.ParameterNumber: true,
+	models.ParameterBool:   true,
+	models.ParameterDate:   true,
+}
+
+const dateLayout = "2006-01-02"
+
+type ValidationError struct {
+	Problems []models.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		messages[i] = problem.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) add(field, code, format string, args ...any) {
+	e.Problems = append(e.Problems, models.FieldError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationError) orNil() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks a saved query before it is stored: the statement must be a
+// single read-only SELECT and every :name placeholder must be declared.
+func Validate(query *models.SavedQuery) error {
+	problems := &ValidationError{}
+
+	if !slugRegex.MatchString(query.Slug) || len(query.Slug) > 100 {
+		problems.add("slug", "invalid", "slug must be lowercase letters, digits and dashes")
+	}
+	if strings.TrimSpace(query.Title) == "" {
+		problems.add("title", "required", "title is required")
+	}
+	if query.RequiredRole == "" {
+		query.RequiredRole = models.RoleViewer
+	}
+	// Reports read every row, so they are never opened up to patrons.
+	// Librarians run viewer reports, so they need no level of their own.
+	if query.RequiredRole != models.RoleAdmin && query.RequiredRole != models.RoleViewer {
+		problems.add("required_role", "invalid", "required_role must be admin or viewer")
+	}
+
+	statement, err := sqlguard.ParseReadOnly(query.SQL)
+	switch {
+	case err != nil:
+		problems.add("sql", "invalid", "sql: %v", err)
+	case statement.Kind != sqlguard.KindSelect:
+		problems.add("sql", "invalid", "sql must be a SELECT statement")
+	default:
+		query.SQL = statement.Text
+	}
+
+	declared := make(map[string]bool, len(query.Parameters))
+	for _, param := range query.Parameters {
+		if !parameterRegex.MatchString(param.Name) || reservedParameters[param.Name] {
+			problems.add("parameters", "invalid", "parameter name %q is not allowed", param.Name)
+			continue
+		}
+		if declared[param.Name] {
+			problems.add("parameters", "duplicate", "parameter %s is declared twice", param.Name)
+		}
+		declared[param.Name] = true
+
+		if !parameterTypes[param.Type] {
+			problems.add("parameters", "invalid", "parameter %s has unsupported type %q", param.Name, param.Type)
+			continue
+		}
+		if param.Default != "" {
+			if _, err := convert(param, param.Default); err != nil {
+				problems.add("parameters", "invalid", "default for %s: %v", param.Name, err)
+			}
+		}
+	}
+
+	for _, name := range sqlguard.NamedParameters(query.SQL) {
+		if !declared[name] {
+			problems.add("sql", "undeclared", "placeholder :%s is not a declared parameter", name)
+		}
+	}
+
+	return problems.orNil()
+}
+
+// Bind converts the request values into typed arguments and rewrites the
+// saved statement to use positional placeholders.
+func Bind(query models.SavedQuery, values url.Values) (string, []any, error) {
+	problems := &ValidationError{}
+	bound := make(map[string]any, len(query.Parameters))
+	known := make(map[string]bool, len(query.Parameters))
+
+	for _, param := range query.Parameters {
+		known[param.Name] = true
+
+		raw := strings.TrimSpace(values.Get(param.Name))
+		if raw == "" {
+			raw = param.Default
+		}
+
+		if raw == "" {
+			if param.Required {
+				problems.add(param.Name, "required", "%s is required", param.Name)
+			}
+			bound[param.Name] = nil
+			continue
+		}
+
+		value, err := convert(param, raw)
+		if err != nil {
+			problems.add(param.Name, "invalid", "%s: %v", param.Name, err)
+			continue
+		}
+		bound[param.Name] = value
+	}
+
+	unknown := make([]string, 0)
+	for key := range values {
+		if !known[key] && !reservedParameters[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		problems.add(key, "unknown", "unknown parameter %s", key)
+	}
+
+	if err := problems.orNil(); err != nil {
+		return "", nil, err
+	}
+
+	return sqlguard.BindNamed(query.SQL, bound)
+}
+
+func convert(param models.ReportParameter, raw string) (any, error) {
+	if len(param.Options) > 0 && !contains(param.Options, raw) {
+		return nil, fmt.Errorf("must be one of %s", strings.Join(param.Options, ", "))
+	}
+
+	switch param.Type {
+	case models.ParameterString:
+		return raw, nil
+	case models.ParameterInt:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return value, checkRange(param, float64(value))
+	case models.ParameterNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return value, checkRange(param, value)
+	case models.ParameterBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return value, nil
+	case models.ParameterDate:
+		value, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a date formatted as YYYY-MM-DD")
+		}
+		return value.Format(dateLayout), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %q", param.Type)
+	}
+}
+
+func checkRange(param models.ReportParameter, value float64) error {
+	if param.Min != nil && value < *param.Min {
+		return fmt.Errorf("must be at least %s", strconv.FormatFloat(*param.Min, 'f', -1, 64))
+	}
+	if param.Max != nil && value > *param.Max {
+		return fmt.Errorf("must be at most %s", strconv.FormatFloat(*param.Max, 'f', -1, 64))
+	}
+	return nil
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package reports
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func activeStudents() models.SavedQuery {
+	return models.SavedQuery{
+		Slug:  "most-active-students",
+		Title: "Most active students",
+		SQL:   "SELECT Student_ID FROM borrow WHERE Issue_Date >= :since LIMIT :limit",
+		Parameters: []models.ReportParameter{
+			{Name: "since", Type: models.ParameterDate, Required: true},
+			{Name: "limit", Type: models.ParameterInt, Default: "10", Min: float(1), Max: float(100)},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	query := activeStudents()
+	if err := Validate(&query); err != nil {
+		t.Fatalf("expected valid report, got %v", err)
+	}
+	if query.RequiredRole != models.RoleViewer {
+		t.Fatalf("expected default role viewer, got %q", query.RequiredRole)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*models.SavedQuery)
+	}{
+		{"bad slug", func(q *models.SavedQuery) { q.Slug = "Most Active" }},
+		{"missing title", func(q *models.SavedQuery) { q.Title = " " }},
+		{"write statement", func(q *models.SavedQuery) { q.SQL = "DELETE FROM borrow" }},
+		{"show statement", func(q *models.SavedQuery) { q.SQL = "SHOW TABLES" }},
+		{"undeclared placeholder", func(q *models.SavedQuery) { q.Parameters = q.Parameters[:1] }},
+		{"unknown type", func(q *models.SavedQuery) { q.Parameters[0].Type = "uuid" }},
+		{"bad default", func(q *models.SavedQuery) { q.Parameters[1].Default = "ten" }},
+		{"reserved name", func(q *models.SavedQuery) { q.Parameters[0].Name = "format" }},
+		{"bad role", func(q *models.SavedQuery) { q.RequiredRole = "owner" }},
+		{"patron role", func(q *models.SavedQuery) { q.RequiredRole = models.RolePatron }},
+		{"librarian role", func(q *models.SavedQuery) { q.RequiredRole = models.RoleLibrarian }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := activeStudents()
+			tt.mutate(&query)
+
+			var validationErr *ValidationError
+			if err := Validate(&query); !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBind(t *testing.T) {
+	query := activeStudents()
+
+	statement, args, err := Bind(query, url.Values{"since": {"2025-01-01"}, "format": {"csv"}})
+	if err != nil {
+		t.Fatalf("Bind returned error: %v", err)
+	}
+
+	if statement != "SELECT Student_ID FROM borrow WHERE Issue_Date >= ? LIMIT ?" {
+		t.Fatalf("unexpected statement %q", statement)
+	}
+	if len(args) != 2 || args[0] != "2025-01-01" || args[1] != int64(10) {
+		t.Fatalf("unexpected args %#v", args)
+	}
+
+	_, _, err = Bind(query, url.Values{"limit": {"500"}, "extra": {"1"}})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Problems) != 3 {
+		t.Fatalf("expected missing, range and unknown problems, got %v", validationErr.Problems)
+	}
+}
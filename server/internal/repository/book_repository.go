@@ -4,74 +4,81 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/karanm6505/dbms/server/internal/dialect"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
+const getAllBooksQuery = `
+	SELECT Book_ID, Title, Author, Publisher, Year_Published, Genre, Status
+	FROM book
+	ORDER BY Book_ID
+`
+
+const getAvailableBooksQuery = `
+	SELECT Book_ID, Title, Author, Publisher, Year_Published, Genre, Status
+	FROM book
+	WHERE Status = 'Available'
+	ORDER BY Title
+`
+
 type BookRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	reads   ReadRouter
+	dialect dialect.Dialect
 }
 
-func NewBookRepository(db *sql.DB) *BookRepository {
-	return &BookRepository{db: db}
+func NewBookRepository(db *sql.DB, opts ...Option) *BookRepository {
+	o := resolveOptions(db, opts)
+	return &BookRepository{db: db, reads: o.reads, dialect: o.dialect}
 }
 
-func (r *BookRepository) GetAll(ctx context.Context) ([]models.Book, error) {
-	const query = `
-		SELECT Book_ID, Title, Author, Publisher, Year_Published, Genre, Status
-		FROM book
-		ORDER BY Book_ID
-	`
+func (r *BookRepository) GetAll(ctx context.Context) (_ []models.Book, err error) {
+	ctx, op := track(ctx, r.dialect, "books.get_all")
+	defer op.end(&err)
 
-	rows, err := r.db.QueryContext(ctx, query)
+	books, err := r.list(ctx, getAllBooksQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	books := make([]models.Book, 0)
-
-	for rows.Next() {
-		var book models.Book
-		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Publisher, &book.YearPublished, &book.Genre, &book.Status); err != nil {
-			return nil, err
-		}
-		books = append(books, book)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
 
+	op.setRows(len(books))
 	return books, nil
 }
 
-func (r *BookRepository) GetAvailable(ctx context.Context) ([]models.Book, error) {
-	const query = `
-		SELECT Book_ID, Title, Author, Publisher, Year_Published, Genre, Status
-		FROM book
-		WHERE Status = 'Available'
-		ORDER BY Title
-	`
+func (r *BookRepository) GetAvailable(ctx context.Context) (_ []models.Book, err error) {
+	ctx, op := track(ctx, r.dialect, "books.get_available")
+	defer op.end(&err)
 
-	rows, err := r.db.QueryContext(ctx, query)
+	books, err := r.list(ctx, getAvailableBooksQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	books := make([]models.Book, 0)
+	op.setRows(len(books))
+	return books, nil
+}
 
-	for rows.Next() {
-		var book models.Book
-		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Publisher, &book.YearPublished, &book.Genre, &book.Status); err != nil {
-			return nil, err
+// list runs a query selecting book rows on the read pool.
+func (r *BookRepository) list(ctx context.Context, query string) ([]models.Book, error) {
+	var books []models.Book
+	err := r.reads.Read(ctx, func(db *sql.DB) error {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return err
 		}
-		books = append(books, book)
-	}
+		defer rows.Close()
 
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
+		books = make([]models.Book, 0)
 
-	return books, nil
+		for rows.Next() {
+			var book models.Book
+			if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Publisher, &book.YearPublished, &book.Genre, &book.Status); err != nil {
+				return err
+			}
+			books = append(books, book)
+		}
+
+		return rows.Err()
+	})
+	return books, err
 }
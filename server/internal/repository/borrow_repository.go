@@ -3,91 +3,278 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/library"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
+const selectBorrowsQuery = `
+	SELECT 
+		b.Borrow_ID,
+		b.Student_ID,
+		s.First_Name,
+		s.Last_Name,
+		b.Book_ID,
+		bk.Title,
+		b.Staff_ID,
+		st.First_Name,
+		st.Last_Name,
+		b.Issue_Date,
+		b.Due_Date,
+		b.Status
+	FROM borrow b
+	JOIN student s ON b.Student_ID = s.Student_ID
+	JOIN book bk ON b.Book_ID = bk.Book_ID
+	JOIN staff st ON b.Staff_ID = st.Staff_ID
+`
+
+const getAllBorrowsQuery = selectBorrowsQuery + `
+	ORDER BY b.Borrow_ID
+`
+
+const getBorrowByIDQuery = selectBorrowsQuery + `
+	WHERE b.Borrow_ID = ?
+`
+
 type BorrowRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	reads   ReadRouter
+	dialect dialect.Dialect
 }
 
-func NewBorrowRepository(db *sql.DB) *BorrowRepository {
-	return &BorrowRepository{db: db}
+func NewBorrowRepository(db *sql.DB, opts ...Option) *BorrowRepository {
+	o := resolveOptions(db, opts)
+	return &BorrowRepository{db: db, reads: o.reads, dialect: o.dialect}
 }
 
-func (r *BorrowRepository) GetAll(ctx context.Context) ([]models.BorrowRecord, error) {
-	const query = `
-		SELECT 
-			b.Borrow_ID,
-			b.Student_ID,
-			s.First_Name,
-			s.Last_Name,
-			b.Book_ID,
-			bk.Title,
-			b.Staff_ID,
-			st.First_Name,
-			st.Last_Name,
-			b.Issue_Date,
-			b.Due_Date,
-			b.Status
-		FROM borrow b
-		JOIN student s ON b.Student_ID = s.Student_ID
-		JOIN book bk ON b.Book_ID = bk.Book_ID
-		JOIN staff st ON b.Staff_ID = st.Staff_ID
-		ORDER BY b.Borrow_ID
-	`
+func (r *BorrowRepository) GetAll(ctx context.Context) (_ []models.BorrowRecord, err error) {
+	ctx, op := track(ctx, r.dialect, "borrows.get_all")
+	defer op.end(&err)
+
+	var records []models.BorrowRecord
+	err = r.reads.Read(ctx, func(db *sql.DB) error {
+		rows, err := db.QueryContext(ctx, getAllBorrowsQuery)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		records = make([]models.BorrowRecord, 0)
+
+		for rows.Next() {
+			record, err := scanBorrow(rows)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
 
-	rows, err := r.db.QueryContext(ctx, query)
+		return rows.Err()
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	records := make([]models.BorrowRecord, 0)
-
-	for rows.Next() {
-		var (
-			record       models.BorrowRecord
-			studentFirst string
-			studentLast  string
-			staffFirst   string
-			staffLast    string
-			issueDate    sql.NullTime
-			dueDate      sql.NullTime
-		)
-
-		if err := rows.Scan(
-			&record.ID,
-			&record.StudentID,
-			&studentFirst,
-			&studentLast,
-			&record.BookID,
-			&record.BookTitle,
-			&record.StaffID,
-			&staffFirst,
-			&staffLast,
-			&issueDate,
-			&dueDate,
-			&record.Status,
-		); err != nil {
-			return nil, err
+
+	op.setRows(len(records))
+	return records, nil
+}
+
+func (r *BorrowRepository) GetByID(ctx context.Context, id int) (_ *models.BorrowRecord, err error) {
+	ctx, op := track(ctx, r.dialect, "borrows.get_by_id")
+	defer op.end(&err)
+
+	record, err := scanBorrow(r.db.QueryRowContext(ctx, r.dialect.Rebind(getBorrowByIDQuery), id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Checkout issues a book to an active student. The book and student rows are
+// locked so two checkouts of the same copy cannot both succeed and the
+// student cannot be deactivated meanwhile; the borrow limit and the book's
+// switch to Issued are left to the schema's triggers.
+func (r *BorrowRepository) Checkout(ctx context.Context, loan *models.BorrowRecord) (err error) {
+	ctx, op := track(ctx, r.dialect, "borrows.checkout")
+	defer op.end(&err)
+
+	// A failed statement aborts a PostgreSQL transaction, so a duplicate ID
+	// retries the whole checkout rather than the insert alone.
+	return retryDuplicate(func() error { return r.checkout(ctx, loan) })
+}
+
+func (r *BorrowRepository) checkout(ctx context.Context, loan *models.BorrowRecord) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var status string
+	if err := tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT Status FROM book WHERE Book_ID = ?"+r.dialect.ForUpdate()), loan.BookID).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return library.ErrUnknownReference
+		}
+		return err
+	}
+	if !library.IsAvailable(status) {
+		return library.ErrBookUnavailable
+	}
+
+	// The student row stays locked until commit, so a concurrent status
+	// change waits for the loan to be recorded.
+	var studentStatus sql.NullString
+	if err := tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT Status FROM student WHERE Student_ID = ?"+r.dialect.ForUpdate()), loan.StudentID).Scan(&studentStatus); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return library.ErrUnknownReference
 		}
+		return err
+	}
+	if !library.CanBorrow(studentStatus.String) {
+		return library.ErrStudentIneligible
+	}
 
-		record.StudentName = studentFirst + " " + studentLast
-		record.StaffName = staffFirst + " " + staffLast
+	issued := time.Now()
+	loan.IssueDate = issued.Format(time.DateOnly)
+	if loan.DueDate == "" {
+		loan.DueDate = library.DueDate(issued).Format(time.DateOnly)
+	}
+	loan.Status = library.LoanIssued
 
-		record.IssueDate = formatDate(issueDate)
-		record.DueDate = formatDate(dueDate)
+	const insertQuery = `
+		INSERT INTO borrow (Student_ID, Book_ID, Staff_ID, Issue_Date, Due_Date, Status)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
 
-		records = append(records, record)
+	id, err := insertID(ctx, tx, r.dialect, insertQuery, "Borrow_ID", loan.StudentID, loan.BookID, loan.StaffID, loan.IssueDate, loan.DueDate, loan.Status)
+	if err != nil {
+		return err
 	}
+	loan.ID = int(id)
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return tx.Commit()
+}
+
+// Return closes an open loan. The after_borrow_return trigger makes the book
+// available again.
+func (r *BorrowRepository) Return(ctx context.Context, id int) (err error) {
+	ctx, op := track(ctx, r.dialect, "borrows.return")
+	defer op.end(&err)
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind("UPDATE borrow SET Status = ? WHERE Borrow_ID = ? AND Status <> ?"), library.LoanReturned, id, library.LoanReturned)
+	if err != nil {
+		return err
 	}
 
-	return records, nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var status string
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT Status FROM borrow WHERE Borrow_ID = ?"), id).Scan(&status); err != nil {
+		return err
+	}
+	return library.ErrAlreadyReturned
+}
+
+// Renew pushes back the due date of an open loan. The loan row is locked so
+// two renewals cannot both pass the renewal limit.
+func (r *BorrowRepository) Renew(ctx context.Context, id, studentID int) (err error) {
+	ctx, op := track(ctx, r.dialect, "borrows.renew")
+	defer op.end(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		owner, bookID, renewals int
+		dueDate                 sql.NullTime
+		status                  string
+	)
+	if err := tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT Student_ID, Book_ID, Due_Date, Status, Renew_Count FROM borrow WHERE Borrow_ID = ?"+r.dialect.ForUpdate()), id).
+		Scan(&owner, &bookID, &dueDate, &status, &renewals); err != nil {
+		return err
+	}
+	if owner != studentID {
+		return sql.ErrNoRows
+	}
+	if status == library.LoanReturned {
+		return library.ErrAlreadyReturned
+	}
+	if renewals >= library.MaxRenewals {
+		return library.ErrRenewalLimit
+	}
+
+	var studentStatus sql.NullString
+	if err := tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT Status FROM student WHERE Student_ID = ?"), studentID).Scan(&studentStatus); err != nil {
+		return err
+	}
+	if !library.CanBorrow(studentStatus.String) {
+		return library.ErrStudentIneligible
+	}
+
+	var waiting int
+	if err := tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM hold WHERE Book_ID = ? AND Student_ID <> ? AND Status IN (?, ?)"),
+		bookID, studentID, library.HoldPending, library.HoldReady).Scan(&waiting); err != nil {
+		return err
+	}
+	if waiting > 0 {
+		return library.ErrBookOnHold
+	}
+
+	due := library.RenewedDueDate(dueDate.Time, time.Now()).Format(time.DateOnly)
+	if _, err := tx.ExecContext(ctx, r.dialect.Rebind("UPDATE borrow SET Due_Date = ?, Renew_Count = Renew_Count + 1 WHERE Borrow_ID = ?"), due, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanBorrow(row rowScanner) (models.BorrowRecord, error) {
+	var (
+		record       models.BorrowRecord
+		studentFirst string
+		studentLast  string
+		staffFirst   string
+		staffLast    string
+		issueDate    sql.NullTime
+		dueDate      sql.NullTime
+	)
+
+	if err := row.Scan(
+		&record.ID,
+		&record.StudentID,
+		&studentFirst,
+		&studentLast,
+		&record.BookID,
+		&record.BookTitle,
+		&record.StaffID,
+		&staffFirst,
+		&staffLast,
+		&issueDate,
+		&dueDate,
+		&record.Status,
+	); err != nil {
+		return models.BorrowRecord{}, err
+	}
+
+	record.StudentName = studentFirst + " " + studentLast
+	record.StaffName = staffFirst + " " + staffLast
+	record.IssueDate = formatDate(issueDate)
+	record.DueDate = formatDate(dueDate)
+
+	return record, nil
 }
 
 func formatDate(value sql.NullTime) string {
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/library"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+func TestBorrowRepository_CheckoutLocksAndInserts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT Status FROM book WHERE Book_ID = \? FOR UPDATE`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"Status"}).AddRow("Available"))
+	mock.ExpectQuery(`SELECT Status FROM student WHERE Student_ID = \? FOR UPDATE`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"Status"}).AddRow("Active"))
+	mock.ExpectExec(`INSERT INTO borrow`).
+		WithArgs(2, 4, 1, sqlmock.AnyArg(), "2030-01-01", library.LoanIssued).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectCommit()
+
+	loan := &models.BorrowRecord{StudentID: 2, BookID: 4, StaffID: 1, DueDate: "2030-01-01"}
+	if err := NewBorrowRepository(db).Checkout(context.Background(), loan); err != nil {
+		t.Fatalf("Checkout returned error: %v", err)
+	}
+	if loan.ID != 11 || loan.Status != library.LoanIssued || loan.IssueDate == "" {
+		t.Fatalf("loan was not filled in: %+v", loan)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestBorrowRepository_CheckoutRejectsIssuedBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT Status FROM book`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"Status"}).AddRow("Issued"))
+	mock.ExpectRollback()
+
+	err = NewBorrowRepository(db).Checkout(context.Background(), &models.BorrowRecord{StudentID: 2, BookID: 5, StaffID: 1})
+	if !errors.Is(err, library.ErrBookUnavailable) {
+		t.Fatalf("expected ErrBookUnavailable, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestBorrowRepository_CheckoutRejectsSuspendedStudent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT Status FROM book`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"Status"}).AddRow("Available"))
+	mock.ExpectQuery(`SELECT Status FROM student`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"Status"}).AddRow("Suspended"))
+	mock.ExpectRollback()
+
+	err = NewBorrowRepository(db).Checkout(context.Background(), &models.BorrowRecord{StudentID: 2, BookID: 4, StaffID: 1})
+	if !errors.Is(err, library.ErrStudentIneligible) {
+		t.Fatalf("expected ErrStudentIneligible, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestBorrowRepository_ReturnClosedLoan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectExec(`UPDATE borrow SET Status = \?`).
+		WithArgs(library.LoanReturned, 3, library.LoanReturned).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT Status FROM borrow WHERE Borrow_ID = \?`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"Status"}).AddRow("Returned"))
+
+	if err := NewBorrowRepository(db).Return(context.Background(), 3); !errors.Is(err, library.ErrAlreadyReturned) {
+		t.Fatalf("expected ErrAlreadyReturned, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestBorrowRepository_CheckoutConcurrent(t *testing.T) {
+	db := openMigratedSQLite(t)
+	students := NewStudentRepository(db, WithDialect(dialect.SQLite))
+	borrows := NewBorrowRepository(db, WithDialect(dialect.SQLite))
+	ctx := context.Background()
+
+	rows, err := db.QueryContext(ctx, "SELECT Book_ID FROM book WHERE Status = 'Available' ORDER BY Book_ID")
+	if err != nil {
+		t.Fatalf("failed to list books: %v", err)
+	}
+	var books []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("failed to scan book: %v", err)
+		}
+		books = append(books, id)
+	}
+	rows.Close()
+
+	// Each checkout goes to its own student so the three-loan limit never
+	// decides the outcome.
+	loans := make([]*models.BorrowRecord, len(books))
+	for i, book := range books {
+		student := &models.Student{FirstName: "Borrower", LastName: "Student", Email: "borrower@example.com", Status: "Active"}
+		if err := students.Create(ctx, student); err != nil {
+			t.Fatalf("Create returned error: %v", err)
+		}
+		loans[i] = &models.BorrowRecord{StudentID: student.ID, BookID: book, StaffID: 1}
+	}
+
+	errs := make([]error, len(loans))
+	var wg sync.WaitGroup
+	for i := range loans {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = borrows.Checkout(ctx, loans[i])
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool, len(loans))
+	for i, loan := range loans {
+		if errs[i] != nil {
+			t.Fatalf("Checkout of book %d returned error: %v", loan.BookID, errs[i])
+		}
+		if seen[loan.ID] {
+			t.Fatalf("borrow ID %d was handed out twice", loan.ID)
+		}
+		seen[loan.ID] = true
+	}
+}
+
+func TestBorrowRepository_Renew(t *testing.T) {
+	db := openMigratedSQLite(t)
+	repo := NewBorrowRepository(db, WithDialect(dialect.SQLite))
+	ctx := context.Background()
+
+	// Loan 2 is student 3's copy of book 4; loan 7 is their copy of book 12.
+	if err := repo.Renew(ctx, 2, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows for another student's loan, got %v", err)
+	}
+
+	for i := 0; i < library.MaxRenewals; i++ {
+		if err := repo.Renew(ctx, 2, 3); err != nil {
+			t.Fatalf("Renew returned error: %v", err)
+		}
+	}
+	if err := repo.Renew(ctx, 2, 3); !errors.Is(err, library.ErrRenewalLimit) {
+		t.Fatalf("expected ErrRenewalLimit, got %v", err)
+	}
+
+	loan, err := repo.GetByID(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if loan.DueDate <= time.Now().Format(time.DateOnly) {
+		t.Fatalf("expected the due date to be in the future, got %s", loan.DueDate)
+	}
+
+	if _, err := db.ExecContext(ctx, "INSERT INTO hold (Student_ID, Book_ID, Placed_Date) VALUES (1, 12, '2025-10-10')"); err != nil {
+		t.Fatalf("failed to insert hold: %v", err)
+	}
+	if err := repo.Renew(ctx, 7, 3); !errors.Is(err, library.ErrBookOnHold) {
+		t.Fatalf("expected ErrBookOnHold, got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+type ConsoleRepository struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+func NewConsoleRepository(db *sql.DB, opts ...Option) *ConsoleRepository {
+	return &ConsoleRepository{db: db, dialect: resolveOptions(db, opts).dialect}
+}
+
+func (r *ConsoleRepository) QueryReadOnly(
+	ctx context.Context,
+	statement string,
+	args []any,
+	maxRows int,
+	timeout time.Duration,
+	onColumns func([]string) error,
+	onRow func([]any) error,
+) (_ int, _ bool, err error) {
+	ctx, op := track(ctx, r.dialect, "console.query_read_only")
+	defer op.end(&err)
+
+	count := 0
+	truncated := false
+
+	// Reports bind their parameters as ?. Statements without arguments are
+	// left alone so PostgreSQL's ? operators keep working.
+	if len(args) > 0 {
+		statement = r.dialect.Rebind(statement)
+	}
+
+	err = r.withReadOnlyTx(ctx, timeout, func(ctx context.Context, tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, statement, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+
+		if err := onColumns(columns); err != nil {
+			return err
+		}
+
+		values := make([]any, len(columns))
+		scanArgs := make([]any, len(columns))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+
+		for rows.Next() {
+			if maxRows > 0 && count >= maxRows {
+				truncated = true
+				break
+			}
+
+			if err := rows.Scan(scanArgs...); err != nil {
+				return err
+			}
+
+			row := make([]any, len(values))
+			for i, value := range values {
+				row[i] = normalizeDBValue(value)
+			}
+
+			if err := onRow(row); err != nil {
+				return err
+			}
+			count++
+		}
+
+		return rows.Err()
+	})
+
+	op.setRows(count)
+	return count, truncated, err
+}
+
+func (r *ConsoleRepository) Explain(
+	ctx context.Context,
+	statement string,
+	args []any,
+	analyze bool,
+	timeout time.Duration,
+) (_ *models.QueryPlan, _ []byte, err error) {
+	ctx, op := track(ctx, r.dialect, "console.explain")
+	defer op.end(&err)
+
+	// The plan parser reads MySQL's EXPLAIN FORMAT=JSON document.
+	if r.dialect != dialect.MySQL {
+		return nil, nil, fmt.Errorf("explaining SQL on %s: %w", r.dialect, errors.ErrUnsupported)
+	}
+
+	plan := &models.QueryPlan{Query: statement}
+	var document []byte
+
+	err = r.withReadOnlyTx(ctx, timeout, func(ctx context.Context, tx *sql.Tx) error {
+		var version string
+		if err := tx.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+			return err
+		}
+		plan.AnalyzeSupported = supportsExplainAnalyze(version)
+
+		if err := tx.QueryRowContext(ctx, "EXPLAIN FORMAT=JSON "+statement, args...).Scan(&document); err != nil {
+			return err
+		}
+
+		if !analyze || !plan.AnalyzeSupported {
+			return nil
+		}
+
+		rows, err := tx.QueryContext(ctx, "EXPLAIN ANALYZE "+statement, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var lines []string
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		}
+		plan.Analyze = strings.Join(lines, "\n")
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return plan, document, nil
+}
+
+func (r *ConsoleRepository) withReadOnlyTx(ctx context.Context, timeout time.Duration, fn func(context.Context, *sql.Tx) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if set, reset := r.sessionQueries(timeout); set != "" {
+		if _, err := conn.ExecContext(ctx, set); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), reset)
+	}
+
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	return fn(ctx, tx)
+}
+
+// sessionQueries returns the statements that set and reset the session
+// state for a console query: the server-side statement timeout, or for
+// SQLite, whose driver ignores read-only transactions and stops a statement
+// only when the context ends, the query_only guard.
+func (r *ConsoleRepository) sessionQueries(timeout time.Duration) (set, reset string) {
+	if r.dialect == dialect.SQLite {
+		return "PRAGMA query_only = ON", "PRAGMA query_only = OFF"
+	}
+	if timeout <= 0 {
+		return "", ""
+	}
+
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+	if r.dialect == dialect.Postgres {
+		return "SET statement_timeout = " + ms, "RESET statement_timeout"
+	}
+	return "SET SESSION max_execution_time = " + ms, "SET SESSION max_execution_time = DEFAULT"
+}
+
+func (r *ConsoleRepository) LogQuery(ctx context.Context, entry *models.QueryLogEntry) (err error) {
+	ctx, op := track(ctx, r.dialect, "console.log_query")
+	defer op.end(&err)
+
+	const query = `
+		INSERT INTO query_log (user_id, user_email, statement, format, status, row_count, duration_ms, error_message)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var errorMessage sql.NullString
+	if entry.ErrorMessage != "" {
+		errorMessage = sql.NullString{String: truncate(entry.ErrorMessage, 1000), Valid: true}
+	}
+
+	id, err := insertID(ctx, r.db, r.dialect, query, "query_log_id",
+		entry.UserID,
+		entry.UserEmail,
+		entry.Statement,
+		entry.Format,
+		entry.Status,
+		entry.RowCount,
+		entry.DurationMS,
+		errorMessage,
+	)
+	if err != nil {
+		return err
+	}
+
+	entry.ID = id
+	return nil
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
+
+func supportsExplainAnalyze(version string) bool {
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		return false
+	}
+
+	parts := strings.SplitN(strings.SplitN(version, "-", 2)[0], ".", 3)
+	if len(parts) < 3 {
+		return false
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return false
+		}
+		numbers[i] = n
+	}
+
+	switch {
+	case numbers[0] != 8:
+		return numbers[0] > 8
+	case numbers[1] != 0:
+		return numbers[1] > 0
+	default:
+		return numbers[2] >= 18
+	}
+}
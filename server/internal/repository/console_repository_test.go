@@ -0,0 +1,36 @@
+package repository
+
+import "testing"
+
+func TestSupportsExplainAnalyze(t *testing.T) {
+	tests := map[string]bool{
+		"8.0.36":                  true,
+		"8.0.17":                  false,
+		"8.4.0":                   true,
+		"9.0.1":                   true,
+		"5.7.44-log":              false,
+		"10.11.6-MariaDB-1:10.11": false,
+		"unknown":                 false,
+	}
+
+	for version, expect := range tests {
+		if got := supportsExplainAnalyze(version); got != expect {
+			t.Fatalf("supportsExplainAnalyze(%q) = %v, expected %v", version, got, expect)
+		}
+	}
+}
+
+func TestLookupQuery(t *testing.T) {
+	query, ok := LookupQuery("students.get_by_id")
+	if !ok {
+		t.Fatal("expected students.get_by_id to be registered")
+	}
+
+	if query.Parameters != 1 {
+		t.Fatalf("expected 1 parameter, got %d", query.Parameters)
+	}
+
+	if _, ok := LookupQuery("students.drop_all"); ok {
+		t.Fatal("expected unknown query lookup to fail")
+	}
+}
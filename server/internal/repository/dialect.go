@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+)
+
+const (
+	mysqlDuplicateEntry     = 1062
+	postgresUniqueViolation = "23505"
+)
+
+// isUniqueViolation reports whether err is a duplicate key error from any of
+// the drivers.
+func isUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresUniqueViolation
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+
+	return false
+}
+
+// isPrimaryKeyViolation reports whether err is a duplicate on a table's
+// primary key, as opposed to a unique business key such as an email address.
+// PostgreSQL names primary key constraints <table>_pkey unless told otherwise.
+func isPrimaryKeyViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry && strings.HasSuffix(mysqlErr.Message, "PRIMARY'")
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresUniqueViolation && strings.HasSuffix(pgErr.ConstraintName, "_pkey")
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+
+	return false
+}
+
+// mysqlQueryTimeout is MySQL's error for a statement stopped by
+// max_execution_time, and postgresQueryCanceled PostgreSQL's for one stopped
+// by statement_timeout.
+const (
+	mysqlQueryTimeout     = 3024
+	postgresQueryCanceled = "57014"
+)
+
+// IsQueryTimeout reports whether err is the database stopping a statement
+// that ran past its time limit.
+func IsQueryTimeout(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlQueryTimeout
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresQueryCanceled
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()&0xff == sqlite3.SQLITE_INTERRUPT
+	}
+
+	return false
+}
+
+// RejectedStatement returns the database's explanation when it refused the
+// statement itself: bad syntax, an unknown table or column, a type error or a
+// missing privilege. It reports false for failures of the connection or the
+// server, which say nothing about the statement.
+func RejectedStatement(err error) (string, bool) {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Message, true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Connection exceptions, insufficient resources, operator
+		// intervention, system and internal errors.
+		for _, class := range []string{"08", "53", "57", "58", "XX"} {
+			if strings.HasPrefix(pgErr.Code, class) {
+				return "", false
+			}
+		}
+		return pgErr.Message, true
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_ERROR, sqlite3.SQLITE_AUTH, sqlite3.SQLITE_READONLY, sqlite3.SQLITE_CONSTRAINT,
+			sqlite3.SQLITE_MISMATCH, sqlite3.SQLITE_RANGE, sqlite3.SQLITE_TOOBIG:
+			return sqliteErr.Error(), true
+		}
+	}
+
+	return "", false
+}
+
+// execer is the part of *sql.DB and *sql.Tx that insertID needs.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// insertID runs an INSERT and returns the generated key in idColumn.
+// PostgreSQL has no LastInsertId, so the key is read back with RETURNING.
+func insertID(ctx context.Context, db execer, d dialect.Dialect, query, idColumn string, args ...any) (int64, error) {
+	if d == dialect.Postgres {
+		var id int64
+		err := db.QueryRowContext(ctx, d.Rebind(query)+" RETURNING "+idColumn, args...).Scan(&id)
+		return id, err
+	}
+
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// generatedKeyAttempts bounds how often retryDuplicate runs an insert. A
+// generated key only collides when rows were written with explicit IDs ahead
+// of a PostgreSQL identity sequence, and every attempt draws a fresh value.
+const generatedKeyAttempts = 3
+
+// retryDuplicate runs insert again while it fails with a duplicate primary
+// key, and returns the last error once the attempts are used up. Any other
+// duplicate is a real conflict and is returned at once.
+func retryDuplicate(insert func() error) error {
+	var err error
+	for attempt := 0; attempt < generatedKeyAttempts; attempt++ {
+		if err = insert(); !isPrimaryKeyViolation(err) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRetryDuplicate(t *testing.T) {
+	db := openMigratedSQLite(t)
+
+	tests := []struct {
+		name  string
+		err   error
+		query string
+		calls int
+	}{
+		{"mysql primary key", &mysql.MySQLError{Number: mysqlDuplicateEntry, Message: "Duplicate entry '11' for key 'student.PRIMARY'"}, "", generatedKeyAttempts},
+		{"mysql unique key", &mysql.MySQLError{Number: mysqlDuplicateEntry, Message: "Duplicate entry 'a@example.com' for key 'users.email'"}, "", 1},
+		{"postgres primary key", &pgconn.PgError{Code: postgresUniqueViolation, ConstraintName: "student_pkey"}, "", generatedKeyAttempts},
+		{"postgres unique key", &pgconn.PgError{Code: postgresUniqueViolation, ConstraintName: "users_email_key"}, "", 1},
+		{"sqlite primary key", nil, "INSERT INTO student (Student_ID, First_Name, Last_Name, Email) VALUES (1, 'Ada', 'Lovelace', 'ada@example.com')", generatedKeyAttempts},
+		{"sqlite unique key", nil, "INSERT INTO users (email, password_hash, role) SELECT email, password_hash, role FROM users LIMIT 1", 1},
+		{"other error", errors.New("connection reset"), "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retryDuplicate(func() error {
+				calls++
+				if tt.query != "" {
+					_, err := db.Exec(tt.query)
+					return err
+				}
+				return tt.err
+			})
+			if err == nil || calls != tt.calls {
+				t.Fatalf("expected %d attempts and an error, got %d and %v", tt.calls, calls, err)
+			}
+			if tt.query != "" && !isUniqueViolation(err) {
+				t.Fatalf("expected a duplicate key error, got %v", err)
+			}
+		})
+	}
+}
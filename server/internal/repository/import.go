@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// Import inserts one batch of books in a transaction. A book counts as a
+// duplicate when a book with the same title and author, ignoring case, is
+// already in the catalogue.
+func (r *BookRepository) Import(ctx context.Context, books []models.Book, dryRun bool) (_ []int, err error) {
+	ctx, op := track(ctx, r.dialect, "books.import")
+	defer op.end(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	titles := make([]any, len(books))
+	for i, book := range books {
+		titles[i] = strings.ToLower(book.Title)
+	}
+
+	existing, err := existingKeys(ctx, tx, r.dialect, "SELECT lower(Title), lower(Author) FROM book WHERE lower(Title) IN (%s)", titles)
+	if err != nil {
+		return nil, err
+	}
+
+	const insertQuery = `
+		INSERT INTO book (Title, Author, Publisher, Year_Published, Genre, Status)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	duplicates := make([]int, 0)
+	for i := range books {
+		book := &books[i]
+		if existing[bookKey(book.Title, book.Author)] {
+			duplicates = append(duplicates, i)
+			continue
+		}
+		if dryRun {
+			continue
+		}
+
+		id, err := insertID(ctx, tx, r.dialect, insertQuery, "Book_ID", book.Title, book.Author, book.Publisher, book.YearPublished, book.Genre, book.Status)
+		if err != nil {
+			return nil, err
+		}
+		book.ID = int(id)
+	}
+
+	if dryRun {
+		return duplicates, nil
+	}
+	op.setRows(len(books) - len(duplicates))
+	return duplicates, tx.Commit()
+}
+
+// Import inserts one batch of students in a transaction. A student counts as
+// a duplicate when the email address, ignoring case, is already in use.
+func (r *StudentRepository) Import(ctx context.Context, students []models.Student, dryRun bool) (_ []int, err error) {
+	ctx, op := track(ctx, r.dialect, "students.import")
+	defer op.end(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	emails := make([]any, len(students))
+	for i, student := range students {
+		emails[i] = strings.ToLower(student.Email)
+	}
+
+	existing, err := existingKeys(ctx, tx, r.dialect, "SELECT lower(Email) FROM student WHERE lower(Email) IN (%s)", emails)
+	if err != nil {
+		return nil, err
+	}
+
+	const insertQuery = `
+		INSERT INTO student (First_Name, Last_Name, Email, Status)
+		VALUES (?, ?, ?, ?)
+	`
+
+	duplicates := make([]int, 0)
+	for i := range students {
+		student := &students[i]
+		if existing[strings.ToLower(student.Email)] {
+			duplicates = append(duplicates, i)
+			continue
+		}
+		if dryRun {
+			continue
+		}
+
+		id, err := insertID(ctx, tx, r.dialect, insertQuery, "Student_ID", student.FirstName, student.LastName, student.Email, student.Status)
+		if err != nil {
+			return nil, err
+		}
+		student.ID = int(id)
+	}
+
+	if dryRun {
+		return duplicates, nil
+	}
+	op.setRows(len(students) - len(duplicates))
+	return duplicates, tx.Commit()
+}
+
+// existingKeys runs a query whose %s is filled with one placeholder per
+// value and returns its rows, with their columns joined by bookKey's
+// separator.
+func existingKeys(ctx context.Context, tx *sql.Tx, d dialect.Dialect, query string, values []any) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(values) == 0 {
+		return existing, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	rows, err := tx.QueryContext(ctx, d.Rebind(fmt.Sprintf(query, placeholders)), values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		parts := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range parts {
+			dest[i] = &parts[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		key := make([]string, len(parts))
+		for i, part := range parts {
+			key[i] = part.String
+		}
+		existing[strings.Join(key, keySeparator)] = true
+	}
+
+	return existing, rows.Err()
+}
+
+const keySeparator = "\x00"
+
+// bookKey identifies a book for duplicate detection.
+func bookKey(title, author string) string {
+	return strings.ToLower(title) + keySeparator + strings.ToLower(author)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+func TestBookRepository_Import(t *testing.T) {
+	db := openMigratedSQLite(t)
+	repo := NewBookRepository(db, WithDialect(dialect.SQLite))
+	ctx := context.Background()
+
+	count := func() int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM book").Scan(&n); err != nil {
+			t.Fatalf("failed to count books: %v", err)
+		}
+		return n
+	}
+	before := count()
+
+	books := []models.Book{
+		{Title: "CLEAN CODE", Author: "robert c. martin", YearPublished: 2008, Status: "Available"},
+		{Title: "Dune", Author: "Frank Herbert", Publisher: "Chilton", YearPublished: 1965, Genre: "Fiction", Status: "Available"},
+	}
+
+	duplicates, err := repo.Import(ctx, books, true)
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if !slices.Equal(duplicates, []int{0}) || count() != before {
+		t.Fatalf("expected a dry run to report book 0 and write nothing, got %v", duplicates)
+	}
+
+	duplicates, err = repo.Import(ctx, books, false)
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if !slices.Equal(duplicates, []int{0}) || count() != before+1 || books[1].ID == 0 {
+		t.Fatalf("expected only Dune to be imported, got %v and ID %d", duplicates, books[1].ID)
+	}
+}
+
+func TestStudentRepository_Import(t *testing.T) {
+	db := openMigratedSQLite(t)
+	repo := NewStudentRepository(db, WithDialect(dialect.SQLite))
+	ctx := context.Background()
+
+	students := []models.Student{
+		{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Status: "Active"},
+		{FirstName: "James", LastName: "White", Email: "James.White@example.com", Status: "Active"},
+	}
+
+	duplicates, err := repo.Import(ctx, students, false)
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if !slices.Equal(duplicates, []int{1}) {
+		t.Fatalf("expected student 1 to be a duplicate, got %v", duplicates)
+	}
+
+	imported, err := repo.GetByID(ctx, students[0].ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if imported.Email != "ada@example.com" {
+		t.Fatalf("unexpected student %+v", imported)
+	}
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/metrics"
+	"github.com/karanm6505/dbms/server/internal/tracing"
+)
+
+// operation instruments a single repository call with a trace span and a
+// latency observation under the given query name.
+type operation struct {
+	name    string
+	started time.Time
+	span    trace.Span
+	rows    int
+}
+
+// track starts an operation. Use it as
+//
+//	ctx, op := track(ctx, r.dialect, "books.get_all")
+//	defer op.end(&err)
+//
+// with a named error result. d labels the span with the database system.
+func track(ctx context.Context, d dialect.Dialect, name string) (context.Context, *operation) {
+	ctx, span := tracing.StartQuery(ctx, d, name)
+	return ctx, &operation{name: name, started: time.Now(), span: span, rows: -1}
+}
+
+func (o *operation) setRows(rows int) {
+	o.rows = rows
+}
+
+func (o *operation) end(err *error) {
+	failure := *err
+	if errors.Is(failure, sql.ErrNoRows) {
+		failure = nil
+	}
+
+	metrics.ObserveQuery(o.name, time.Since(o.started), failure)
+	tracing.EndQuery(o.span, o.rows, failure)
+}
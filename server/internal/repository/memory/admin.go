@@ -0,0 +1,305 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/library"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/repository"
+)
+
+type users struct{ s *Store }
+
+func (r users) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if strings.EqualFold(user.Email, email) {
+			return &user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r users) GetByID(_ context.Context, id int64) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &user, nil
+}
+
+func (r users) Create(_ context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.users {
+		if strings.EqualFold(existing.Email, user.Email) {
+			return repository.ErrUserAlreadyExists
+		}
+	}
+
+	r.s.nextUserID++
+	user.ID = r.s.nextUserID
+	user.CreatedAt = r.s.now().UTC()
+	user.UpdatedAt = user.CreatedAt
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r users) LinkStudent(_ context.Context, id int64, studentID *int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if err := r.s.checkLink(id, studentID); err != nil {
+		return err
+	}
+
+	user.StudentID = studentID
+	r.s.users[id] = user
+	return nil
+}
+
+func (r users) SetVerification(_ context.Context, id int64, hash string, expires time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	expires = expires.UTC()
+	user.VerificationHash, user.VerificationExpiresAt = hash, &expires
+	if hash == "" {
+		user.VerificationExpiresAt = nil
+	}
+	r.s.users[id] = user
+	return nil
+}
+
+func (r users) ConfirmEmail(_ context.Context, id int64, studentID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if err := r.s.checkLink(id, &studentID); err != nil {
+		return err
+	}
+
+	verified := r.s.now().UTC()
+	user.EmailVerifiedAt, user.StudentID = &verified, &studentID
+	user.VerificationHash, user.VerificationExpiresAt = "", nil
+	r.s.users[id] = user
+	return nil
+}
+
+// checkLink applies the foreign key and unique constraint on
+// users.student_id. The caller holds the lock.
+func (s *Store) checkLink(userID int64, studentID *int) error {
+	if studentID == nil {
+		return nil
+	}
+	if _, ok := s.students[*studentID]; !ok {
+		return library.ErrUnknownReference
+	}
+	for _, other := range s.users {
+		if other.ID != userID && other.StudentID != nil && *other.StudentID == *studentID {
+			return repository.ErrStudentAlreadyLinked
+		}
+	}
+	return nil
+}
+
+type savedQueries struct{ s *Store }
+
+func (r savedQueries) List(context.Context) ([]models.SavedQuery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	list := make([]models.SavedQuery, 0, len(r.s.savedQueries))
+	for _, saved := range r.s.savedQueries {
+		list = append(list, cloneSavedQuery(saved))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Title < list[j].Title })
+
+	return list, nil
+}
+
+func (r savedQueries) GetBySlug(_ context.Context, slug string) (*models.SavedQuery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	saved, ok := r.s.savedQueries[slug]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	saved = cloneSavedQuery(saved)
+	return &saved, nil
+}
+
+func (r savedQueries) Create(_ context.Context, saved *models.SavedQuery) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, exists := r.s.savedQueries[saved.Slug]; exists {
+		return repository.ErrSavedQueryExists
+	}
+
+	r.s.nextSavedQueryID++
+	saved.ID = r.s.nextSavedQueryID
+	saved.CreatedAt = r.s.now().UTC()
+	saved.UpdatedAt = saved.CreatedAt
+	r.s.savedQueries[saved.Slug] = cloneSavedQuery(*saved)
+	return nil
+}
+
+func (r savedQueries) Update(_ context.Context, slug string, saved *models.SavedQuery) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.savedQueries[slug]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if _, taken := r.s.savedQueries[saved.Slug]; taken && saved.Slug != slug {
+		return repository.ErrSavedQueryExists
+	}
+
+	updated := cloneSavedQuery(*saved)
+	updated.ID = existing.ID
+	updated.CreatedBy = existing.CreatedBy
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = r.s.now().UTC()
+
+	delete(r.s.savedQueries, slug)
+	r.s.savedQueries[updated.Slug] = updated
+	return nil
+}
+
+func (r savedQueries) Delete(_ context.Context, slug string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.savedQueries[slug]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.s.savedQueries, slug)
+	return nil
+}
+
+func cloneSavedQuery(saved models.SavedQuery) models.SavedQuery {
+	saved.Parameters = slices.Clone(saved.Parameters)
+	if saved.Parameters == nil {
+		saved.Parameters = make([]models.ReportParameter, 0)
+	}
+	return saved
+}
+
+// console records what was attempted but cannot run SQL: there is no SQL
+// engine behind the store.
+type console struct{ s *Store }
+
+func (console) QueryReadOnly(context.Context, string, []any, int, time.Duration, func([]string) error, func([]any) error) (int, bool, error) {
+	return 0, false, fmt.Errorf("running SQL: %w", errors.ErrUnsupported)
+}
+
+func (console) Explain(context.Context, string, []any, bool, time.Duration) (*models.QueryPlan, []byte, error) {
+	return nil, nil, fmt.Errorf("explaining SQL: %w", errors.ErrUnsupported)
+}
+
+func (r console) LogQuery(_ context.Context, entry *models.QueryLogEntry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	entry.ID = int64(len(r.s.queryLog) + 1)
+	r.s.queryLog = append(r.s.queryLog, *entry)
+	return nil
+}
+
+// metadata describes the store's tables. It has no stored routines or
+// triggers; their rules are implemented in Go.
+type metadata struct{ s *Store }
+
+var tableNames = []string{"book", "borrow", "fine", "hold", "saved_queries", "staff", "student", "users"}
+
+func (metadata) DatabaseName() string {
+	return DatabaseName
+}
+
+func (metadata) Capabilities() models.SchemaCapabilities {
+	return models.SchemaCapabilities{Driver: "memory"}
+}
+
+func (metadata) ListTables(context.Context) ([]models.SchemaTable, error) {
+	tables := make([]models.SchemaTable, len(tableNames))
+	for i, name := range tableNames {
+		tables[i] = models.SchemaTable{Name: name}
+	}
+	return tables, nil
+}
+
+func (metadata) ListFunctions(context.Context) ([]models.DbRoutine, error) {
+	return make([]models.DbRoutine, 0), nil
+}
+
+func (metadata) ListProcedures(context.Context) ([]models.DbRoutine, error) {
+	return make([]models.DbRoutine, 0), nil
+}
+
+func (metadata) ListTriggers(context.Context) ([]models.DbTrigger, error) {
+	return make([]models.DbTrigger, 0), nil
+}
+
+func (metadata) GetTableDefinition(_ context.Context, name string) (*models.TableDefinition, error) {
+	if !slices.Contains(tableNames, strings.ToLower(name)) {
+		return nil, sql.ErrNoRows
+	}
+	return nil, fmt.Errorf("table definitions: %w", errors.ErrUnsupported)
+}
+
+func (metadata) GetFunctionDefinition(context.Context, string) (*models.RoutineDefinition, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (metadata) GetProcedureDefinition(context.Context, string) (*models.RoutineDefinition, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (metadata) GetTriggerDefinition(context.Context, string) (*models.TriggerDefinition, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (metadata) GetSchemaDiagram(context.Context) (*models.SchemaDiagram, error) {
+	return nil, fmt.Errorf("schema diagram: %w", errors.ErrUnsupported)
+}
+
+func (metadata) GetSchemaSnapshot(context.Context) (*models.SchemaSnapshot, error) {
+	return nil, fmt.Errorf("schema snapshot: %w", errors.ErrUnsupported)
+}
+
+func (metadata) ExecuteProcedure(context.Context, string, []any) ([]map[string]any, error) {
+	return nil, fmt.Errorf("stored procedures: %w", errors.ErrUnsupported)
+}
+
+func (metadata) ExecuteFunction(context.Context, string, []any) (any, error) {
+	return nil, fmt.Errorf("stored functions: %w", errors.ErrUnsupported)
+}
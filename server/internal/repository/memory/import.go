@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// Import holds the lock for the whole batch, which stands in for the SQL
+// transaction.
+func (r students) Import(_ context.Context, batch []models.Student, dryRun bool) ([]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing := make(map[string]bool, len(r.s.students))
+	for _, student := range r.s.students {
+		existing[strings.ToLower(student.Email)] = true
+	}
+
+	duplicates := make([]int, 0)
+	for i := range batch {
+		if existing[strings.ToLower(batch[i].Email)] {
+			duplicates = append(duplicates, i)
+			continue
+		}
+		if !dryRun {
+			batch[i].ID = nextID(r.s.students)
+			r.s.students[batch[i].ID] = batch[i]
+		}
+	}
+	return duplicates, nil
+}
+
+func (r books) Import(_ context.Context, batch []models.Book, dryRun bool) ([]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := func(book models.Book) string {
+		return strings.ToLower(book.Title) + "\x00" + strings.ToLower(book.Author)
+	}
+
+	existing := make(map[string]bool, len(r.s.books))
+	for _, book := range r.s.books {
+		existing[key(book)] = true
+	}
+
+	duplicates := make([]int, 0)
+	for i := range batch {
+		if existing[key(batch[i])] {
+			duplicates = append(duplicates, i)
+			continue
+		}
+		if !dryRun {
+			batch[i].ID = nextID(r.s.books)
+			r.s.books[batch[i].ID] = batch[i]
+		}
+	}
+	return duplicates, nil
+}
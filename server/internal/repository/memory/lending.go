@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/library"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/repository"
+)
+
+type students struct{ s *Store }
+
+func (r students) GetAll(context.Context) ([]models.Student, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return sortedValues(r.s.students), nil
+}
+
+func (r students) GetByID(_ context.Context, id int) (*models.Student, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	student, ok := r.s.students[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &student, nil
+}
+
+func (r students) GetByEmail(_ context.Context, email string) (*models.Student, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var found *models.Student
+	for _, student := range sortedValues(r.s.students) {
+		if !strings.EqualFold(student.Email, strings.TrimSpace(email)) {
+			continue
+		}
+		if found != nil {
+			return nil, repository.ErrAmbiguousEmail
+		}
+		found = &student
+	}
+	if found == nil {
+		return nil, sql.ErrNoRows
+	}
+	return found, nil
+}
+
+func (r students) Create(_ context.Context, student *models.Student) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	student.ID = nextID(r.s.students)
+	r.s.students[student.ID] = *student
+	return nil
+}
+
+func (r students) Update(_ context.Context, student *models.Student, override bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	current, ok := r.s.students[student.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if err := library.CheckTransition(current.Status, student.Status); err != nil {
+		return err
+	}
+
+	if !override && current.Status != student.Status && library.Deactivates(student.Status) {
+		loans, fines := r.s.openLoans(student.ID), 0
+		for _, fine := range r.s.fines {
+			if fine.StudentID == student.ID && fine.PaidDate == "" {
+				fines++
+			}
+		}
+		if loans > 0 || fines > 0 {
+			return library.OutstandingError(loans, fines)
+		}
+	}
+
+	r.s.students[student.ID] = *student
+	return nil
+}
+
+type books struct{ s *Store }
+
+func (r books) GetAll(context.Context) ([]models.Book, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return sortedValues(r.s.books), nil
+}
+
+func (r books) GetAvailable(context.Context) ([]models.Book, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	available := make([]models.Book, 0)
+	for _, book := range sortedValues(r.s.books) {
+		if library.IsAvailable(book.Status) {
+			available = append(available, book)
+		}
+	}
+	sort.SliceStable(available, func(i, j int) bool { return available[i].Title < available[j].Title })
+
+	return available, nil
+}
+
+type staff struct{ s *Store }
+
+func (r staff) GetAll(context.Context) ([]models.Staff, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return sortedValues(r.s.staff), nil
+}
+
+type borrows struct{ s *Store }
+
+func (r borrows) GetAll(context.Context) ([]models.BorrowRecord, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	records := sortedValues(r.s.loans)
+	for i := range records {
+		r.s.describe(&records[i])
+	}
+	return records, nil
+}
+
+func (r borrows) GetByID(_ context.Context, id int) (*models.BorrowRecord, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	record, ok := r.s.loans[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	r.s.describe(&record)
+	return &record, nil
+}
+
+// Checkout applies the checks in the same order as MySQL: the locked book
+// and student rows, then the before_borrow_limit trigger, then the foreign
+// keys.
+func (r borrows) Checkout(_ context.Context, loan *models.BorrowRecord) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	book, ok := r.s.books[loan.BookID]
+	if !ok {
+		return library.ErrUnknownReference
+	}
+	if !library.IsAvailable(book.Status) {
+		return library.ErrBookUnavailable
+	}
+
+	student, ok := r.s.students[loan.StudentID]
+	if !ok {
+		return library.ErrUnknownReference
+	}
+	if !library.CanBorrow(student.Status) {
+		return library.ErrStudentIneligible
+	}
+
+	if r.s.openLoans(loan.StudentID) >= library.MaxActiveLoans {
+		return library.ErrBorrowLimitExceeded
+	}
+
+	if _, ok := r.s.staff[loan.StaffID]; !ok {
+		return library.ErrUnknownReference
+	}
+
+	issued := r.s.now()
+	loan.ID = nextID(r.s.loans)
+	loan.IssueDate = issued.Format(time.DateOnly)
+	if loan.DueDate == "" {
+		loan.DueDate = library.DueDate(issued).Format(time.DateOnly)
+	}
+	loan.Status = library.LoanIssued
+
+	stored := *loan
+	stored.StudentName, stored.BookTitle, stored.StaffName = "", "", ""
+	r.s.loans[loan.ID] = stored
+
+	// after_borrow_insert
+	book.Status = library.BookIssued
+	r.s.books[book.ID] = book
+
+	return nil
+}
+
+func (r borrows) Return(_ context.Context, id int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	loan, ok := r.s.loans[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if loan.Status == library.LoanReturned {
+		return library.ErrAlreadyReturned
+	}
+
+	loan.Status = library.LoanReturned
+	r.s.loans[id] = loan
+
+	// after_borrow_return
+	if book, ok := r.s.books[loan.BookID]; ok {
+		book.Status = library.BookAvailable
+		r.s.books[book.ID] = book
+	}
+
+	return nil
+}
+
+func (r borrows) Renew(_ context.Context, id, studentID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	loan, ok := r.s.loans[id]
+	if !ok || loan.StudentID != studentID {
+		return sql.ErrNoRows
+	}
+	if loan.Status == library.LoanReturned {
+		return library.ErrAlreadyReturned
+	}
+	if r.s.renewals[id] >= library.MaxRenewals {
+		return library.ErrRenewalLimit
+	}
+	if !library.CanBorrow(r.s.students[studentID].Status) {
+		return library.ErrStudentIneligible
+	}
+	for _, hold := range r.s.holds {
+		if hold.BookID == loan.BookID && hold.StudentID != studentID && library.IsOpenHold(hold.Status) {
+			return library.ErrBookOnHold
+		}
+	}
+
+	due, _ := time.Parse(time.DateOnly, loan.DueDate)
+	loan.DueDate = library.RenewedDueDate(due, r.s.now()).Format(time.DateOnly)
+	r.s.loans[id] = loan
+	r.s.renewals[id]++
+	return nil
+}
+
+// openLoans counts the student's issued loans. The caller holds the lock.
+func (s *Store) openLoans(studentID int) int {
+	open := 0
+	for _, loan := range s.loans {
+		if loan.StudentID == studentID && loan.Status == library.LoanIssued {
+			open++
+		}
+	}
+	return open
+}
+
+// describe fills in the names the MySQL query joins in. The caller holds
+// the lock.
+func (s *Store) describe(record *models.BorrowRecord) {
+	student := s.students[record.StudentID]
+	member := s.staff[record.StaffID]
+
+	record.StudentName = student.FirstName + " " + student.LastName
+	record.BookTitle = s.books[record.BookID].Title
+	record.StaffName = member.FirstName + " " + member.LastName
+}
+
+type stats struct{ s *Store }
+
+func (r stats) GetDashboardStats(context.Context) (models.DashboardStats, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	result := models.DashboardStats{
+		TotalStudents: len(r.s.students),
+		TotalBooks:    len(r.s.books),
+		TotalStaff:    len(r.s.staff),
+	}
+
+	for _, student := range r.s.students {
+		if strings.EqualFold(student.Status, "Active") {
+			result.ActiveStudents++
+		}
+	}
+	for _, book := range r.s.books {
+		switch {
+		case library.IsAvailable(book.Status):
+			result.AvailableBooks++
+		case library.IsOnLoan(book.Status):
+			result.BorrowedBooks++
+		}
+	}
+
+	return result, nil
+}
+
+func (r stats) GetCirculationStats(context.Context) (models.CirculationStats, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	today := r.s.today()
+	result := models.CirculationStats{Checkouts: len(r.s.loans)}
+
+	for _, loan := range r.s.loans {
+		if loan.Status == library.LoanReturned {
+			result.Returns++
+			continue
+		}
+		result.Open++
+		// Dates are stored as YYYY-MM-DD, so they compare as strings.
+		if loan.DueDate < today {
+			result.Overdue++
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/library"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/repository"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s := New()
+	s.now = func() time.Time { return time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC) }
+	s.students[1] = models.Student{ID: 1, FirstName: "Ada", LastName: "Lovelace", Status: "Active"}
+	s.staff[1] = models.Staff{ID: 1, FirstName: "Anna", LastName: "Clark", Status: "Active"}
+	for id := 1; id <= 5; id++ {
+		s.books[id] = models.Book{ID: id, Title: "Book", Status: library.BookAvailable}
+	}
+	return s
+}
+
+func checkout(t *testing.T, store repository.BorrowStore, studentID, bookID int) (*models.BorrowRecord, error) {
+	t.Helper()
+
+	loan := &models.BorrowRecord{StudentID: studentID, BookID: bookID, StaffID: 1}
+	return loan, store.Checkout(context.Background(), loan)
+}
+
+func TestCheckoutIssuesBookAndReturnReleasesIt(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	loan, err := checkout(t, s.Borrows(), 1, 2)
+	if err != nil {
+		t.Fatalf("checkout failed: %v", err)
+	}
+	if loan.ID != 1 || loan.Status != library.LoanIssued || loan.IssueDate != "2025-10-01" || loan.DueDate != "2025-10-15" {
+		t.Fatalf("unexpected loan %+v", loan)
+	}
+	if s.books[2].Status != library.BookIssued {
+		t.Fatalf("expected book to be issued, got %q", s.books[2].Status)
+	}
+
+	record, err := s.Borrows().GetByID(ctx, loan.ID)
+	if err != nil || record.StudentName != "Ada Lovelace" || record.StaffName != "Anna Clark" {
+		t.Fatalf("unexpected record %+v, %v", record, err)
+	}
+
+	if _, err := checkout(t, s.Borrows(), 1, 2); !errors.Is(err, library.ErrBookUnavailable) {
+		t.Fatalf("expected issued book to be unavailable, got %v", err)
+	}
+
+	if err := s.Borrows().Return(ctx, loan.ID); err != nil {
+		t.Fatalf("return failed: %v", err)
+	}
+	if s.books[2].Status != library.BookAvailable {
+		t.Fatalf("expected book to be available again, got %q", s.books[2].Status)
+	}
+	if err := s.Borrows().Return(ctx, loan.ID); !errors.Is(err, library.ErrAlreadyReturned) {
+		t.Fatalf("expected second return to fail, got %v", err)
+	}
+	if err := s.Borrows().Return(ctx, 99); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected unknown loan to be missing, got %v", err)
+	}
+}
+
+func TestCheckoutEnforcesBorrowLimit(t *testing.T) {
+	s := newTestStore(t)
+
+	for book := 1; book <= library.MaxActiveLoans; book++ {
+		if _, err := checkout(t, s.Borrows(), 1, book); err != nil {
+			t.Fatalf("checkout %d failed: %v", book, err)
+		}
+	}
+
+	if _, err := checkout(t, s.Borrows(), 1, 4); !errors.Is(err, library.ErrBorrowLimitExceeded) {
+		t.Fatalf("expected borrow limit, got %v", err)
+	}
+	if s.books[4].Status != library.BookAvailable {
+		t.Fatal("a rejected checkout must not change the book")
+	}
+
+	if err := s.Borrows().Return(context.Background(), 1); err != nil {
+		t.Fatalf("return failed: %v", err)
+	}
+	if _, err := checkout(t, s.Borrows(), 1, 4); err != nil {
+		t.Fatalf("expected checkout after a return to succeed, got %v", err)
+	}
+}
+
+func TestCheckoutRejectsUnknownReferences(t *testing.T) {
+	s := newTestStore(t)
+
+	for _, tt := range []struct{ student, book int }{{1, 42}, {42, 1}} {
+		if _, err := checkout(t, s.Borrows(), tt.student, tt.book); !errors.Is(err, library.ErrUnknownReference) {
+			t.Errorf("student %d book %d: expected unknown reference, got %v", tt.student, tt.book, err)
+		}
+	}
+}
+
+func TestStatsFollowCirculation(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := checkout(t, s.Borrows(), 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.loans[2] = models.BorrowRecord{ID: 2, StudentID: 1, BookID: 5, StaffID: 1, DueDate: "2025-09-01", Status: library.LoanIssued}
+
+	dashboard, _ := s.Stats().GetDashboardStats(ctx)
+	if dashboard.TotalBooks != 5 || dashboard.AvailableBooks != 4 || dashboard.BorrowedBooks != 1 || dashboard.ActiveStudents != 1 {
+		t.Fatalf("unexpected dashboard stats %+v", dashboard)
+	}
+
+	circulation, _ := s.Stats().GetCirculationStats(ctx)
+	if circulation != (models.CirculationStats{Checkouts: 2, Open: 2, Overdue: 1}) {
+		t.Fatalf("unexpected circulation stats %+v", circulation)
+	}
+}
+
+func TestUsersRejectDuplicateEmail(t *testing.T) {
+	users := New().Users()
+	ctx := context.Background()
+
+	if err := users.Create(ctx, &models.User{Email: "ada@example.com", Role: models.RoleViewer}); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Create(ctx, &models.User{Email: "ADA@example.com"}); !errors.Is(err, repository.ErrUserAlreadyExists) {
+		t.Fatalf("expected duplicate to be rejected, got %v", err)
+	}
+}
+
+func TestSeededStoreMatchesSampleData(t *testing.T) {
+	s := NewSeeded()
+
+	books, _ := s.Books().GetAll(context.Background())
+	if len(books) != len(seedBooks) || books[0].ID != 1 {
+		t.Fatalf("unexpected books %v", books)
+	}
+	if _, err := s.Users().GetByEmail(context.Background(), seedAdminEmail); err != nil {
+		t.Fatalf("expected seeded admin, got %v", err)
+	}
+}
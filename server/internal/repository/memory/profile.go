@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/library"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// GetProfile builds the same sections as the SQL queries. Current loans
+// follow get_books_borrowed_by_student and the overdue days follow
+// overdueby.
+func (r students) GetProfile(_ context.Context, id int, q models.ProfileQuery) (*models.StudentProfile, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	student, ok := r.s.students[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	profile := &models.StudentProfile{}
+	if q.Has(models.ProfileStudent) {
+		profile.Student = &student
+	}
+
+	loans := r.s.currentLoans(id)
+	if q.Has(models.ProfileLoans) {
+		profile.Loans = &loans
+	}
+	if q.Has(models.ProfileOverdueCount) {
+		overdue := 0
+		for _, loan := range loans {
+			if loan.OverdueDays > 0 {
+				overdue++
+			}
+		}
+		profile.OverdueCount = &overdue
+	}
+
+	if q.Has(models.ProfileHistory) {
+		profile.History = r.s.loanHistory(id, q.Page, q.PageSize)
+	}
+
+	if q.Has(models.ProfileBorrowedCount) {
+		count := r.s.openLoans(id)
+		profile.BorrowedCount = &count
+	}
+
+	if q.Has(models.ProfileFines) {
+		summary := &models.FineSummary{Items: make([]models.Fine, 0)}
+		for _, fine := range sortedValues(r.s.fines) {
+			if fine.StudentID == id && fine.PaidDate == "" {
+				summary.Outstanding += fine.Amount
+				summary.Items = append(summary.Items, fine)
+			}
+		}
+		profile.Fines = summary
+	}
+
+	if q.Has(models.ProfileHolds) {
+		holds := make([]models.Hold, 0)
+		for _, hold := range sortedValues(r.s.holds) {
+			if hold.StudentID == id && library.IsOpenHold(hold.Status) {
+				hold.BookTitle = r.s.books[hold.BookID].Title
+				holds = append(holds, hold)
+			}
+		}
+		profile.Holds = &holds
+	}
+
+	return profile, nil
+}
+
+// currentLoans lists the student's open loans by due date. The caller holds
+// the lock.
+func (s *Store) currentLoans(studentID int) []models.CurrentLoan {
+	today, _ := time.Parse(time.DateOnly, s.today())
+
+	loans := make([]models.CurrentLoan, 0)
+	for _, loan := range sortedValues(s.loans) {
+		status := strings.ToLower(strings.TrimSpace(loan.Status))
+		if loan.StudentID != studentID || (status != "not returned" && status != "issued" && status != "borrowed") {
+			continue
+		}
+
+		book := s.books[loan.BookID]
+		current := models.CurrentLoan{
+			BorrowID:      loan.ID,
+			BookID:        book.ID,
+			Title:         book.Title,
+			Author:        book.Author,
+			Publisher:     book.Publisher,
+			YearPublished: book.YearPublished,
+			Genre:         book.Genre,
+			IssueDate:     loan.IssueDate,
+			DueDate:       loan.DueDate,
+			Status:        loan.Status,
+		}
+		if due, err := time.Parse(time.DateOnly, loan.DueDate); err == nil {
+			current.OverdueDays = int(today.Sub(due).Hours() / 24)
+		}
+		loans = append(loans, current)
+	}
+
+	sort.SliceStable(loans, func(i, j int) bool { return loans[i].DueDate < loans[j].DueDate })
+	return loans
+}
+
+// loanHistory returns one page of the student's loans, newest first. The
+// caller holds the lock.
+func (s *Store) loanHistory(studentID, page, pageSize int) *models.LoanHistory {
+	all := make([]models.BorrowRecord, 0)
+	for _, loan := range sortedValues(s.loans) {
+		if loan.StudentID == studentID {
+			s.describe(&loan)
+			all = append(all, loan)
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].IssueDate != all[j].IssueDate {
+			return all[i].IssueDate > all[j].IssueDate
+		}
+		return all[i].ID > all[j].ID
+	})
+
+	history := &models.LoanHistory{Page: page, PageSize: pageSize, Total: len(all), Items: make([]models.BorrowRecord, 0)}
+	start := (page - 1) * pageSize
+	if start < len(all) {
+		end := min(start+pageSize, len(all))
+		history.Items = append(history.Items, all[start:end]...)
+	}
+	return history
+}
@@ -0,0 +1,75 @@
+package memory
+
+import "github.com/karanm6505/dbms/server/internal/models"
+
+// The sample data loaded by migration 0002, so demo mode shows the same
+// library as a freshly migrated database.
+
+// seedAdminPasswordHash is the bcrypt hash of the sample admin's password
+// from migration 0002.
+const seedAdminPasswordHash = "$2a$10$K9sm5Sh5c6T852H9ohmmLu08px1fncihv.a6aOFYn3wKyhkCdnaeq"
+
+const seedAdminEmail = "karanm6505@gmail.com"
+
+var seedStudents = []models.Student{
+	{ID: 1, FirstName: "John", LastName: "Smith", Email: "john.smith@example.com", Status: "Active"},
+	{ID: 2, FirstName: "Emily", LastName: "Johnson", Email: "emily.johnson@example.com", Status: "Active"},
+	{ID: 3, FirstName: "Michael", LastName: "Brown", Email: "michael.brown@example.com", Status: "Active"},
+	{ID: 4, FirstName: "Sarah", LastName: "Davis", Email: "sarah.davis@example.com", Status: "Active"},
+	{ID: 5, FirstName: "David", LastName: "Wilson", Email: "david.wilson@example.com", Status: "Active"},
+	{ID: 6, FirstName: "Jessica", LastName: "Taylor", Email: "jessica.taylor@example.com", Status: "Active"},
+	{ID: 7, FirstName: "Daniel", LastName: "Anderson", Email: "daniel.anderson@example.com", Status: "Active"},
+	{ID: 8, FirstName: "Laura", LastName: "Thomas", Email: "laura.thomas@example.com", Status: "Inactive"},
+	{ID: 9, FirstName: "James", LastName: "White", Email: "james.white@example.com", Status: "Active"},
+	{ID: 10, FirstName: "Karen", LastName: "Harris", Email: "karen.harris@example.com", Status: "Active"},
+}
+
+var seedStaff = []models.Staff{
+	{ID: 1, FirstName: "Anna", LastName: "Clark", Position: "Librarian", Status: "Active"},
+	{ID: 2, FirstName: "Robert", LastName: "Miller", Position: "Supervisor", Status: "Active"},
+	{ID: 3, FirstName: "Laura", LastName: "Taylor", Position: "Technician", Status: "Active"},
+	{ID: 4, FirstName: "James", LastName: "Anderson", Position: "Librarian", Status: "On leave"},
+	{ID: 5, FirstName: "Karen", LastName: "Thomas", Position: "Assistant", Status: "Active"},
+}
+
+var seedBooks = []models.Book{
+	{ID: 1, Title: "Introduction to Algorithms", Author: "Thomas H. Cormen", Publisher: "MIT Press", YearPublished: 2022, Genre: "Computer Science", Status: "Available"},
+	{ID: 2, Title: "Database System Concepts", Author: "Abraham Silberschatz", Publisher: "McGraw Hill", YearPublished: 2020, Genre: "Database Systems", Status: "Borrowed"},
+	{ID: 3, Title: "Artificial Intelligence: A Modern Approach", Author: "Stuart Russell", Publisher: "Pearson", YearPublished: 2021, Genre: "AI", Status: "Available"},
+	{ID: 4, Title: "Clean Code", Author: "Robert C. Martin", Publisher: "Prentice Hall", YearPublished: 2018, Genre: "Programming", Status: "Available"},
+	{ID: 5, Title: "Operating System Concepts", Author: "Abraham Silberschatz", Publisher: "Wiley", YearPublished: 2022, Genre: "Operating Systems", Status: "Issued"},
+	{ID: 6, Title: "The Pragmatic Programmer", Author: "Andrew Hunt", Publisher: "Addison-Wesley", YearPublished: 2021, Genre: "Programming", Status: "Available"},
+	{ID: 7, Title: "Design Patterns", Author: "Erich Gamma", Publisher: "Addison-Wesley", YearPublished: 2020, Genre: "Software Engineering", Status: "Borrowed"},
+	{ID: 8, Title: "Computer Networks", Author: "Andrew S. Tanenbaum", Publisher: "Pearson", YearPublished: 2021, Genre: "Networking", Status: "Available"},
+	{ID: 9, Title: "Data Structures and Algorithms in Python", Author: "Michael T. Goodrich", Publisher: "Wiley", YearPublished: 2022, Genre: "Computer Science", Status: "Borrowed"},
+	{ID: 10, Title: "Python Crash Course", Author: "Eric Matthes", Publisher: "No Starch Press", YearPublished: 2023, Genre: "Programming", Status: "Available"},
+	{ID: 11, Title: "Artificial Intelligence with Python", Author: "Prateek Joshi", Publisher: "Packt", YearPublished: 2021, Genre: "AI", Status: "Available"},
+	{ID: 12, Title: "Deep Learning", Author: "Ian Goodfellow", Publisher: "MIT Press", YearPublished: 2019, Genre: "AI", Status: "Borrowed"},
+	{ID: 13, Title: "Machine Learning", Author: "Tom Mitchell", Publisher: "McGraw Hill", YearPublished: 2020, Genre: "AI", Status: "Available"},
+	{ID: 14, Title: "Python for Data Analysis", Author: "Wes McKinney", Publisher: "OReilly", YearPublished: 2022, Genre: "Data Science", Status: "Available"},
+	{ID: 15, Title: "Hands-On Machine Learning with Scikit-Learn", Author: "Aurélien Géron", Publisher: "OReilly", YearPublished: 2021, Genre: "AI", Status: "Borrowed"},
+	{ID: 16, Title: "Introduction to Computer Security", Author: "Matt Bishop", Publisher: "Pearson", YearPublished: 2020, Genre: "Security", Status: "Available"},
+	{ID: 17, Title: "Computer Organization and Design", Author: "David A. Patterson", Publisher: "Morgan Kaufmann", YearPublished: 2019, Genre: "Computer Architecture", Status: "Available"},
+	{ID: 18, Title: "Programming Pearls", Author: "Jon Bentley", Publisher: "Addison-Wesley", YearPublished: 1999, Genre: "Programming", Status: "Available"},
+	{ID: 19, Title: "Algorithms Unlocked", Author: "Thomas H. Cormen", Publisher: "MIT Press", YearPublished: 2013, Genre: "Computer Science", Status: "Available"},
+	{ID: 20, Title: "Introduction to Compiler Design", Author: "Alfred V. Aho", Publisher: "Pearson", YearPublished: 2020, Genre: "Compiler", Status: "Borrowed"},
+	{ID: 21, Title: "Modern Operating Systems", Author: "Andrew Tanenbaum", Publisher: "Pearson", YearPublished: 2021, Genre: "Operating Systems", Status: "Available"},
+	{ID: 22, Title: "The Art of Computer Programming", Author: "Donald Knuth", Publisher: "Addison-Wesley", YearPublished: 2011, Genre: "Algorithms", Status: "Available"},
+	{ID: 23, Title: "Effective Java", Author: "Joshua Bloch", Publisher: "Addison-Wesley", YearPublished: 2018, Genre: "Programming", Status: "Borrowed"},
+	{ID: 24, Title: "Computer Graphics: Principles and Practice", Author: "Foley et al.", Publisher: "Pearson", YearPublished: 2019, Genre: "Graphics", Status: "Available"},
+	{ID: 25, Title: "Introduction to Artificial Intelligence", Author: "Wolfgang Ertel", Publisher: "Springer", YearPublished: 2020, Genre: "AI", Status: "Available"},
+	{ID: 26, Title: "Database Systems", Author: "Korth", Publisher: "McGraw-Hill", YearPublished: 2020, Genre: "Computer Science", Status: "Available"},
+}
+
+var seedLoans = []models.BorrowRecord{
+	{ID: 1, StudentID: 1, BookID: 2, StaffID: 1, IssueDate: "2025-09-10", DueDate: "2025-09-25", Status: "Returned"},
+	{ID: 2, StudentID: 3, BookID: 4, StaffID: 4, IssueDate: "2025-09-15", DueDate: "2025-09-30", Status: "Issued"},
+	{ID: 3, StudentID: 5, BookID: 9, StaffID: 1, IssueDate: "2025-08-01", DueDate: "2025-08-15", Status: "Returned"},
+	{ID: 4, StudentID: 2, BookID: 5, StaffID: 2, IssueDate: "2025-09-28", DueDate: "2025-10-10", Status: "Issued"},
+	{ID: 5, StudentID: 4, BookID: 14, StaffID: 1, IssueDate: "2025-09-05", DueDate: "2025-09-20", Status: "Returned"},
+	{ID: 6, StudentID: 1, BookID: 7, StaffID: 5, IssueDate: "2025-09-12", DueDate: "2025-09-27", Status: "Issued"},
+	{ID: 7, StudentID: 3, BookID: 12, StaffID: 1, IssueDate: "2025-09-22", DueDate: "2025-10-05", Status: "Issued"},
+	{ID: 8, StudentID: 5, BookID: 20, StaffID: 4, IssueDate: "2025-09-25", DueDate: "2025-10-10", Status: "Issued"},
+	{ID: 9, StudentID: 6, BookID: 1, StaffID: 5, IssueDate: "2025-10-07", DueDate: "2025-10-21", Status: "Returned"},
+	{ID: 10, StudentID: 3, BookID: 5, StaffID: 1, IssueDate: "2025-10-07", DueDate: "2025-10-21", Status: "Issued"},
+}
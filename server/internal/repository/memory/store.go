@@ -0,0 +1,143 @@
+// Package memory is an in-process implementation of the repository stores.
+// It applies the same lending rules as the MySQL schema's triggers, so
+// handlers can be tested without a database and the API can run in demo mode.
+// Nothing is persisted.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/migrate"
+	"github.com/karanm6505/dbms/server/internal/models"
+	"github.com/karanm6505/dbms/server/internal/repository"
+)
+
+// DatabaseName is reported by the metadata store in place of a schema name.
+const DatabaseName = "memory"
+
+// Store holds every table behind one lock, so rules that span tables, like
+// a checkout updating the book it issues, are applied atomically.
+type Store struct {
+	mu sync.RWMutex
+
+	students     map[int]models.Student
+	books        map[int]models.Book
+	staff        map[int]models.Staff
+	loans        map[int]models.BorrowRecord
+	renewals     map[int]int
+	fines        map[int]models.Fine
+	holds        map[int]models.Hold
+	users        map[int64]models.User
+	savedQueries map[string]models.SavedQuery
+	queryLog     []models.QueryLogEntry
+
+	nextUserID       int64
+	nextSavedQueryID int64
+
+	now func() time.Time
+}
+
+// New returns an empty store.
+func New() *Store {
+	return &Store{
+		students:     make(map[int]models.Student),
+		books:        make(map[int]models.Book),
+		staff:        make(map[int]models.Staff),
+		loans:        make(map[int]models.BorrowRecord),
+		renewals:     make(map[int]int),
+		fines:        make(map[int]models.Fine),
+		holds:        make(map[int]models.Hold),
+		users:        make(map[int64]models.User),
+		savedQueries: make(map[string]models.SavedQuery),
+		now:          time.Now,
+	}
+}
+
+// NewSeeded returns a store loaded with the sample library, including the
+// sample admin account.
+func NewSeeded() *Store {
+	s := New()
+	for _, student := range seedStudents {
+		s.students[student.ID] = student
+	}
+	for _, member := range seedStaff {
+		s.staff[member.ID] = member
+	}
+	for _, book := range seedBooks {
+		s.books[book.ID] = book
+	}
+	for _, loan := range seedLoans {
+		s.loans[loan.ID] = loan
+	}
+
+	s.nextUserID++
+	created := s.now().UTC()
+	s.users[s.nextUserID] = models.User{
+		ID:           s.nextUserID,
+		Email:        seedAdminEmail,
+		PasswordHash: seedAdminPasswordHash,
+		Role:         models.RoleAdmin,
+		CreatedAt:    created,
+		UpdatedAt:    created,
+	}
+
+	return s
+}
+
+func (s *Store) Students() repository.StudentStore        { return students{s} }
+func (s *Store) Books() repository.BookStore              { return books{s} }
+func (s *Store) Staff() repository.StaffStore             { return staff{s} }
+func (s *Store) Borrows() repository.BorrowStore          { return borrows{s} }
+func (s *Store) Stats() repository.StatsStore             { return stats{s} }
+func (s *Store) Metadata() repository.MetadataStore       { return metadata{s} }
+func (s *Store) Users() repository.UserStore              { return users{s} }
+func (s *Store) Console() repository.ConsoleStore         { return console{s} }
+func (s *Store) SavedQueries() repository.SavedQueryStore { return savedQueries{s} }
+
+// Health stands in for the database connection and migrator in the
+// readiness probe.
+func (s *Store) Health() Health { return Health{} }
+
+// Health is always reachable, has no connection pool and reports no
+// migrations because the store's schema is built in.
+type Health struct{}
+
+func (Health) PingContext(context.Context) error { return nil }
+
+func (Health) Stats() sql.DBStats { return sql.DBStats{} }
+
+func (Health) Status(context.Context) ([]migrate.Status, error) { return nil, nil }
+
+func (s *Store) today() string {
+	return s.now().Format(time.DateOnly)
+}
+
+// sortedValues returns the map's values ordered by key, matching the
+// ORDER BY on the primary key in the MySQL queries.
+func sortedValues[K int | int64, V any](values map[K]V) []V {
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	sorted := make([]V, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, values[key])
+	}
+	return sorted
+}
+
+func nextID[V any](values map[int]V) int {
+	next := 1
+	for id := range values {
+		if id >= next {
+			next = id + 1
+		}
+	}
+	return next
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// PostgreSQL procedures cannot return rows, so the schema's row-returning
+// MySQL procedures are set-returning functions there. They are listed,
+// described and run as procedures so the API looks the same on both
+// servers. Trigger functions are reported through their triggers.
+const (
+	pgFunctionFilter  = `p.prokind = 'f' AND NOT p.proretset AND p.prorettype <> 'trigger'::regtype`
+	pgProcedureFilter = `(p.prokind = 'p' OR (p.prokind = 'f' AND p.proretset))`
+)
+
+// pgRelation resolves the ? table name in the current schema.
+const pgRelation = `(quote_ident(current_schema()) || '.' || quote_ident(?))::regclass`
+
+var postgresMetadataQueries = metadataQueries{
+	listTables: `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = current_schema()
+		ORDER BY table_name
+	`,
+	listFunctions: `
+		SELECT DISTINCT p.proname
+		FROM pg_proc p
+		WHERE p.pronamespace = current_schema()::regnamespace AND ` + pgFunctionFilter + `
+		ORDER BY p.proname
+	`,
+	listProcedures: `
+		SELECT DISTINCT p.proname
+		FROM pg_proc p
+		WHERE p.pronamespace = current_schema()::regnamespace AND ` + pgProcedureFilter + `
+		ORDER BY p.proname
+	`,
+	listTriggers: `
+		SELECT trigger_name, event_manipulation, event_object_table, action_timing
+		FROM information_schema.triggers
+		WHERE trigger_schema = current_schema()
+		ORDER BY trigger_name
+	`,
+	diagramColumns: `
+		SELECT c.table_name, c.column_name, c.data_type, format_type(a.atttypid, a.atttypmod), c.is_nullable,
+			CASE WHEN EXISTS (
+				SELECT 1 FROM pg_index i
+				WHERE i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY (i.indkey)
+			) THEN 'PRI' ELSE '' END
+		FROM information_schema.columns c
+		JOIN information_schema.tables t
+			ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		JOIN pg_attribute a
+			ON a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass
+			AND a.attname = c.column_name
+		WHERE c.table_schema = current_schema() AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position
+	`,
+	diagramKeys: `
+		SELECT con.conname, cl.relname, a.attname, ref.relname, ra.attname
+		FROM pg_constraint con
+		JOIN pg_class cl ON cl.oid = con.conrelid
+		JOIN pg_class ref ON ref.oid = con.confrelid
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refnum, position)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum
+		WHERE con.contype = 'f' AND cl.relnamespace = current_schema()::regnamespace
+		ORDER BY cl.relname, con.conname, k.position
+	`,
+}
+
+func (r *MetadataRepository) postgresRoutineDefinition(ctx context.Context, routineType, name string) (*models.RoutineDefinition, error) {
+	filter := pgFunctionFilter
+	if routineType == "PROCEDURE" {
+		filter = pgProcedureFilter
+	}
+
+	query := `
+		SELECT p.proname,
+			CASE WHEN ` + pgProcedureFilter + ` THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+			pg_get_userbyid(p.proowner),
+			CASE WHEN p.prosecdef THEN 'DEFINER' ELSE 'INVOKER' END,
+			p.provolatile = 'i',
+			CASE p.provolatile WHEN 'i' THEN 'IMMUTABLE' WHEN 's' THEN 'STABLE' ELSE 'VOLATILE' END,
+			pg_get_function_result(p.oid),
+			p.prosrc,
+			COALESCE(obj_description(p.oid, 'pg_proc'), ''),
+			pg_get_functiondef(p.oid)
+		FROM pg_proc p
+		WHERE p.pronamespace = current_schema()::regnamespace AND p.proname = ? AND ` + filter + `
+		ORDER BY p.oid
+		LIMIT 1
+	`
+
+	var (
+		routine models.RoutineDefinition
+		returns sql.NullString
+	)
+
+	// Unquoted identifiers are folded to lower case by PostgreSQL.
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), strings.ToLower(name)).Scan(
+		&routine.Name,
+		&routine.Type,
+		&routine.Definer,
+		&routine.SecurityType,
+		&routine.IsDeterministic,
+		&routine.SQLDataAccess,
+		&returns,
+		&routine.Body,
+		&routine.Comment,
+		&routine.CreateStatement,
+	); err != nil {
+		return nil, err
+	}
+
+	routine.Returns = returns.String
+	return &routine, nil
+}
+
+func (r *MetadataRepository) postgresTriggerDefinition(ctx context.Context, name string) (*models.TriggerDefinition, error) {
+	const query = `
+		SELECT it.trigger_name, it.event_manipulation, it.event_object_table, it.action_timing, it.action_order,
+			pg_get_userbyid(c.relowner), p.prosrc, pg_get_triggerdef(tg.oid, true)
+		FROM information_schema.triggers it
+		JOIN pg_class c ON c.relname = it.event_object_table AND c.relnamespace = current_schema()::regnamespace
+		JOIN pg_trigger tg ON tg.tgrelid = c.oid AND tg.tgname = it.trigger_name
+		JOIN pg_proc p ON p.oid = tg.tgfoid
+		WHERE it.trigger_schema = current_schema() AND it.trigger_name = ?
+		ORDER BY it.event_manipulation
+		LIMIT 1
+	`
+
+	var trigger models.TriggerDefinition
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), strings.ToLower(name)).Scan(
+		&trigger.Name,
+		&trigger.Event,
+		&trigger.TableName,
+		&trigger.Timing,
+		&trigger.ActionOrder,
+		&trigger.Definer,
+		&trigger.Body,
+		&trigger.CreateStatement,
+	); err != nil {
+		return nil, err
+	}
+
+	return &trigger, nil
+}
+
+// postgresTableDefinition rebuilds a CREATE statement from the catalog, as
+// PostgreSQL has no SHOW CREATE TABLE.
+func (r *MetadataRepository) postgresTableDefinition(ctx context.Context, name string) (*models.TableDefinition, error) {
+	name = strings.ToLower(name)
+
+	const tableQuery = `
+		SELECT table_name, table_type
+		FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name = ?
+	`
+
+	var table models.TableDefinition
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(tableQuery), name).Scan(&table.Name, &table.Type); err != nil {
+		return nil, err
+	}
+
+	if table.Type == "VIEW" {
+		var definition string
+		query := `SELECT pg_get_viewdef(` + pgRelation + `, true)`
+		if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), name).Scan(&definition); err != nil {
+			return nil, err
+		}
+		table.CreateStatement = fmt.Sprintf("CREATE VIEW %s AS\n%s", table.Name, definition)
+		return &table, nil
+	}
+
+	lines, err := r.postgresColumnLines(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints, err := r.postgresConstraintLines(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, constraints...)
+
+	table.CreateStatement = fmt.Sprintf("CREATE TABLE %s (\n    %s\n)", table.Name, strings.Join(lines, ",\n    "))
+	return &table, nil
+}
+
+func (r *MetadataRepository) postgresColumnLines(ctx context.Context, name string) ([]string, error) {
+	query := `
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, a.attidentity::text,
+			COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = ` + pgRelation + ` AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]string, 0)
+	for rows.Next() {
+		var (
+			column, dataType, identity, defaultValue string
+			notNull                                  bool
+		)
+		if err := rows.Scan(&column, &dataType, &notNull, &identity, &defaultValue); err != nil {
+			return nil, err
+		}
+
+		line := column + " " + dataType
+		switch identity {
+		case "a":
+			line += " GENERATED ALWAYS AS IDENTITY"
+		case "d":
+			line += " GENERATED BY DEFAULT AS IDENTITY"
+		}
+		if notNull {
+			line += " NOT NULL"
+		}
+		if defaultValue != "" {
+			line += " DEFAULT " + defaultValue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func (r *MetadataRepository) postgresConstraintLines(ctx context.Context, name string) ([]string, error) {
+	query := `
+		SELECT conname, pg_get_constraintdef(oid, true)
+		FROM pg_constraint
+		WHERE conrelid = ` + pgRelation + `
+		ORDER BY CASE contype WHEN 'p' THEN 0 WHEN 'u' THEN 1 WHEN 'f' THEN 2 ELSE 3 END, conname
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]string, 0)
+	for rows.Next() {
+		var constraint, definition string
+		if err := rows.Scan(&constraint, &definition); err != nil {
+			return nil, err
+		}
+		lines = append(lines, "CONSTRAINT "+constraint+" "+definition)
+	}
+
+	return lines, rows.Err()
+}
+
+// postgresExecuteProcedure CALLs a real procedure and selects from a
+// set-returning function standing in for one.
+func (r *MetadataRepository) postgresExecuteProcedure(ctx context.Context, name string, args []any) ([]map[string]any, error) {
+	query := `
+		SELECT p.prokind = 'p'
+		FROM pg_proc p
+		WHERE p.pronamespace = current_schema()::regnamespace AND p.proname = ? AND ` + pgProcedureFilter + `
+		ORDER BY p.oid
+		LIMIT 1
+	`
+
+	var isProcedure bool
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), strings.ToLower(name)).Scan(&isProcedure); err != nil {
+		return nil, err
+	}
+
+	call := fmt.Sprintf("SELECT * FROM %s(%s)", name, buildPlaceholders(len(args)))
+	if isProcedure {
+		call = buildCallableQuery("CALL", name, len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(call), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSingleResultSet(rows)
+}
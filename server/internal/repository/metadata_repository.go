@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/karanm6505/dbms/server/internal/dialect"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
@@ -14,24 +17,111 @@ var identifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 
 var ErrInvalidIdentifier = errors.New("invalid identifier")
 
-type MetadataRepository struct {
-	db     *sql.DB
-	dbName string
-}
-
-func NewMetadataRepository(db *sql.DB, dbName string) *MetadataRepository {
-	return &MetadataRepository{db: db, dbName: dbName}
+// metadataQueries are the catalog queries whose results are scanned the same
+// way on every database. The MySQL ones take the schema name as their only
+// argument; the others take none. An empty query means the database has no
+// such objects.
+type metadataQueries struct {
+	listTables     string
+	listFunctions  string
+	listProcedures string
+	listTriggers   string
+	diagramColumns string
+	diagramKeys    string
 }
 
-func (r *MetadataRepository) ListTables(ctx context.Context) ([]models.SchemaTable, error) {
-	const query = `
+var mysqlMetadataQueries = metadataQueries{
+	listTables: `
 		SELECT TABLE_NAME
 		FROM information_schema.tables
 		WHERE TABLE_SCHEMA = ?
 		ORDER BY TABLE_NAME
-	`
+	`,
+	listFunctions: `
+		SELECT ROUTINE_NAME
+		FROM information_schema.routines
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'FUNCTION'
+		ORDER BY ROUTINE_NAME
+	`,
+	listProcedures: `
+		SELECT ROUTINE_NAME
+		FROM information_schema.routines
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'PROCEDURE'
+		ORDER BY ROUTINE_NAME
+	`,
+	listTriggers: `
+		SELECT TRIGGER_NAME, EVENT_MANIPULATION, EVENT_OBJECT_TABLE, ACTION_TIMING
+		FROM information_schema.triggers
+		WHERE TRIGGER_SCHEMA = ?
+		ORDER BY TRIGGER_NAME
+	`,
+	diagramColumns: `
+		SELECT c.TABLE_NAME, c.COLUMN_NAME, c.DATA_TYPE, c.COLUMN_TYPE, c.IS_NULLABLE, c.COLUMN_KEY
+		FROM information_schema.columns c
+		JOIN information_schema.tables t
+			ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+		WHERE c.TABLE_SCHEMA = ? AND t.TABLE_TYPE = 'BASE TABLE'
+		ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION
+	`,
+	diagramKeys: `
+		SELECT CONSTRAINT_NAME, TABLE_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION
+	`,
+}
+
+type MetadataRepository struct {
+	db      *sql.DB
+	dbName  string
+	dialect dialect.Dialect
+	queries metadataQueries
+}
+
+func NewMetadataRepository(db *sql.DB, dbName string, opts ...Option) *MetadataRepository {
+	r := &MetadataRepository{db: db, dbName: dbName, dialect: resolveOptions(db, opts).dialect, queries: mysqlMetadataQueries}
+	switch r.dialect {
+	case dialect.Postgres:
+		r.queries = postgresMetadataQueries
+	case dialect.SQLite:
+		r.queries = sqliteMetadataQueries
+	}
+	return r
+}
+
+// Capabilities reports what the repository can do on its database. SQLite
+// has no stored routines, and only MySQL is compared against for drift.
+func (r *MetadataRepository) Capabilities() models.SchemaCapabilities {
+	capabilities := models.SchemaCapabilities{
+		Driver:            string(r.dialect),
+		Routines:          true,
+		ExecuteProcedures: true,
+		ExecuteFunctions:  true,
+		Triggers:          true,
+		TableDefinitions:  true,
+		SchemaDiagram:     true,
+		SchemaSnapshot:    r.dialect == dialect.MySQL,
+	}
+	if r.dialect == dialect.SQLite {
+		capabilities.Routines = false
+		capabilities.ExecuteProcedures = false
+		capabilities.ExecuteFunctions = false
+	}
+	return capabilities
+}
+
+func (r *MetadataRepository) schemaArgs() []any {
+	if r.dialect != dialect.MySQL {
+		return nil
+	}
+	return []any{r.dbName}
+}
 
-	rows, err := r.db.QueryContext(ctx, query, r.dbName)
+func (r *MetadataRepository) ListTables(ctx context.Context) (_ []models.SchemaTable, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.list_tables")
+	defer op.end(&err)
+
+	rows, err := r.db.QueryContext(ctx, r.queries.listTables, r.schemaArgs()...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,18 +141,19 @@ func (r *MetadataRepository) ListTables(ctx context.Context) ([]models.SchemaTab
 		return nil, err
 	}
 
+	op.setRows(len(tables))
 	return tables, nil
 }
 
-func (r *MetadataRepository) ListFunctions(ctx context.Context) ([]models.DbRoutine, error) {
-	const query = `
-		SELECT ROUTINE_NAME
-		FROM information_schema.routines
-		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'FUNCTION'
-		ORDER BY ROUTINE_NAME
-	`
+func (r *MetadataRepository) ListFunctions(ctx context.Context) (_ []models.DbRoutine, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.list_functions")
+	defer op.end(&err)
+
+	if r.queries.listFunctions == "" {
+		return make([]models.DbRoutine, 0), nil
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, r.dbName)
+	rows, err := r.db.QueryContext(ctx, r.queries.listFunctions, r.schemaArgs()...)
 	if err != nil {
 		return nil, err
 	}
@@ -71,15 +162,15 @@ func (r *MetadataRepository) ListFunctions(ctx context.Context) ([]models.DbRout
 	return scanRoutines(rows)
 }
 
-func (r *MetadataRepository) ListProcedures(ctx context.Context) ([]models.DbRoutine, error) {
-	const query = `
-		SELECT ROUTINE_NAME
-		FROM information_schema.routines
-		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'PROCEDURE'
-		ORDER BY ROUTINE_NAME
-	`
+func (r *MetadataRepository) ListProcedures(ctx context.Context) (_ []models.DbRoutine, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.list_procedures")
+	defer op.end(&err)
+
+	if r.queries.listProcedures == "" {
+		return make([]models.DbRoutine, 0), nil
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, r.dbName)
+	rows, err := r.db.QueryContext(ctx, r.queries.listProcedures, r.schemaArgs()...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,15 +179,11 @@ func (r *MetadataRepository) ListProcedures(ctx context.Context) ([]models.DbRou
 	return scanRoutines(rows)
 }
 
-func (r *MetadataRepository) ListTriggers(ctx context.Context) ([]models.DbTrigger, error) {
-	const query = `
-		SELECT TRIGGER_NAME, EVENT_MANIPULATION, EVENT_OBJECT_TABLE, ACTION_TIMING
-		FROM information_schema.triggers
-		WHERE TRIGGER_SCHEMA = ?
-		ORDER BY TRIGGER_NAME
-	`
+func (r *MetadataRepository) ListTriggers(ctx context.Context) (_ []models.DbTrigger, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.list_triggers")
+	defer op.end(&err)
 
-	rows, err := r.db.QueryContext(ctx, query, r.dbName)
+	rows, err := r.db.QueryContext(ctx, r.queries.listTriggers, r.schemaArgs()...)
 	if err != nil {
 		return nil, err
 	}
@@ -116,13 +203,419 @@ func (r *MetadataRepository) ListTriggers(ctx context.Context) ([]models.DbTrigg
 		return nil, err
 	}
 
+	op.setRows(len(triggers))
 	return triggers, nil
 }
 
-func (r *MetadataRepository) ExecuteProcedure(ctx context.Context, name string, args []any) ([]map[string]any, error) {
+func (r *MetadataRepository) GetFunctionDefinition(ctx context.Context, name string) (_ *models.RoutineDefinition, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.get_function_definition")
+	defer op.end(&err)
+
+	return r.getRoutineDefinition(ctx, "FUNCTION", name)
+}
+
+func (r *MetadataRepository) GetProcedureDefinition(ctx context.Context, name string) (_ *models.RoutineDefinition, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.get_procedure_definition")
+	defer op.end(&err)
+
+	return r.getRoutineDefinition(ctx, "PROCEDURE", name)
+}
+
+func (r *MetadataRepository) getRoutineDefinition(ctx context.Context, routineType, name string) (*models.RoutineDefinition, error) {
+	if !isValidIdentifier(name) {
+		return nil, ErrInvalidIdentifier
+	}
+	if !r.Capabilities().Routines {
+		return nil, sql.ErrNoRows
+	}
+	if r.dialect == dialect.Postgres {
+		return r.postgresRoutineDefinition(ctx, routineType, name)
+	}
+
+	const query = `
+		SELECT ROUTINE_NAME, ROUTINE_TYPE, DEFINER, SECURITY_TYPE, IS_DETERMINISTIC, SQL_DATA_ACCESS,
+			SQL_MODE, DTD_IDENTIFIER, ROUTINE_DEFINITION, ROUTINE_COMMENT, CREATED, LAST_ALTERED
+		FROM information_schema.routines
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = ? AND ROUTINE_NAME = ?
+	`
+
+	var (
+		routine       models.RoutineDefinition
+		deterministic string
+		returns       sql.NullString
+		body          sql.NullString
+		created       sql.NullTime
+		lastAltered   sql.NullTime
+	)
+
+	if err := r.db.QueryRowContext(ctx, query, r.dbName, routineType, name).Scan(
+		&routine.Name,
+		&routine.Type,
+		&routine.Definer,
+		&routine.SecurityType,
+		&deterministic,
+		&routine.SQLDataAccess,
+		&routine.SQLMode,
+		&returns,
+		&body,
+		&routine.Comment,
+		&created,
+		&lastAltered,
+	); err != nil {
+		return nil, err
+	}
+
+	routine.IsDeterministic = strings.EqualFold(deterministic, "YES")
+	routine.Returns = returns.String
+	routine.Body = body.String
+	routine.Created = formatTimestamp(created)
+	routine.LastAltered = formatTimestamp(lastAltered)
+
+	createStatement, err := r.showCreate(ctx, routineType, routine.Name, "Create "+titleCase(routineType))
+	if err != nil {
+		return nil, err
+	}
+	routine.CreateStatement = createStatement
+
+	return &routine, nil
+}
+
+func (r *MetadataRepository) GetTriggerDefinition(ctx context.Context, name string) (_ *models.TriggerDefinition, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.get_trigger_definition")
+	defer op.end(&err)
+
+	if !isValidIdentifier(name) {
+		return nil, ErrInvalidIdentifier
+	}
+	switch r.dialect {
+	case dialect.Postgres:
+		return r.postgresTriggerDefinition(ctx, name)
+	case dialect.SQLite:
+		return r.sqliteTriggerDefinition(ctx, name)
+	}
+
+	const query = `
+		SELECT TRIGGER_NAME, EVENT_MANIPULATION, EVENT_OBJECT_TABLE, ACTION_TIMING, ACTION_ORDER,
+			DEFINER, SQL_MODE, ACTION_STATEMENT, CREATED
+		FROM information_schema.triggers
+		WHERE TRIGGER_SCHEMA = ? AND TRIGGER_NAME = ?
+	`
+
+	var (
+		trigger models.TriggerDefinition
+		created sql.NullTime
+	)
+
+	if err := r.db.QueryRowContext(ctx, query, r.dbName, name).Scan(
+		&trigger.Name,
+		&trigger.Event,
+		&trigger.TableName,
+		&trigger.Timing,
+		&trigger.ActionOrder,
+		&trigger.Definer,
+		&trigger.SQLMode,
+		&trigger.Body,
+		&created,
+	); err != nil {
+		return nil, err
+	}
+
+	trigger.Created = formatTimestamp(created)
+
+	createStatement, err := r.showCreate(ctx, "TRIGGER", trigger.Name, "SQL Original Statement")
+	if err != nil {
+		return nil, err
+	}
+	trigger.CreateStatement = createStatement
+
+	return &trigger, nil
+}
+
+func (r *MetadataRepository) GetTableDefinition(ctx context.Context, name string) (_ *models.TableDefinition, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.get_table_definition")
+	defer op.end(&err)
+
+	if !isValidIdentifier(name) {
+		return nil, ErrInvalidIdentifier
+	}
+	switch r.dialect {
+	case dialect.Postgres:
+		return r.postgresTableDefinition(ctx, name)
+	case dialect.SQLite:
+		return r.sqliteTableDefinition(ctx, name)
+	}
+
+	const query = `
+		SELECT TABLE_NAME, TABLE_TYPE
+		FROM information_schema.tables
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+	`
+
+	var table models.TableDefinition
+	if err := r.db.QueryRowContext(ctx, query, r.dbName, name).Scan(&table.Name, &table.Type); err != nil {
+		return nil, err
+	}
+
+	column := "Create Table"
+	if table.Type == "VIEW" {
+		column = "Create View"
+	}
+
+	createStatement, err := r.showCreate(ctx, "TABLE", table.Name, column)
+	if err != nil {
+		return nil, err
+	}
+	table.CreateStatement = createStatement
+
+	return &table, nil
+}
+
+func (r *MetadataRepository) showCreate(ctx context.Context, objectType, name, column string) (string, error) {
+	query := fmt.Sprintf("SHOW CREATE %s %s.%s", objectType, quoteIdentifier(r.dbName), quoteIdentifier(name))
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	result, err := scanSingleResultSet(rows)
+	if err != nil {
+		return "", err
+	}
+
+	if len(result) == 0 {
+		return "", sql.ErrNoRows
+	}
+
+	statement, _ := result[0][column].(string)
+	return statement, nil
+}
+
+func (r *MetadataRepository) GetSchemaDiagram(ctx context.Context) (_ *models.SchemaDiagram, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.get_schema_diagram")
+	defer op.end(&err)
+
+	rows, err := r.db.QueryContext(ctx, r.queries.diagramColumns, r.schemaArgs()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	diagram := &models.SchemaDiagram{
+		Database:      r.dbName,
+		Tables:        make([]models.DiagramTable, 0),
+		Relationships: make([]models.DiagramRelationship, 0),
+	}
+	tableIndex := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			tableName string
+			column    models.DiagramColumn
+			nullable  string
+			key       string
+		)
+		if err := rows.Scan(&tableName, &column.Name, &column.DataType, &column.ColumnType, &nullable, &key); err != nil {
+			return nil, err
+		}
+		column.Nullable = strings.EqualFold(nullable, "YES")
+		column.PrimaryKey = key == "PRI"
+
+		idx, ok := tableIndex[tableName]
+		if !ok {
+			idx = len(diagram.Tables)
+			tableIndex[tableName] = idx
+			diagram.Tables = append(diagram.Tables, models.DiagramTable{Name: tableName})
+		}
+		diagram.Tables[idx].Columns = append(diagram.Tables[idx].Columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	keyRows, err := r.db.QueryContext(ctx, r.queries.diagramKeys, r.schemaArgs()...)
+	if err != nil {
+		return nil, err
+	}
+	defer keyRows.Close()
+
+	relationshipIndex := make(map[string]int)
+
+	for keyRows.Next() {
+		var constraint, tableName, columnName, referencedTable, referencedColumn string
+		if err := keyRows.Scan(&constraint, &tableName, &columnName, &referencedTable, &referencedColumn); err != nil {
+			return nil, err
+		}
+
+		key := tableName + "." + constraint
+		idx, ok := relationshipIndex[key]
+		if !ok {
+			idx = len(diagram.Relationships)
+			relationshipIndex[key] = idx
+			diagram.Relationships = append(diagram.Relationships, models.DiagramRelationship{
+				Name:            constraint,
+				Table:           tableName,
+				ReferencedTable: referencedTable,
+			})
+		}
+
+		relationship := &diagram.Relationships[idx]
+		relationship.Columns = append(relationship.Columns, columnName)
+		relationship.ReferencedColumns = append(relationship.ReferencedColumns, referencedColumn)
+
+		if tIdx, ok := tableIndex[tableName]; ok {
+			for i := range diagram.Tables[tIdx].Columns {
+				column := &diagram.Tables[tIdx].Columns[i]
+				if column.Name == columnName {
+					column.ForeignKey = true
+					if column.Nullable {
+						relationship.Nullable = true
+					}
+				}
+			}
+		}
+	}
+
+	if err := keyRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return diagram, nil
+}
+
+func (r *MetadataRepository) GetSchemaSnapshot(ctx context.Context) (_ *models.SchemaSnapshot, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.get_schema_snapshot")
+	defer op.end(&err)
+
+	// Snapshots feed the drift check, which compares against the MySQL
+	// migrations.
+	if !r.Capabilities().SchemaSnapshot {
+		return nil, fmt.Errorf("schema snapshot on %s: %w", r.dialect, errors.ErrUnsupported)
+	}
+
+	snapshot := &models.SchemaSnapshot{
+		Tables:   make([]models.SnapshotTable, 0),
+		Routines: make([]models.SnapshotRoutine, 0),
+		Triggers: make([]models.SnapshotTrigger, 0),
+	}
+
+	const columnsQuery = `
+		SELECT c.TABLE_NAME, c.COLUMN_NAME, c.COLUMN_TYPE, c.IS_NULLABLE
+		FROM information_schema.columns c
+		JOIN information_schema.tables t
+			ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+		WHERE c.TABLE_SCHEMA = ? AND t.TABLE_TYPE = 'BASE TABLE'
+		ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION
+	`
+
+	columnRows, err := r.db.QueryContext(ctx, columnsQuery, r.dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer columnRows.Close()
+
+	tableIndex := make(map[string]int)
+
+	for columnRows.Next() {
+		var (
+			tableName string
+			column    models.SnapshotColumn
+			nullable  string
+		)
+		if err := columnRows.Scan(&tableName, &column.Name, &column.Type, &nullable); err != nil {
+			return nil, err
+		}
+		column.Nullable = strings.EqualFold(nullable, "YES")
+
+		idx, ok := tableIndex[tableName]
+		if !ok {
+			idx = len(snapshot.Tables)
+			tableIndex[tableName] = idx
+			snapshot.Tables = append(snapshot.Tables, models.SnapshotTable{Name: tableName})
+		}
+		snapshot.Tables[idx].Columns = append(snapshot.Tables[idx].Columns, column)
+	}
+
+	if err := columnRows.Err(); err != nil {
+		return nil, err
+	}
+
+	const routinesQuery = `
+		SELECT ROUTINE_NAME, ROUTINE_TYPE, ROUTINE_DEFINITION
+		FROM information_schema.routines
+		WHERE ROUTINE_SCHEMA = ?
+		ORDER BY ROUTINE_TYPE, ROUTINE_NAME
+	`
+
+	routineRows, err := r.db.QueryContext(ctx, routinesQuery, r.dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer routineRows.Close()
+
+	for routineRows.Next() {
+		var (
+			routine models.SnapshotRoutine
+			body    sql.NullString
+		)
+		if err := routineRows.Scan(&routine.Name, &routine.Type, &body); err != nil {
+			return nil, err
+		}
+		routine.Body = body.String
+		snapshot.Routines = append(snapshot.Routines, routine)
+	}
+
+	if err := routineRows.Err(); err != nil {
+		return nil, err
+	}
+
+	const triggersQuery = `
+		SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE, EVENT_MANIPULATION, ACTION_TIMING, ACTION_STATEMENT
+		FROM information_schema.triggers
+		WHERE TRIGGER_SCHEMA = ?
+		ORDER BY TRIGGER_NAME
+	`
+
+	triggerRows, err := r.db.QueryContext(ctx, triggersQuery, r.dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer triggerRows.Close()
+
+	for triggerRows.Next() {
+		var trigger models.SnapshotTrigger
+		if err := triggerRows.Scan(&trigger.Name, &trigger.Table, &trigger.Event, &trigger.Timing, &trigger.Body); err != nil {
+			return nil, err
+		}
+		snapshot.Triggers = append(snapshot.Triggers, trigger)
+	}
+
+	if err := triggerRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (r *MetadataRepository) DatabaseName() string {
+	return r.dbName
+}
+
+func (r *MetadataRepository) ExecuteProcedure(ctx context.Context, name string, args []any) (_ []map[string]any, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.execute_procedure")
+	defer op.end(&err)
+
+	if !r.Capabilities().ExecuteProcedures {
+		return nil, fmt.Errorf("stored procedures on %s: %w", r.dialect, errors.ErrUnsupported)
+	}
 	if !isValidIdentifier(name) {
 		return nil, ErrInvalidIdentifier
 	}
+	if r.dialect == dialect.Postgres {
+		return r.postgresExecuteProcedure(ctx, name, args)
+	}
 
 	query := buildCallableQuery("CALL", name, len(args))
 
@@ -135,7 +628,13 @@ func (r *MetadataRepository) ExecuteProcedure(ctx context.Context, name string,
 	return scanAllResultSets(rows)
 }
 
-func (r *MetadataRepository) ExecuteFunction(ctx context.Context, name string, args []any) (any, error) {
+func (r *MetadataRepository) ExecuteFunction(ctx context.Context, name string, args []any) (_ any, err error) {
+	ctx, op := track(ctx, r.dialect, "metadata.execute_function")
+	defer op.end(&err)
+
+	if !r.Capabilities().ExecuteFunctions {
+		return nil, fmt.Errorf("stored functions on %s: %w", r.dialect, errors.ErrUnsupported)
+	}
 	if !isValidIdentifier(name) {
 		return nil, ErrInvalidIdentifier
 	}
@@ -148,7 +647,7 @@ func (r *MetadataRepository) ExecuteFunction(ctx context.Context, name string, a
 		query = fmt.Sprintf("SELECT %s(%s) AS result", name, placeholders)
 	}
 
-	row := r.db.QueryRowContext(ctx, query, args...)
+	row := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), args...)
 
 	var result any
 	if err := row.Scan(&result); err != nil {
@@ -232,6 +731,25 @@ func isValidIdentifier(s string) bool {
 	return identifierRegex.MatchString(s)
 }
 
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func titleCase(value string) string {
+	if value == "" {
+		return value
+	}
+	lower := strings.ToLower(value)
+	return strings.ToUpper(lower[:1]) + lower[1:]
+}
+
+func formatTimestamp(value sql.NullTime) string {
+	if !value.Valid {
+		return ""
+	}
+	return value.Time.UTC().Format(time.RFC3339)
+}
+
 func buildCallableQuery(prefix, name string, argCount int) string {
 	if prefix != "CALL" {
 		return ""
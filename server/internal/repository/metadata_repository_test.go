@@ -1,6 +1,20 @@
 package repository
 
-import "testing"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "modernc.org/sqlite"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/migrate"
+)
 
 func TestBuildPlaceholders(t *testing.T) {
 	tests := []struct {
@@ -84,3 +98,166 @@ func TestNormalizeDBValue(t *testing.T) {
 		}
 	})
 }
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"borrow", "`borrow`"},
+		{"odd`name", "`odd``name`"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			if got := quoteIdentifier(tc.input); got != tc.expect {
+				t.Fatalf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestMetadataRepository_GetTriggerDefinition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo := NewMetadataRepository(db, "Library_Management_System")
+	created := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	body := "BEGIN\n    UPDATE book SET Status = 'Issued' WHERE Book_ID = NEW.Book_ID;\nEND"
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM information_schema.triggers")).
+		WithArgs("Library_Management_System", "after_borrow_insert").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"TRIGGER_NAME", "EVENT_MANIPULATION", "EVENT_OBJECT_TABLE", "ACTION_TIMING", "ACTION_ORDER",
+			"DEFINER", "SQL_MODE", "ACTION_STATEMENT", "CREATED",
+		}).AddRow("after_borrow_insert", "INSERT", "borrow", "AFTER", 1, "root@%", "STRICT_TRANS_TABLES", body, created))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SHOW CREATE TRIGGER `Library_Management_System`.`after_borrow_insert`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Trigger", "sql_mode", "SQL Original Statement"}).
+			AddRow("after_borrow_insert", "STRICT_TRANS_TABLES", []byte("CREATE TRIGGER after_borrow_insert ...")))
+
+	trigger, err := repo.GetTriggerDefinition(context.Background(), "after_borrow_insert")
+	if err != nil {
+		t.Fatalf("GetTriggerDefinition returned error: %v", err)
+	}
+
+	if trigger.Body != body {
+		t.Fatalf("expected body %q, got %q", body, trigger.Body)
+	}
+
+	if trigger.CreateStatement != "CREATE TRIGGER after_borrow_insert ..." {
+		t.Fatalf("unexpected create statement %q", trigger.CreateStatement)
+	}
+
+	if trigger.Created != "2025-10-01T12:00:00Z" {
+		t.Fatalf("unexpected created timestamp %q", trigger.Created)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestMetadataRepository_GetProcedureDefinitionInvalidName(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo := NewMetadataRepository(db, "Library_Management_System")
+
+	if _, err := repo.GetProcedureDefinition(context.Background(), "drop table;"); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Fatalf("expected ErrInvalidIdentifier, got %v", err)
+	}
+}
+
+func TestMetadataRepository_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	migrator, err := migrate.NewForDialect(db, dialect.SQLite)
+	if err != nil {
+		t.Fatalf("NewForDialect returned error: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+
+	repo := NewMetadataRepository(db, "library", WithDialect(dialect.SQLite))
+
+	capabilities := repo.Capabilities()
+	if capabilities.Routines || capabilities.ExecuteProcedures || capabilities.ExecuteFunctions {
+		t.Fatalf("expected routines to be unsupported, got %+v", capabilities)
+	}
+	if !capabilities.Triggers || !capabilities.SchemaDiagram {
+		t.Fatalf("expected triggers and the diagram to be supported, got %+v", capabilities)
+	}
+
+	if _, err := repo.ExecuteProcedure(ctx, "GetBorrowedBooks", nil); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported from ExecuteProcedure, got %v", err)
+	}
+	if _, err := repo.ExecuteFunction(ctx, "BookAvailability", []any{1}); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported from ExecuteFunction, got %v", err)
+	}
+
+	procedures, err := repo.ListProcedures(ctx)
+	if err != nil || len(procedures) != 0 {
+		t.Fatalf("expected no procedures, got %v (err %v)", procedures, err)
+	}
+
+	triggers, err := repo.ListTriggers(ctx)
+	if err != nil {
+		t.Fatalf("ListTriggers returned error: %v", err)
+	}
+	found := false
+	for _, trigger := range triggers {
+		if trigger.Name == "before_borrow_limit" {
+			found = true
+			if trigger.Event != "INSERT" || trigger.Timing != "BEFORE" || trigger.TableName != "borrow" {
+				t.Fatalf("unexpected trigger %+v", trigger)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected before_borrow_limit among %+v", triggers)
+	}
+
+	trigger, err := repo.GetTriggerDefinition(ctx, "after_borrow_return")
+	if err != nil {
+		t.Fatalf("GetTriggerDefinition returned error: %v", err)
+	}
+	if trigger.Event != "UPDATE" || trigger.Timing != "AFTER" || !strings.HasPrefix(trigger.Body, "BEGIN") {
+		t.Fatalf("unexpected trigger definition %+v", trigger)
+	}
+
+	table, err := repo.GetTableDefinition(ctx, "book")
+	if err != nil {
+		t.Fatalf("GetTableDefinition returned error: %v", err)
+	}
+	if table.Type != "BASE TABLE" || !strings.Contains(table.CreateStatement, "CREATE TABLE") {
+		t.Fatalf("unexpected table definition %+v", table)
+	}
+
+	diagram, err := repo.GetSchemaDiagram(ctx)
+	if err != nil {
+		t.Fatalf("GetSchemaDiagram returned error: %v", err)
+	}
+	related := false
+	for _, relationship := range diagram.Relationships {
+		if relationship.Table == "borrow" && relationship.ReferencedTable == "book" {
+			related = true
+		}
+	}
+	if !related {
+		t.Fatalf("expected a borrow -> book relationship, got %+v", diagram.Relationships)
+	}
+}
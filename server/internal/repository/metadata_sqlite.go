@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// sqliteTriggers reads triggers from sqlite_master. SQLite keeps only the
+// CREATE TRIGGER text, so the event and timing are picked out of the part
+// before " ON ", with line breaks flattened and spaces around every word.
+const sqliteTriggers = `
+	WITH t AS (
+		SELECT name, tbl_name, sql,
+			' ' || upper(replace(replace(replace(sql, char(13), ' '), char(10), ' '), char(9), ' ')) || ' ' AS ddl
+		FROM sqlite_master
+		WHERE type = 'trigger'
+	), h AS (
+		SELECT name, tbl_name, sql, substr(ddl, 1, instr(ddl, ' ON ')) AS head
+		FROM t
+	)
+	SELECT name,
+		CASE WHEN instr(head, ' INSERT ') > 0 THEN 'INSERT' WHEN instr(head, ' DELETE ') > 0 THEN 'DELETE' ELSE 'UPDATE' END AS event,
+		tbl_name AS table_name,
+		CASE WHEN instr(head, ' BEFORE ') > 0 THEN 'BEFORE' WHEN instr(head, ' INSTEAD OF ') > 0 THEN 'INSTEAD OF' ELSE 'AFTER' END AS timing,
+		sql
+	FROM h
+`
+
+// sqliteMetadataQueries leave out the routine lists: SQLite has no stored
+// procedures or functions. Foreign keys are unnamed, so relationships are
+// named after their table and position.
+var sqliteMetadataQueries = metadataQueries{
+	listTables: `
+		SELECT name
+		FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
+		ORDER BY name
+	`,
+	listTriggers: `
+		SELECT name, event, table_name, timing
+		FROM (` + sqliteTriggers + `)
+		ORDER BY name
+	`,
+	diagramColumns: `
+		SELECT m.name, p.name,
+			lower(CASE WHEN instr(p.type, '(') > 0 THEN substr(p.type, 1, instr(p.type, '(') - 1) ELSE p.type END),
+			lower(p.type),
+			CASE WHEN p."notnull" = 0 AND p.pk = 0 THEN 'YES' ELSE 'NO' END,
+			CASE WHEN p.pk > 0 THEN 'PRI' ELSE '' END
+		FROM sqlite_master m
+		JOIN pragma_table_info(m.name) p
+		WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'
+		ORDER BY m.name, p.cid
+	`,
+	diagramKeys: `
+		SELECT 'fk_' || m.name || '_' || f.id, m.name, f."from", f."table", f."to"
+		FROM sqlite_master m
+		JOIN pragma_foreign_key_list(m.name) f
+		WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'
+		ORDER BY m.name, f.id, f.seq
+	`,
+}
+
+func (r *MetadataRepository) sqliteTriggerDefinition(ctx context.Context, name string) (*models.TriggerDefinition, error) {
+	query := `
+		SELECT name, event, table_name, timing, sql
+		FROM (` + sqliteTriggers + `)
+		WHERE name = ? COLLATE NOCASE
+	`
+
+	var trigger models.TriggerDefinition
+	if err := r.db.QueryRowContext(ctx, query, name).Scan(
+		&trigger.Name,
+		&trigger.Event,
+		&trigger.TableName,
+		&trigger.Timing,
+		&trigger.CreateStatement,
+	); err != nil {
+		return nil, err
+	}
+
+	trigger.Body = sqliteTriggerBody(trigger.CreateStatement)
+	return &trigger, nil
+}
+
+// sqliteTriggerBody returns the BEGIN ... END block of a CREATE TRIGGER
+// statement, matching what MySQL reports as the action statement.
+func sqliteTriggerBody(statement string) string {
+	upper := strings.ToUpper(statement)
+	begin := strings.Index(upper, "BEGIN")
+	if begin < 0 {
+		return statement
+	}
+	return strings.TrimSpace(statement[begin:])
+}
+
+func (r *MetadataRepository) sqliteTableDefinition(ctx context.Context, name string) (*models.TableDefinition, error) {
+	const query = `
+		SELECT name, CASE type WHEN 'view' THEN 'VIEW' ELSE 'BASE TABLE' END, sql
+		FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name = ? COLLATE NOCASE
+	`
+
+	var table models.TableDefinition
+	if err := r.db.QueryRowContext(ctx, query, name).Scan(&table.Name, &table.Type, &table.CreateStatement); err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+var registeredQueries = map[string]string{
+	"books.get_all":       getAllBooksQuery,
+	"books.get_available": getAvailableBooksQuery,
+	"borrows.get_all":     getAllBorrowsQuery,
+	"staff.get_all":       getAllStaffQuery,
+	"students.get_all":    getAllStudentsQuery,
+	"students.get_by_id":  getStudentByIDQuery,
+	"users.get_by_email":  getUserByEmailQuery,
+	"users.get_by_id":     getUserByIDQuery,
+}
+
+func RegisteredQueries() []models.NamedQuery {
+	queries := make([]models.NamedQuery, 0, len(registeredQueries))
+	for name := range registeredQueries {
+		query, _ := LookupQuery(name)
+		queries = append(queries, query)
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+
+	return queries
+}
+
+func LookupQuery(name string) (models.NamedQuery, bool) {
+	query, ok := registeredQueries[name]
+	if !ok {
+		return models.NamedQuery{}, false
+	}
+
+	return models.NamedQuery{
+		Name:       name,
+		SQL:        strings.TrimSpace(query),
+		Parameters: strings.Count(query, "?"),
+	}, true
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+)
+
+// ReadRouter runs read-only queries that tolerate replication lag, such as
+// list endpoints and dashboard statistics. db.Cluster routes them to a
+// healthy replica and falls back to the primary. Read may call fn more than
+// once, so fn must reset anything it fills in.
+type ReadRouter interface {
+	Read(ctx context.Context, fn func(db *sql.DB) error) error
+}
+
+type Option func(*options)
+
+type options struct {
+	reads   ReadRouter
+	dialect dialect.Dialect
+}
+
+// WithReadRouter sends a repository's read-only queries through router
+// instead of the primary pool.
+func WithReadRouter(router ReadRouter) Option {
+	return func(o *options) {
+		o.reads = router
+	}
+}
+
+// WithDialect makes a repository speak d instead of MySQL.
+func WithDialect(d dialect.Dialect) Option {
+	return func(o *options) {
+		o.dialect = d
+	}
+}
+
+type primaryOnly struct {
+	db *sql.DB
+}
+
+func (p primaryOnly) Read(_ context.Context, fn func(db *sql.DB) error) error {
+	return fn(p.db)
+}
+
+func resolveOptions(db *sql.DB, opts []Option) options {
+	o := options{reads: primaryOnly{db: db}, dialect: dialect.MySQL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+var ErrSavedQueryExists = errors.New("a saved query with this slug already exists")
+
+const savedQueryColumns = `
+	saved_query_id, slug, title, COALESCE(description, ''), query_text, parameters,
+	required_role, COALESCE(created_by, 0), created_at, updated_at
+`
+
+type SavedQueryRepository struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+func NewSavedQueryRepository(db *sql.DB, opts ...Option) *SavedQueryRepository {
+	return &SavedQueryRepository{db: db, dialect: resolveOptions(db, opts).dialect}
+}
+
+func (r *SavedQueryRepository) List(ctx context.Context) (_ []models.SavedQuery, err error) {
+	ctx, op := track(ctx, r.dialect, "saved_queries.list")
+	defer op.end(&err)
+
+	query := `SELECT ` + savedQueryColumns + ` FROM saved_queries ORDER BY title`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	queries := make([]models.SavedQuery, 0)
+	for rows.Next() {
+		saved, err := scanSavedQuery(rows)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, *saved)
+	}
+
+	op.setRows(len(queries))
+	return queries, rows.Err()
+}
+
+func (r *SavedQueryRepository) GetBySlug(ctx context.Context, slug string) (_ *models.SavedQuery, err error) {
+	ctx, op := track(ctx, r.dialect, "saved_queries.get_by_slug")
+	defer op.end(&err)
+
+	query := `SELECT ` + savedQueryColumns + ` FROM saved_queries WHERE slug = ?`
+
+	return scanSavedQuery(r.db.QueryRowContext(ctx, r.dialect.Rebind(query), slug))
+}
+
+func (r *SavedQueryRepository) Create(ctx context.Context, saved *models.SavedQuery) (err error) {
+	ctx, op := track(ctx, r.dialect, "saved_queries.create")
+	defer op.end(&err)
+
+	const query = `
+		INSERT INTO saved_queries (slug, title, description, query_text, parameters, required_role, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	parameters, err := json.Marshal(saved.Parameters)
+	if err != nil {
+		return err
+	}
+
+	id, err := insertID(ctx, r.db, r.dialect, query, "saved_query_id",
+		saved.Slug, saved.Title, saved.Description, saved.SQL, parameters, saved.RequiredRole, saved.CreatedBy,
+	)
+	if err != nil {
+		return mapSavedQueryError(err)
+	}
+	saved.ID = id
+
+	return nil
+}
+
+func (r *SavedQueryRepository) Update(ctx context.Context, slug string, saved *models.SavedQuery) (err error) {
+	ctx, op := track(ctx, r.dialect, "saved_queries.update")
+	defer op.end(&err)
+
+	const query = `
+		UPDATE saved_queries
+		SET slug = ?, title = ?, description = ?, query_text = ?, parameters = ?, required_role = ?
+		WHERE slug = ?
+	`
+
+	parameters, err := json.Marshal(saved.Parameters)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query),
+		saved.Slug, saved.Title, saved.Description, saved.SQL, parameters, saved.RequiredRole, slug,
+	)
+	if err != nil {
+		return mapSavedQueryError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	// MySQL reports zero affected rows when nothing changed, so tell an
+	// unchanged report apart from a missing one.
+	var exists int
+	return r.db.QueryRowContext(ctx, r.dialect.Rebind(`SELECT 1 FROM saved_queries WHERE slug = ?`), saved.Slug).Scan(&exists)
+}
+
+func (r *SavedQueryRepository) Delete(ctx context.Context, slug string) (err error) {
+	ctx, op := track(ctx, r.dialect, "saved_queries.delete")
+	defer op.end(&err)
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(`DELETE FROM saved_queries WHERE slug = ?`), slug)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSavedQuery(row rowScanner) (*models.SavedQuery, error) {
+	saved := &models.SavedQuery{}
+	var parameters []byte
+
+	if err := row.Scan(
+		&saved.ID,
+		&saved.Slug,
+		&saved.Title,
+		&saved.Description,
+		&saved.SQL,
+		&parameters,
+		&saved.RequiredRole,
+		&saved.CreatedBy,
+		&saved.CreatedAt,
+		&saved.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	saved.Parameters = make([]models.ReportParameter, 0)
+	if len(parameters) > 0 {
+		if err := json.Unmarshal(parameters, &saved.Parameters); err != nil {
+			return nil, err
+		}
+	}
+
+	return saved, nil
+}
+
+func mapSavedQueryError(err error) error {
+	if isUniqueViolation(err) {
+		return ErrSavedQueryExists
+	}
+	return err
+}
@@ -4,43 +4,55 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/karanm6505/dbms/server/internal/dialect"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
+const getAllStaffQuery = `
+	SELECT Staff_ID, First_Name, Last_Name, Position, Status
+	FROM staff
+	ORDER BY Staff_ID
+`
+
 type StaffRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	reads   ReadRouter
+	dialect dialect.Dialect
 }
 
-func NewStaffRepository(db *sql.DB) *StaffRepository {
-	return &StaffRepository{db: db}
+func NewStaffRepository(db *sql.DB, opts ...Option) *StaffRepository {
+	o := resolveOptions(db, opts)
+	return &StaffRepository{db: db, reads: o.reads, dialect: o.dialect}
 }
 
-func (r *StaffRepository) GetAll(ctx context.Context) ([]models.Staff, error) {
-	const query = `
-		SELECT Staff_ID, First_Name, Last_Name, Position, Status
-		FROM staff
-		ORDER BY Staff_ID
-	`
+func (r *StaffRepository) GetAll(ctx context.Context) (_ []models.Staff, err error) {
+	ctx, op := track(ctx, r.dialect, "staff.get_all")
+	defer op.end(&err)
 
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	var staffMembers []models.Staff
+	err = r.reads.Read(ctx, func(db *sql.DB) error {
+		rows, err := db.QueryContext(ctx, getAllStaffQuery)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	staffMembers := make([]models.Staff, 0)
+		staffMembers = make([]models.Staff, 0)
 
-	for rows.Next() {
-		var staff models.Staff
-		if err := rows.Scan(&staff.ID, &staff.FirstName, &staff.LastName, &staff.Position, &staff.Status); err != nil {
-			return nil, err
+		for rows.Next() {
+			var staff models.Staff
+			if err := rows.Scan(&staff.ID, &staff.FirstName, &staff.LastName, &staff.Position, &staff.Status); err != nil {
+				return err
+			}
+			staffMembers = append(staffMembers, staff)
 		}
-		staffMembers = append(staffMembers, staff)
-	}
 
-	if err := rows.Err(); err != nil {
+		return rows.Err()
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	op.setRows(len(staffMembers))
 	return staffMembers, nil
 }
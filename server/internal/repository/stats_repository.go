@@ -4,18 +4,25 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/karanm6505/dbms/server/internal/dialect"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
 type StatsRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	reads   ReadRouter
+	dialect dialect.Dialect
 }
 
-func NewStatsRepository(db *sql.DB) *StatsRepository {
-	return &StatsRepository{db: db}
+func NewStatsRepository(db *sql.DB, opts ...Option) *StatsRepository {
+	o := resolveOptions(db, opts)
+	return &StatsRepository{db: db, reads: o.reads, dialect: o.dialect}
 }
 
-func (r *StatsRepository) GetDashboardStats(ctx context.Context) (models.DashboardStats, error) {
+func (r *StatsRepository) GetDashboardStats(ctx context.Context) (_ models.DashboardStats, err error) {
+	ctx, op := track(ctx, r.dialect, "stats.get_dashboard")
+	defer op.end(&err)
+
 	var stats models.DashboardStats
 
 	queries := map[string]*int{
@@ -27,11 +34,44 @@ func (r *StatsRepository) GetDashboardStats(ctx context.Context) (models.Dashboa
 		"SELECT COUNT(*) FROM staff":                                      &stats.TotalStaff,
 	}
 
-	for query, target := range queries {
-		row := r.db.QueryRowContext(ctx, query)
-		if err := row.Scan(target); err != nil {
-			return models.DashboardStats{}, err
+	// All counts come from the same server; a retry on the primary runs
+	// them all again.
+	err = r.reads.Read(ctx, func(db *sql.DB) error {
+		for query, target := range queries {
+			if err := db.QueryRowContext(ctx, query).Scan(target); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return models.DashboardStats{}, err
+	}
+
+	return stats, nil
+}
+
+func (r *StatsRepository) GetCirculationStats(ctx context.Context) (_ models.CirculationStats, err error) {
+	ctx, op := track(ctx, r.dialect, "stats.get_circulation")
+	defer op.end(&err)
+
+	// CASE and CURRENT_DATE keep the query portable between MySQL and
+	// PostgreSQL.
+	const query = `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN Status = 'Returned' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN Status <> 'Returned' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN Status <> 'Returned' AND Due_Date < CURRENT_DATE THEN 1 ELSE 0 END), 0)
+		FROM borrow
+	`
+
+	var stats models.CirculationStats
+	err = r.reads.Read(ctx, func(db *sql.DB) error {
+		return db.QueryRowContext(ctx, query).Scan(&stats.Checkouts, &stats.Returns, &stats.Open, &stats.Overdue)
+	})
+	if err != nil {
+		return models.CirculationStats{}, err
 	}
 
 	return stats, nil
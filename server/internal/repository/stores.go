@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// The Store interfaces are what the HTTP handlers depend on. The SQL
+// repositories in this package implement them, as does the in-memory store
+// in package memory, which is used for tests and demo mode. Every
+// implementation must enforce the rules in package library.
+
+type StudentStore interface {
+	GetAll(ctx context.Context) ([]models.Student, error)
+	GetByID(ctx context.Context, id int) (*models.Student, error)
+	// GetByEmail finds a student by email address, ignoring case. It fails
+	// with ErrAmbiguousEmail when several students share the address.
+	GetByEmail(ctx context.Context, email string) (*models.Student, error)
+	Create(ctx context.Context, student *models.Student) error
+	// Update saves every field of student. A status change must pass
+	// library.CheckTransition, and one that deactivates the student fails
+	// with library.ErrOutstandingObligations while loans are open or fines
+	// unpaid, unless override is set. An unknown ID is sql.ErrNoRows.
+	Update(ctx context.Context, student *models.Student, override bool) error
+	// GetProfile returns the sections of a student's profile selected by q.
+	// An unknown ID is sql.ErrNoRows.
+	GetProfile(ctx context.Context, id int, q models.ProfileQuery) (*models.StudentProfile, error)
+	// Import inserts students in one transaction and fills in their IDs,
+	// skipping those whose email address, ignoring case, is already in use.
+	// It returns the indexes of the skipped students. With dryRun set
+	// nothing is written.
+	Import(ctx context.Context, students []models.Student, dryRun bool) ([]int, error)
+}
+
+type BookStore interface {
+	GetAll(ctx context.Context) ([]models.Book, error)
+	GetAvailable(ctx context.Context) ([]models.Book, error)
+	// Import inserts books in one transaction and fills in their IDs,
+	// skipping those whose title and author, ignoring case, are already in
+	// the catalogue. It returns the indexes of the skipped books. With dryRun
+	// set nothing is written.
+	Import(ctx context.Context, books []models.Book, dryRun bool) ([]int, error)
+}
+
+type StaffStore interface {
+	GetAll(ctx context.Context) ([]models.Staff, error)
+}
+
+type BorrowStore interface {
+	GetAll(ctx context.Context) ([]models.BorrowRecord, error)
+	GetByID(ctx context.Context, id int) (*models.BorrowRecord, error)
+	// Checkout issues loan.BookID to loan.StudentID and fills in the ID,
+	// dates and status. It fails with library.ErrBookUnavailable,
+	// library.ErrStudentIneligible, library.ErrBorrowLimitExceeded or
+	// library.ErrUnknownReference (or the equivalent MySQL error) when a
+	// lending rule is broken.
+	Checkout(ctx context.Context, loan *models.BorrowRecord) error
+	// Return closes a loan and makes its book available again. It fails with
+	// library.ErrAlreadyReturned for a closed loan.
+	Return(ctx context.Context, id int) error
+	// Renew extends a loan of studentID by a loan period. A loan of another
+	// student is sql.ErrNoRows. It fails with library.ErrAlreadyReturned,
+	// library.ErrRenewalLimit, library.ErrStudentIneligible or
+	// library.ErrBookOnHold when a lending rule is broken.
+	Renew(ctx context.Context, id, studentID int) error
+}
+
+type StatsStore interface {
+	GetDashboardStats(ctx context.Context) (models.DashboardStats, error)
+	GetCirculationStats(ctx context.Context) (models.CirculationStats, error)
+}
+
+type MetadataStore interface {
+	DatabaseName() string
+	// Capabilities reports what the other methods can do on this database.
+	// Operations it rules out fail with errors.ErrUnsupported.
+	Capabilities() models.SchemaCapabilities
+	ListTables(ctx context.Context) ([]models.SchemaTable, error)
+	ListFunctions(ctx context.Context) ([]models.DbRoutine, error)
+	ListProcedures(ctx context.Context) ([]models.DbRoutine, error)
+	ListTriggers(ctx context.Context) ([]models.DbTrigger, error)
+	GetTableDefinition(ctx context.Context, name string) (*models.TableDefinition, error)
+	GetFunctionDefinition(ctx context.Context, name string) (*models.RoutineDefinition, error)
+	GetProcedureDefinition(ctx context.Context, name string) (*models.RoutineDefinition, error)
+	GetTriggerDefinition(ctx context.Context, name string) (*models.TriggerDefinition, error)
+	GetSchemaDiagram(ctx context.Context) (*models.SchemaDiagram, error)
+	GetSchemaSnapshot(ctx context.Context) (*models.SchemaSnapshot, error)
+	ExecuteProcedure(ctx context.Context, name string, args []any) ([]map[string]any, error)
+	ExecuteFunction(ctx context.Context, name string, args []any) (any, error)
+}
+
+type UserStore interface {
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id int64) (*models.User, error)
+	// Create fails with ErrUserAlreadyExists for a taken email.
+	Create(ctx context.Context, user *models.User) error
+	// LinkStudent links the account to a student, or unlinks it when
+	// studentID is nil. It fails with ErrStudentAlreadyLinked when another
+	// account has the student. An unknown user is sql.ErrNoRows.
+	LinkStudent(ctx context.Context, id int64, studentID *int) error
+	// SetVerification stores the hash of an email verification code. An
+	// empty hash clears it.
+	SetVerification(ctx context.Context, id int64, hash string, expires time.Time) error
+	// ConfirmEmail marks the email verified, clears the code and links the
+	// student, failing like LinkStudent.
+	ConfirmEmail(ctx context.Context, id int64, studentID int) error
+}
+
+type ConsoleStore interface {
+	QueryReadOnly(
+		ctx context.Context,
+		statement string,
+		args []any,
+		maxRows int,
+		timeout time.Duration,
+		onColumns func([]string) error,
+		onRow func([]any) error,
+	) (int, bool, error)
+	Explain(ctx context.Context, statement string, args []any, analyze bool, timeout time.Duration) (*models.QueryPlan, []byte, error)
+	LogQuery(ctx context.Context, entry *models.QueryLogEntry) error
+}
+
+type SavedQueryStore interface {
+	List(ctx context.Context) ([]models.SavedQuery, error)
+	GetBySlug(ctx context.Context, slug string) (*models.SavedQuery, error)
+	Create(ctx context.Context, saved *models.SavedQuery) error
+	Update(ctx context.Context, slug string, saved *models.SavedQuery) error
+	Delete(ctx context.Context, slug string) error
+}
+
+var (
+	_ StudentStore    = (*StudentRepository)(nil)
+	_ BookStore       = (*BookRepository)(nil)
+	_ StaffStore      = (*StaffRepository)(nil)
+	_ BorrowStore     = (*BorrowRepository)(nil)
+	_ StatsStore      = (*StatsRepository)(nil)
+	_ MetadataStore   = (*MetadataRepository)(nil)
+	_ UserStore       = (*UserRepository)(nil)
+	_ ConsoleStore    = (*ConsoleRepository)(nil)
+	_ SavedQueryStore = (*SavedQueryRepository)(nil)
+)
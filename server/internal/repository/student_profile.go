@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/library"
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// currentLoansQuery is the get_books_borrowed_by_student procedure with the
+// loan's ID and overdue days added. %s is the overdue expression.
+const currentLoansQuery = `
+	SELECT Br.Borrow_ID, B.Book_ID, B.Title, B.Author, B.Publisher, B.Year_Published, B.Genre,
+		Br.Issue_Date, Br.Due_Date, Br.Status, %s
+	FROM book B
+	JOIN borrow Br ON B.Book_ID = Br.Book_ID
+	WHERE Br.Student_ID = ?
+	  AND lower(TRIM(Br.Status)) IN ('not returned', 'issued', 'borrowed')
+	ORDER BY Br.Due_Date, Br.Borrow_ID
+`
+
+const loanHistoryQuery = selectBorrowsQuery + `
+	WHERE b.Student_ID = ?
+	ORDER BY b.Issue_Date DESC, b.Borrow_ID DESC
+	LIMIT ? OFFSET ?
+`
+
+const unpaidFinesQuery = `
+	SELECT Fine_ID, Student_ID, Borrow_ID, Amount, Reason, Issued_Date, Paid_Date
+	FROM fine
+	WHERE Student_ID = ? AND Paid_Date IS NULL
+	ORDER BY Issued_Date, Fine_ID
+`
+
+const openHoldsQuery = `
+	SELECT h.Hold_ID, h.Student_ID, h.Book_ID, b.Title, h.Placed_Date, h.Status
+	FROM hold h
+	JOIN book b ON b.Book_ID = h.Book_ID
+	WHERE h.Student_ID = ? AND h.Status IN (?, ?)
+	ORDER BY h.Placed_Date, h.Hold_ID
+`
+
+// GetProfile reads the sections of a student's profile named in q from the
+// read pool. The overdue count and borrowed count come from the schema's
+// overdueby and borrowed_count functions.
+func (r *StudentRepository) GetProfile(ctx context.Context, id int, q models.ProfileQuery) (_ *models.StudentProfile, err error) {
+	ctx, op := track(ctx, r.dialect, "students.get_profile")
+	defer op.end(&err)
+
+	var profile *models.StudentProfile
+	err = r.reads.Read(ctx, func(db *sql.DB) error {
+		var err error
+		profile, err = r.getProfile(ctx, db, id, q)
+		return err
+	})
+	return profile, err
+}
+
+func (r *StudentRepository) getProfile(ctx context.Context, db *sql.DB, id int, q models.ProfileQuery) (*models.StudentProfile, error) {
+	student, err := scanStudent(db.QueryRowContext(ctx, r.dialect.Rebind(getStudentByIDQuery), id))
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &models.StudentProfile{}
+	if q.Has(models.ProfileStudent) {
+		profile.Student = &student
+	}
+
+	if q.Has(models.ProfileLoans) || q.Has(models.ProfileOverdueCount) {
+		loans, err := r.currentLoans(ctx, db, id)
+		if err != nil {
+			return nil, err
+		}
+		if q.Has(models.ProfileLoans) {
+			profile.Loans = &loans
+		}
+		if q.Has(models.ProfileOverdueCount) {
+			overdue := 0
+			for _, loan := range loans {
+				if loan.OverdueDays > 0 {
+					overdue++
+				}
+			}
+			profile.OverdueCount = &overdue
+		}
+	}
+
+	if q.Has(models.ProfileHistory) {
+		if profile.History, err = r.loanHistory(ctx, db, id, q.Page, q.PageSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.Has(models.ProfileBorrowedCount) {
+		var count int
+		if err := db.QueryRowContext(ctx, r.dialect.Rebind(r.borrowedCountQuery()), id).Scan(&count); err != nil {
+			return nil, err
+		}
+		profile.BorrowedCount = &count
+	}
+
+	if q.Has(models.ProfileFines) {
+		if profile.Fines, err = r.unpaidFines(ctx, db, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.Has(models.ProfileHolds) {
+		holds, err := r.openHolds(ctx, db, id)
+		if err != nil {
+			return nil, err
+		}
+		profile.Holds = &holds
+	}
+
+	return profile, nil
+}
+
+// overdueDays is overdueby(Br.Due_Date). SQLite has no stored functions, so
+// it gets the function's definition inline.
+func (r *StudentRepository) overdueDays() string {
+	if r.dialect == dialect.SQLite {
+		return "CAST(julianday(date('now', 'localtime')) - julianday(Br.Due_Date) AS INTEGER)"
+	}
+	return "overdueby(Br.Due_Date)"
+}
+
+// borrowedCountQuery calls borrowed_count, or repeats its query on SQLite.
+func (r *StudentRepository) borrowedCountQuery() string {
+	if r.dialect == dialect.SQLite {
+		return "SELECT COUNT(*) FROM borrow WHERE Student_ID = ? AND Status = 'Issued'"
+	}
+	return "SELECT borrowed_count(?)"
+}
+
+func (r *StudentRepository) currentLoans(ctx context.Context, db *sql.DB, id int) ([]models.CurrentLoan, error) {
+	rows, err := db.QueryContext(ctx, r.dialect.Rebind(fmt.Sprintf(currentLoansQuery, r.overdueDays())), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	loans := make([]models.CurrentLoan, 0)
+	for rows.Next() {
+		var (
+			loan      models.CurrentLoan
+			issueDate sql.NullTime
+			dueDate   sql.NullTime
+			overdue   sql.NullInt64
+		)
+		if err := rows.Scan(
+			&loan.BorrowID,
+			&loan.BookID,
+			&loan.Title,
+			&loan.Author,
+			&loan.Publisher,
+			&loan.YearPublished,
+			&loan.Genre,
+			&issueDate,
+			&dueDate,
+			&loan.Status,
+			&overdue,
+		); err != nil {
+			return nil, err
+		}
+		loan.IssueDate = formatDate(issueDate)
+		loan.DueDate = formatDate(dueDate)
+		loan.OverdueDays = int(overdue.Int64)
+		loans = append(loans, loan)
+	}
+
+	return loans, rows.Err()
+}
+
+func (r *StudentRepository) loanHistory(ctx context.Context, db *sql.DB, id, page, pageSize int) (*models.LoanHistory, error) {
+	history := &models.LoanHistory{Page: page, PageSize: pageSize, Items: make([]models.BorrowRecord, 0)}
+
+	if err := db.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM borrow WHERE Student_ID = ?"), id).Scan(&history.Total); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, r.dialect.Rebind(loanHistoryQuery), id, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scanBorrow(rows)
+		if err != nil {
+			return nil, err
+		}
+		history.Items = append(history.Items, record)
+	}
+
+	return history, rows.Err()
+}
+
+func (r *StudentRepository) unpaidFines(ctx context.Context, db *sql.DB, id int) (*models.FineSummary, error) {
+	rows, err := db.QueryContext(ctx, r.dialect.Rebind(unpaidFinesQuery), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := &models.FineSummary{Items: make([]models.Fine, 0)}
+	for rows.Next() {
+		fine, err := scanFine(rows)
+		if err != nil {
+			return nil, err
+		}
+		summary.Outstanding += fine.Amount
+		summary.Items = append(summary.Items, fine)
+	}
+
+	return summary, rows.Err()
+}
+
+func (r *StudentRepository) openHolds(ctx context.Context, db *sql.DB, id int) ([]models.Hold, error) {
+	rows, err := db.QueryContext(ctx, r.dialect.Rebind(openHoldsQuery), id, library.HoldPending, library.HoldReady)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holds := make([]models.Hold, 0)
+	for rows.Next() {
+		var (
+			hold   models.Hold
+			placed sql.NullTime
+		)
+		if err := rows.Scan(&hold.ID, &hold.StudentID, &hold.BookID, &hold.BookTitle, &placed, &hold.Status); err != nil {
+			return nil, err
+		}
+		hold.PlacedDate = formatDate(placed)
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+func scanFine(row rowScanner) (models.Fine, error) {
+	var (
+		fine     models.Fine
+		borrowID sql.NullInt64
+		reason   sql.NullString
+		issued   sql.NullTime
+		paid     sql.NullTime
+	)
+
+	if err := row.Scan(&fine.ID, &fine.StudentID, &borrowID, &fine.Amount, &reason, &issued, &paid); err != nil {
+		return models.Fine{}, err
+	}
+
+	if borrowID.Valid {
+		id := int(borrowID.Int64)
+		fine.BorrowID = &id
+	}
+	fine.Reason = reason.String
+	fine.IssuedDate = formatDate(issued)
+	fine.PaidDate = formatDate(paid)
+	return fine, nil
+}
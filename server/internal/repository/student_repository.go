@@ -3,78 +3,214 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
 
+	"github.com/karanm6505/dbms/server/internal/dialect"
+	"github.com/karanm6505/dbms/server/internal/library"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
+const getAllStudentsQuery = `
+	SELECT Student_ID, First_Name, Last_Name, Email, Status, Status_Reason, Status_Effective_Date
+	FROM student
+	ORDER BY Student_ID
+`
+
+const getStudentsByEmailQuery = `
+	SELECT Student_ID, First_Name, Last_Name, Email, Status, Status_Reason, Status_Effective_Date
+	FROM student
+	WHERE lower(Email) = ?
+	ORDER BY Student_ID
+	LIMIT 2
+`
+
+const getStudentByIDQuery = `
+	SELECT Student_ID, First_Name, Last_Name, Email, Status, Status_Reason, Status_Effective_Date
+	FROM student
+	WHERE Student_ID = ?
+`
+
+// ErrAmbiguousEmail is returned when an email address belongs to more than
+// one student, so it cannot identify a single one.
+var ErrAmbiguousEmail = errors.New("more than one student has this email address")
+
 type StudentRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	reads   ReadRouter
+	dialect dialect.Dialect
 }
 
-func NewStudentRepository(db *sql.DB) *StudentRepository {
-	return &StudentRepository{db: db}
+func NewStudentRepository(db *sql.DB, opts ...Option) *StudentRepository {
+	o := resolveOptions(db, opts)
+	return &StudentRepository{db: db, reads: o.reads, dialect: o.dialect}
 }
 
-func (r *StudentRepository) GetAll(ctx context.Context) ([]models.Student, error) {
-	const query = `
-		SELECT Student_ID, First_Name, Last_Name, Email, Status
-		FROM student
-		ORDER BY Student_ID
-	`
+func (r *StudentRepository) GetAll(ctx context.Context) (_ []models.Student, err error) {
+	ctx, op := track(ctx, r.dialect, "students.get_all")
+	defer op.end(&err)
+
+	var students []models.Student
+	err = r.reads.Read(ctx, func(db *sql.DB) error {
+		rows, err := db.QueryContext(ctx, getAllStudentsQuery)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	rows, err := r.db.QueryContext(ctx, query)
+		students = make([]models.Student, 0)
+
+		for rows.Next() {
+			student, err := scanStudent(rows)
+			if err != nil {
+				return err
+			}
+			students = append(students, student)
+		}
+
+		return rows.Err()
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	students := make([]models.Student, 0)
+	op.setRows(len(students))
+	return students, nil
+}
+
+func (r *StudentRepository) GetByID(ctx context.Context, id int) (_ *models.Student, err error) {
+	ctx, op := track(ctx, r.dialect, "students.get_by_id")
+	defer op.end(&err)
+
+	student, err := scanStudent(r.db.QueryRowContext(ctx, r.dialect.Rebind(getStudentByIDQuery), id))
+	if err != nil {
+		return nil, err
+	}
 
+	return &student, nil
+}
+
+func (r *StudentRepository) GetByEmail(ctx context.Context, email string) (_ *models.Student, err error) {
+	ctx, op := track(ctx, r.dialect, "students.get_by_email")
+	defer op.end(&err)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(getStudentsByEmailQuery), strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := make([]models.Student, 0, 2)
 	for rows.Next() {
-		var student models.Student
-		if err := rows.Scan(&student.ID, &student.FirstName, &student.LastName, &student.Email, &student.Status); err != nil {
+		student, err := scanStudent(rows)
+		if err != nil {
 			return nil, err
 		}
 		students = append(students, student)
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return students, nil
+	switch len(students) {
+	case 0:
+		return nil, sql.ErrNoRows
+	case 1:
+		return &students[0], nil
+	default:
+		return nil, ErrAmbiguousEmail
+	}
 }
 
-func (r *StudentRepository) GetByID(ctx context.Context, id int) (*models.Student, error) {
-	const query = `
-		SELECT Student_ID, First_Name, Last_Name, Email, Status
-		FROM student
-		WHERE Student_ID = ?
+func (r *StudentRepository) Create(ctx context.Context, student *models.Student) (err error) {
+	ctx, op := track(ctx, r.dialect, "students.create")
+	defer op.end(&err)
+
+	const insertQuery = `
+		INSERT INTO student (First_Name, Last_Name, Email, Status)
+		VALUES (?, ?, ?, ?)
 	`
 
-	var student models.Student
-	if err := r.db.QueryRowContext(ctx, query, id).Scan(&student.ID, &student.FirstName, &student.LastName, &student.Email, &student.Status); err != nil {
-		return nil, err
+	return retryDuplicate(func() error {
+		id, err := insertID(ctx, r.db, r.dialect, insertQuery, "Student_ID", student.FirstName, student.LastName, student.Email, student.Status)
+		if err != nil {
+			return err
+		}
+		student.ID = int(id)
+		return nil
+	})
+}
+
+// Update locks the student row, so a checkout cannot slip in between the
+// check for open loans and the status change.
+func (r *StudentRepository) Update(ctx context.Context, student *models.Student, override bool) (err error) {
+	ctx, op := track(ctx, r.dialect, "students.update")
+	defer op.end(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	return &student, nil
-}
+	var current sql.NullString
+	if err := tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT Status FROM student WHERE Student_ID = ?"+r.dialect.ForUpdate()), student.ID).Scan(&current); err != nil {
+		return err
+	}
+
+	if err := library.CheckTransition(current.String, student.Status); err != nil {
+		return err
+	}
 
-func (r *StudentRepository) Create(ctx context.Context, student *models.Student) error {
-	const nextIDQuery = `
-		SELECT COALESCE(MAX(Student_ID), 0) + 1
-		FROM student
+	if !override && current.String != student.Status && library.Deactivates(student.Status) {
+		const outstandingQuery = `
+			SELECT
+				(SELECT COUNT(*) FROM borrow WHERE Student_ID = ? AND Status = ?),
+				(SELECT COUNT(*) FROM fine WHERE Student_ID = ? AND Paid_Date IS NULL)
+		`
+
+		var loans, fines int
+		if err := tx.QueryRowContext(ctx, r.dialect.Rebind(outstandingQuery), student.ID, library.LoanIssued, student.ID).Scan(&loans, &fines); err != nil {
+			return err
+		}
+		if loans > 0 || fines > 0 {
+			return library.OutstandingError(loans, fines)
+		}
+	}
+
+	const updateQuery = `
+		UPDATE student
+		SET First_Name = ?, Last_Name = ?, Email = ?, Status = ?, Status_Reason = ?, Status_Effective_Date = ?
+		WHERE Student_ID = ?
 	`
 
-	if err := r.db.QueryRowContext(ctx, nextIDQuery).Scan(&student.ID); err != nil {
+	if _, err := tx.ExecContext(ctx, r.dialect.Rebind(updateQuery),
+		student.FirstName, student.LastName, student.Email, student.Status,
+		nullIfEmpty(student.StatusReason), nullIfEmpty(student.StatusEffectiveDate), student.ID,
+	); err != nil {
 		return err
 	}
 
-	const insertQuery = `
-		INSERT INTO student (Student_ID, First_Name, Last_Name, Email, Status)
-		VALUES (?, ?, ?, ?, ?)
-	`
+	return tx.Commit()
+}
+
+func scanStudent(row rowScanner) (models.Student, error) {
+	var (
+		student   models.Student
+		reason    sql.NullString
+		effective sql.NullTime
+	)
+
+	if err := row.Scan(&student.ID, &student.FirstName, &student.LastName, &student.Email, &student.Status, &reason, &effective); err != nil {
+		return models.Student{}, err
+	}
+
+	student.StatusReason = reason.String
+	student.StatusEffectiveDate = formatDate(effective)
+	return student, nil
+}
 
-	_, err := r.db.ExecContext(ctx, insertQuery, student.ID, student.FirstName, student.LastName, student.Email, student.Status)
-	return err
+// nullIfEmpty stores an empty optional column as NULL.
+func nullIfEmpty(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
 }
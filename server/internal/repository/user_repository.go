@@ -4,91 +4,195 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
-	"github.com/go-sql-driver/mysql"
-
+	"github.com/karanm6505/dbms/server/internal/dialect"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
-var ErrUserAlreadyExists = errors.New("user already exists")
+var (
+	ErrUserAlreadyExists    = errors.New("user already exists")
+	ErrStudentAlreadyLinked = errors.New("student is already linked to another account")
+)
+
+const getUserByEmailQuery = `
+	SELECT user_id, email, password_hash, role, student_id, email_verified_at,
+		verification_hash, verification_expires_at, created_at, updated_at
+	FROM users
+	WHERE email = ?
+`
+
+const getUserByIDQuery = `
+	SELECT user_id, email, password_hash, role, student_id, email_verified_at,
+		verification_hash, verification_expires_at, created_at, updated_at
+	FROM users
+	WHERE user_id = ?
+`
 
 type UserRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect.Dialect
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *sql.DB, opts ...Option) *UserRepository {
+	return &UserRepository{db: db, dialect: resolveOptions(db, opts).dialect}
 }
 
-func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	const query = `
-		SELECT user_id, email, password_hash, role, created_at, updated_at
-		FROM users
-		WHERE email = ?
-	`
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (_ *models.User, err error) {
+	ctx, op := track(ctx, r.dialect, "users.get_by_email")
+	defer op.end(&err)
 
-	user := &models.User{}
-	if err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	); err != nil {
+	user, err := scanUser(r.db.QueryRowContext(ctx, r.dialect.Rebind(getUserByEmailQuery), email))
+	if err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (r *UserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
-	const query = `
-		SELECT user_id, email, password_hash, role, created_at, updated_at
-		FROM users
-		WHERE user_id = ?
-	`
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (_ *models.User, err error) {
+	ctx, op := track(ctx, r.dialect, "users.get_by_id")
+	defer op.end(&err)
 
-	user := &models.User{}
-	if err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	); err != nil {
+	user, err := scanUser(r.db.QueryRowContext(ctx, r.dialect.Rebind(getUserByIDQuery), id))
+	if err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) (err error) {
+	ctx, op := track(ctx, r.dialect, "users.create")
+	defer op.end(&err)
+
 	const query = `
 		INSERT INTO users (email, password_hash, role)
 		VALUES (?, ?, ?)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, user.Email, user.PasswordHash, user.Role)
+	id, err := insertID(ctx, r.db, r.dialect, query, "user_id", user.Email, user.PasswordHash, user.Role)
 	if err != nil {
-		var mysqlErr *mysql.MySQLError
-		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		if isUniqueViolation(err) {
 			return ErrUserAlreadyExists
 		}
 		return err
 	}
 
-	id, err := result.LastInsertId()
+	fresh, err := r.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	fresh, err := r.GetByID(ctx, id)
+	*user = *fresh
+	return nil
+}
+
+// LinkStudent links the account to a student, or unlinks it when studentID
+// is nil.
+func (r *UserRepository) LinkStudent(ctx context.Context, id int64, studentID *int) (err error) {
+	ctx, op := track(ctx, r.dialect, "users.link_student")
+	defer op.end(&err)
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind("UPDATE users SET student_id = ? WHERE user_id = ?"), studentID, id)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrStudentAlreadyLinked
+		}
 		return err
 	}
+	return r.requireUser(ctx, result, id)
+}
 
-	*user = *fresh
-	return nil
+// SetVerification stores the hash of a new email verification code,
+// replacing any earlier one. An empty hash clears the pending code.
+func (r *UserRepository) SetVerification(ctx context.Context, id int64, hash string, expires time.Time) (err error) {
+	ctx, op := track(ctx, r.dialect, "users.set_verification")
+	defer op.end(&err)
+
+	const query = `
+		UPDATE users
+		SET verification_hash = ?, verification_expires_at = ?
+		WHERE user_id = ?
+	`
+
+	expiresAt := sql.NullTime{Time: expires.UTC(), Valid: hash != ""}
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), nullIfEmpty(hash), expiresAt, id)
+	if err != nil {
+		return err
+	}
+	return r.requireUser(ctx, result, id)
+}
+
+// ConfirmEmail records that the account's email was verified, clears the
+// code and links the account to the student with that email.
+func (r *UserRepository) ConfirmEmail(ctx context.Context, id int64, studentID int) (err error) {
+	ctx, op := track(ctx, r.dialect, "users.confirm_email")
+	defer op.end(&err)
+
+	const query = `
+		UPDATE users
+		SET email_verified_at = ?, verification_hash = NULL, verification_expires_at = NULL, student_id = ?
+		WHERE user_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), time.Now().UTC(), studentID, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrStudentAlreadyLinked
+		}
+		return err
+	}
+	return r.requireUser(ctx, result, id)
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var (
+		user       models.User
+		studentID  sql.NullInt64
+		verifiedAt sql.NullTime
+		hash       sql.NullString
+		expiresAt  sql.NullTime
+	)
+
+	if err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&studentID,
+		&verifiedAt,
+		&hash,
+		&expiresAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if studentID.Valid {
+		id := int(studentID.Int64)
+		user.StudentID = &id
+	}
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
+	user.VerificationHash = hash.String
+	if expiresAt.Valid {
+		user.VerificationExpiresAt = &expiresAt.Time
+	}
+	return &user, nil
+}
+
+// requireUser returns sql.ErrNoRows when an UPDATE of user id matched no
+// row. MySQL reports zero affected rows when nothing changed, so an
+// unchanged user is told apart from a missing one.
+func (r *UserRepository) requireUser(ctx context.Context, result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	var exists int
+	return r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT 1 FROM users WHERE user_id = ?"), id).Scan(&exists)
 }
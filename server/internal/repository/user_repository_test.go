@@ -10,9 +10,16 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/karanm6505/dbms/server/internal/dialect"
 	"github.com/karanm6505/dbms/server/internal/models"
 )
 
+var userColumns = []string{
+	"user_id", "email", "password_hash", "role", "student_id", "email_verified_at",
+	"verification_hash", "verification_expires_at", "created_at", "updated_at",
+}
+
 func TestUserRepository_GetByEmail(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -23,11 +30,12 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	email := "admin@example.com"
-	rows := sqlmock.NewRows([]string{"user_id", "email", "password_hash", "role", "created_at", "updated_at"}).
-		AddRow(int64(1), email, "hash", models.RoleAdmin, time.Now(), time.Now())
+	rows := sqlmock.NewRows(userColumns).
+		AddRow(int64(1), email, "hash", models.RoleAdmin, nil, nil, nil, nil, time.Now(), time.Now())
 
 	mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT user_id, email, password_hash, role, created_at, updated_at
+		SELECT user_id, email, password_hash, role, student_id, email_verified_at,
+			verification_hash, verification_expires_at, created_at, updated_at
 		FROM users
 		WHERE email = ?
 	`)).
@@ -59,7 +67,8 @@ func TestUserRepository_GetByID_NotFound(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT user_id, email, password_hash, role, created_at, updated_at
+		SELECT user_id, email, password_hash, role, student_id, email_verified_at,
+			verification_hash, verification_expires_at, created_at, updated_at
 		FROM users
 		WHERE user_id = ?
 	`)).
@@ -90,8 +99,8 @@ func TestUserRepository_Create(t *testing.T) {
 
 	repo := NewUserRepository(db)
 
-	queryRows := sqlmock.NewRows([]string{"user_id", "email", "password_hash", "role", "created_at", "updated_at"}).
-		AddRow(int64(1), "new@example.com", "hash", models.RoleViewer, time.Now(), time.Now())
+	queryRows := sqlmock.NewRows(userColumns).
+		AddRow(int64(1), "new@example.com", "hash", models.RoleViewer, nil, nil, nil, nil, time.Now(), time.Now())
 
 	mock.ExpectExec(regexp.QuoteMeta(`
 		INSERT INTO users (email, password_hash, role)
@@ -101,7 +110,8 @@ func TestUserRepository_Create(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT user_id, email, password_hash, role, created_at, updated_at
+		SELECT user_id, email, password_hash, role, student_id, email_verified_at,
+			verification_hash, verification_expires_at, created_at, updated_at
 		FROM users
 		WHERE user_id = ?
 	`)).
@@ -150,3 +160,101 @@ func TestUserRepository_CreateDuplicateEmail(t *testing.T) {
 		t.Fatalf("expectations were not met: %v", err)
 	}
 }
+
+func TestUserRepository_CreatePostgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo := NewUserRepository(db, WithDialect(dialect.Postgres))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		INSERT INTO users (email, password_hash, role)
+		VALUES ($1, $2, $3)
+	 RETURNING user_id`)).
+		WithArgs("new@example.com", "hash", models.RoleViewer).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(int64(7)))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE user_id = $1`)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(int64(7), "new@example.com", "hash", models.RoleViewer, nil, nil, nil, nil, time.Now(), time.Now()))
+
+	user := &models.User{Email: "new@example.com", PasswordHash: "hash", Role: models.RoleViewer}
+	if err := repo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	if user.ID != 7 {
+		t.Fatalf("expected user ID 7, got %d", user.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations were not met: %v", err)
+	}
+}
+
+func TestUserRepository_CreateDuplicateEmailPostgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo := NewUserRepository(db, WithDialect(dialect.Postgres))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO users`)).
+		WillReturnError(&pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"})
+
+	user := &models.User{Email: "duplicate@example.com", PasswordHash: "hash", Role: models.RoleViewer}
+
+	err = repo.Create(context.Background(), user)
+	if !errors.Is(err, ErrUserAlreadyExists) {
+		t.Fatalf("expected ErrUserAlreadyExists, got %v", err)
+	}
+}
+
+func TestUserRepository_LinkStudent(t *testing.T) {
+	db := openMigratedSQLite(t)
+	repo := NewUserRepository(db, WithDialect(dialect.SQLite))
+	ctx := context.Background()
+
+	first := &models.User{Email: "first@example.com", PasswordHash: "hash", Role: models.RolePatron}
+	second := &models.User{Email: "second@example.com", PasswordHash: "hash", Role: models.RolePatron}
+	for _, user := range []*models.User{first, second} {
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Create returned error: %v", err)
+		}
+	}
+
+	if err := repo.SetVerification(ctx, first.ID, "abc", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("SetVerification returned error: %v", err)
+	}
+	if err := repo.ConfirmEmail(ctx, first.ID, 3); err != nil {
+		t.Fatalf("ConfirmEmail returned error: %v", err)
+	}
+
+	linked, err := repo.GetByID(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if linked.StudentID == nil || *linked.StudentID != 3 || linked.EmailVerifiedAt == nil || linked.VerificationHash != "" || linked.VerificationExpiresAt != nil {
+		t.Fatalf("unexpected user after confirmation %+v", linked)
+	}
+
+	studentID := 3
+	if err := repo.LinkStudent(ctx, second.ID, &studentID); !errors.Is(err, ErrStudentAlreadyLinked) {
+		t.Fatalf("expected ErrStudentAlreadyLinked, got %v", err)
+	}
+	if err := repo.LinkStudent(ctx, first.ID, nil); err != nil {
+		t.Fatalf("LinkStudent returned error: %v", err)
+	}
+	if err := repo.LinkStudent(ctx, first.ID, nil); err != nil {
+		t.Fatalf("unlinking twice returned error: %v", err)
+	}
+	if err := repo.LinkStudent(ctx, 999, nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows for an unknown user, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package sqlguard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamedParameters returns the distinct :name placeholders of a statement in
+// the order they first appear.
+func NamedParameters(statement string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+
+	scanNamed(statement, func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}, nil)
+
+	return names
+}
+
+// BindNamed rewrites :name placeholders into positional ? placeholders and
+// returns the matching argument list.
+func BindNamed(statement string, values map[string]any) (string, []any, error) {
+	var (
+		b       strings.Builder
+		args    []any
+		missing string
+	)
+
+	scanNamed(statement, func(name string) {
+		value, ok := values[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		args = append(args, value)
+		b.WriteByte('?')
+	}, &b)
+
+	if missing != "" {
+		return "", nil, fmt.Errorf("no value bound for parameter %q", missing)
+	}
+
+	return b.String(), args, nil
+}
+
+func scanNamed(statement string, onName func(string), out *strings.Builder) {
+	write := func(s string) {
+		if out != nil {
+			out.WriteString(s)
+		}
+	}
+
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(statement, i)
+			write(statement[i:end])
+			i = end
+		case c == ':' && i+1 < len(statement) && isWordStart(statement[i+1]) && (i == 0 || statement[i-1] != ':'):
+			start := i + 1
+			end := start
+			for end < len(statement) && (isWordStart(statement[end]) || (statement[end] >= '0' && statement[end] <= '9')) {
+				end++
+			}
+			onName(statement[start:end])
+			i = end
+		default:
+			write(string(c))
+			i++
+		}
+	}
+}
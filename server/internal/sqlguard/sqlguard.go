@@ -0,0 +1,220 @@
+package sqlguard
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/migrate"
+)
+
+// Errors returned by ParseReadOnly for statements the console refuses.
+var (
+	ErrEmptyStatement     = errors.New("statement is empty")
+	ErrMultipleStatements = errors.New("only a single statement is allowed")
+	ErrNotReadOnly        = errors.New("only SELECT, SHOW and EXPLAIN statements are allowed")
+	ErrForbiddenClause    = errors.New("statement contains a clause that is not allowed in read-only mode")
+)
+
+// Kinds of read-only statement. WITH queries are KindSelect, and DESCRIBE is
+// KindExplain.
+const (
+	KindSelect  = "SELECT"
+	KindShow    = "SHOW"
+	KindExplain = "EXPLAIN"
+)
+
+var writeKeywords = map[string]bool{
+	"INSERT":   true,
+	"UPDATE":   true,
+	"DELETE":   true,
+	"REPLACE":  true,
+	"CREATE":   true,
+	"ALTER":    true,
+	"DROP":     true,
+	"TRUNCATE": true,
+	"CALL":     true,
+	"GRANT":    true,
+	"REVOKE":   true,
+	"SET":      true,
+	"LOAD":     true,
+	"HANDLER":  true,
+	"DO":       true,
+}
+
+var explainOptions = map[string]bool{
+	"EXTENDED":    true,
+	"PARTITIONS":  true,
+	"FORMAT":      true,
+	"TRADITIONAL": true,
+	"JSON":        true,
+	"TREE":        true,
+}
+
+// Statement is a statement accepted by ParseReadOnly. Text has its comments
+// removed and is what should be run. For EXPLAIN, Analyze reports whether it
+// executes the query, and Target is the first keyword of what is explained.
+type Statement struct {
+	Text    string
+	Kind    string
+	Analyze bool
+	Target  string
+}
+
+// ParseReadOnly accepts a single SELECT, SHOW or EXPLAIN statement and
+// rejects anything that could write, lock rows or run a MySQL versioned
+// comment. Comments and literals are skipped, so neither can hide the verb
+// or trip a false rejection.
+func ParseReadOnly(input string) (Statement, error) {
+	statements := migrate.SplitStatements(input)
+	switch {
+	case len(statements) == 0:
+		return Statement{}, ErrEmptyStatement
+	case len(statements) > 1:
+		return Statement{}, ErrMultipleStatements
+	}
+
+	text := statements[0]
+	if strings.Contains(text, "/*!") {
+		return Statement{}, ErrForbiddenClause
+	}
+
+	words := Keywords(text)
+	if len(words) == 0 {
+		return Statement{}, ErrEmptyStatement
+	}
+
+	statement := Statement{Text: text}
+
+	switch words[0] {
+	case "SELECT", "WITH":
+		statement.Kind = KindSelect
+		if err := checkSelect(words); err != nil {
+			return Statement{}, err
+		}
+	case "SHOW":
+		statement.Kind = KindShow
+	case "EXPLAIN", "DESCRIBE", "DESC":
+		statement.Kind = KindExplain
+		rest := words[1:]
+		for len(rest) > 0 && (explainOptions[rest[0]] || rest[0] == "ANALYZE") {
+			if rest[0] == "ANALYZE" {
+				statement.Analyze = true
+			}
+			rest = rest[1:]
+		}
+		if len(rest) > 0 {
+			statement.Target = rest[0]
+		}
+		if writeKeywords[statement.Target] {
+			return Statement{}, ErrNotReadOnly
+		}
+		if statement.Target == "SELECT" || statement.Target == "WITH" {
+			if err := checkSelect(rest); err != nil {
+				return Statement{}, err
+			}
+		}
+	default:
+		return Statement{}, ErrNotReadOnly
+	}
+
+	return statement, nil
+}
+
+func checkSelect(words []string) error {
+	for i, word := range words {
+		switch {
+		case word == "INTO":
+			return ErrForbiddenClause
+		case writeKeywords[word] && words[0] == "WITH":
+			return ErrNotReadOnly
+		case word == "FOR" && i+1 < len(words) && (words[i+1] == "UPDATE" || words[i+1] == "SHARE"):
+			return ErrForbiddenClause
+		case word == "LOCK" && i+2 < len(words) && words[i+1] == "IN" && words[i+2] == "SHARE":
+			return ErrForbiddenClause
+		}
+	}
+	return nil
+}
+
+// Keywords returns the upper-cased bare words of a statement, skipping string
+// literals, quoted identifiers and comments. The contents of a MySQL /*! */
+// comment are executed by the server, so they are scanned like the rest.
+func Keywords(statement string) []string {
+	words := make([]string, 0)
+
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(statement, i)
+		case c == '#' || isDashComment(statement, i):
+			if end := strings.IndexByte(statement[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(statement)
+			}
+		case strings.HasPrefix(statement[i:], "/*") && !strings.HasPrefix(statement[i:], "/*!"):
+			if end := strings.Index(statement[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(statement)
+			}
+		case isWordStart(c):
+			start := i
+			for i < len(statement) && isWordChar(statement[i]) {
+				i++
+			}
+			words = append(words, strings.ToUpper(statement[start:i]))
+		case c == '@':
+			for i++; i < len(statement) && (isWordChar(statement[i]) || statement[i] == '@'); i++ {
+			}
+		default:
+			i++
+		}
+	}
+
+	return words
+}
+
+func skipQuoted(statement string, start int) int {
+	quote := statement[start]
+	for i := start + 1; i < len(statement); i++ {
+		switch statement[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(statement) && statement[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(statement)
+}
+
+// isDashComment follows MySQL, where -- only starts a comment when followed
+// by whitespace, like migrate.SplitStatements does.
+func isDashComment(statement string, i int) bool {
+	if !strings.HasPrefix(statement[i:], "--") {
+		return false
+	}
+	if i+2 == len(statement) {
+		return true
+	}
+	switch statement[i+2] {
+	case ' ', '\t', '\r', '\n':
+		return true
+	}
+	return false
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || c == '$' || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,122 @@
+package sqlguard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseReadOnly(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		kind      string
+		err       error
+	}{
+		{"select", "SELECT * FROM book WHERE Status = 'Available';", KindSelect, nil},
+		{"cte", "WITH issued AS (SELECT * FROM borrow) SELECT COUNT(*) FROM issued", KindSelect, nil},
+		{"show", "show tables", KindShow, nil},
+		{"explain", "EXPLAIN FORMAT=JSON SELECT * FROM borrow", KindExplain, nil},
+		{"describe", "DESCRIBE book", KindExplain, nil},
+		{"keyword in string", "SELECT 'DELETE FROM book; INTO' AS note", KindSelect, nil},
+		{"comment only", "-- nothing here", "", ErrEmptyStatement},
+		{"multiple", "SELECT 1; SELECT 2", "", ErrMultipleStatements},
+		{"delete", "DELETE FROM book", "", ErrNotReadOnly},
+		{"call", "CALL add_new_book('a','b','c',2020,'d')", "", ErrNotReadOnly},
+		{"into outfile", "SELECT * FROM users INTO OUTFILE '/tmp/users.csv'", "", ErrForbiddenClause},
+		{"into variable", "SELECT COUNT(*) INTO @n FROM book", "", ErrForbiddenClause},
+		{"for update", "SELECT * FROM book FOR UPDATE", "", ErrForbiddenClause},
+		{"lock in share mode", "SELECT * FROM book LOCK IN SHARE MODE", "", ErrForbiddenClause},
+		{"version comment", "SELECT 1 /*!50000 INTO OUTFILE '/tmp/x' */", "", ErrForbiddenClause},
+		{"cte delete", "WITH x AS (SELECT 1) DELETE FROM book", "", ErrNotReadOnly},
+		{"explain analyze delete", "EXPLAIN ANALYZE DELETE FROM book", "", ErrNotReadOnly},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := ParseReadOnly(tc.statement)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if statement.Kind != tc.kind {
+				t.Fatalf("expected kind %q, got %q", tc.kind, statement.Kind)
+			}
+		})
+	}
+}
+
+func TestParseReadOnlyComments(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		kind      string
+		err       error
+	}{
+		{"block comment before delete", "/* x */ DELETE FROM book", "", ErrNotReadOnly},
+		{"line comment before delete", "-- report\nDELETE FROM book", "", ErrNotReadOnly},
+		{"hash comment before drop", "# report\nDROP TABLE book", "", ErrNotReadOnly},
+		{"statement in line comment", "SELECT 1 -- ; DROP TABLE book", KindSelect, nil},
+		{"clause in block comment", "SELECT * FROM book /* FOR UPDATE */", KindSelect, nil},
+		{"clause in hash comment", "SELECT * FROM book # INTO OUTFILE", KindSelect, nil},
+		{"block comment before select", "/* weekly */ SELECT * FROM book", KindSelect, nil},
+		{"subtraction is not a comment", "SELECT 1--1 INTO @n", "", ErrForbiddenClause},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := ParseReadOnly(tc.statement)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if statement.Kind != tc.kind {
+				t.Fatalf("expected kind %q, got %q", tc.kind, statement.Kind)
+			}
+		})
+	}
+}
+
+func TestKeywordsSkipsComments(t *testing.T) {
+	got := Keywords("/* DELETE */ SELECT a -- INTO\n, b # FOR UPDATE\nFROM t /*!50000 INTO */")
+	want := []string{"SELECT", "A", "B", "FROM", "T", "INTO"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseReadOnlyExplainAnalyze(t *testing.T) {
+	statement, err := ParseReadOnly("EXPLAIN ANALYZE SELECT * FROM borrow")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !statement.Analyze || statement.Target != "SELECT" {
+		t.Fatalf("unexpected statement %+v", statement)
+	}
+}
+
+func TestBindNamed(t *testing.T) {
+	statement := "SELECT * FROM book WHERE (:genre IS NULL OR Genre = :genre) AND Title <> ':skip' LIMIT :limit"
+
+	names := NamedParameters(statement)
+	if len(names) != 2 || names[0] != "genre" || names[1] != "limit" {
+		t.Fatalf("unexpected parameters %v", names)
+	}
+
+	bound, args, err := BindNamed(statement, map[string]any{"genre": "AI", "limit": 5})
+	if err != nil {
+		t.Fatalf("BindNamed returned error: %v", err)
+	}
+
+	expect := "SELECT * FROM book WHERE (? IS NULL OR Genre = ?) AND Title <> ':skip' LIMIT ?"
+	if bound != expect {
+		t.Fatalf("expected %q, got %q", expect, bound)
+	}
+
+	if len(args) != 3 || args[0] != "AI" || args[1] != "AI" || args[2] != 5 {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	if _, _, err := BindNamed(statement, map[string]any{"genre": "AI"}); err == nil {
+		t.Fatal("expected error for unbound parameter")
+	}
+}
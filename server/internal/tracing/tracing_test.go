@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/karanm6505/dbms/server/internal/dialect"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	return recorder
+}
+
+func TestMiddlewareContinuesTraceAndNamesSpanAfterRoute(t *testing.T) {
+	recorder := recordSpans(t)
+
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/api/students/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := StartQuery(r.Context(), dialect.MySQL, "students.get_by_id")
+		EndQuery(span, 1, nil)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/students/4", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected query and server spans, got %d", len(spans))
+	}
+
+	query, server := spans[0], spans[1]
+	if server.Name() != "GET /api/students/{id}" {
+		t.Fatalf("unexpected server span name %q", server.Name())
+	}
+	if server.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("server span did not continue the incoming trace")
+	}
+	if query.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Fatalf("query span is not a child of the server span")
+	}
+	if !hasAttribute(query.Attributes(), attribute.Int("db.rows", 1)) {
+		t.Fatalf("query span is missing row count: %v", query.Attributes())
+	}
+}
+
+func TestEndQueryRecordsError(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := StartQuery(context.Background(), dialect.MySQL, "books.get_all")
+	EndQuery(span, -1, errors.New("connection reset"))
+
+	ended := recorder.Ended()[0]
+	if ended.Status().Code != codes.Error || len(ended.Events()) != 1 {
+		t.Fatalf("expected error status and exception event, got %v %v", ended.Status(), ended.Events())
+	}
+}
+
+func TestStartQueryLabelsDatabaseSystem(t *testing.T) {
+	recorder := recordSpans(t)
+
+	for d, want := range map[dialect.Dialect]attribute.KeyValue{
+		dialect.MySQL:    semconv.DBSystemMySQL,
+		dialect.Postgres: semconv.DBSystemPostgreSQL,
+		dialect.SQLite:   semconv.DBSystemSqlite,
+	} {
+		_, span := StartQuery(context.Background(), d, "books.get_all")
+		EndQuery(span, 0, nil)
+
+		spans := recorder.Ended()
+		if got := spans[len(spans)-1].Attributes(); !hasAttribute(got, want) {
+			t.Errorf("%s: expected %v, got %v", d, want, got)
+		}
+	}
+}
+
+func hasAttribute(attributes []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attributes {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
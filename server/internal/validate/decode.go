@@ -0,0 +1,88 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// DefaultMaxBodyBytes bounds JSON request bodies.
+const DefaultMaxBodyBytes = 1 << 20
+
+var (
+	ErrEmptyBody    = errors.New("request body is empty")
+	ErrBodyTooLarge = errors.New("request body is too large")
+)
+
+// Decode reads a single JSON document of at most maxBytes into dst, rejecting
+// fields dst does not declare, then validates it with Struct. Decoding
+// problems are reported as an *Error so clients get the same shape either way.
+func Decode(w http.ResponseWriter, r *http.Request, dst any, maxBytes int64) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ErrEmptyBody
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return invalid("body", "invalid_json", "request body must contain a single JSON object")
+	}
+
+	return Struct(dst)
+}
+
+func decodeError(err error) error {
+	var (
+		maxBytesErr *http.MaxBytesError
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody
+	case errors.As(err, &maxBytesErr):
+		return ErrBodyTooLarge
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return invalid("body", "invalid_json", "request body is not valid JSON")
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		return invalid(field, "type", fmt.Sprintf("%s must be a %s", field, jsonType(typeErr.Type.Kind().String())))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return invalid(field, "unknown", fmt.Sprintf("%s is not a recognised field", field))
+	default:
+		return invalid("body", "invalid_json", "request body is not valid JSON")
+	}
+}
+
+func invalid(field, code, message string) error {
+	return &Error{Fields: []models.FieldError{{Field: field, Code: code, Message: message}}}
+}
+
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "slice" || kind == "array":
+		return "list"
+	case kind == "struct" || kind == "map":
+		return "object"
+	case kind == "bool":
+		return "boolean"
+	default:
+		return kind
+	}
+}
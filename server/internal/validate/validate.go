@@ -0,0 +1,196 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/karanm6505/dbms/server/internal/models"
+)
+
+// Rules are declared with a `validate` struct tag, e.g.
+//
+//	Email  string `json:"email" validate:"required,max=100,email"`
+//	Status string `json:"status" validate:"oneof=Active|Inactive"`
+//
+// Supported rules: required, min=N, max=N (characters for strings, value for
+// numbers, elements for slices), maxbytes=N (UTF-8 bytes of a string), email,
+// oneof=a|b|c, year and date (YYYY-MM-DD). Only required
+// applies to empty values; every other rule is skipped when the field is
+// empty so optional fields need no special casing. The notrim rule keeps
+// surrounding whitespace, which matters for secrets such as passwords.
+
+// MySQL's YEAR type stores 1901 to 2155.
+const (
+	minYear = 1901
+	maxYear = 2155
+)
+
+// Error carries every rejected field of a request.
+type Error struct {
+	Fields []models.FieldError
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Struct trims every string field of the struct v points to and checks it
+// against its rules. It returns nil or an *Error listing all failures.
+func Struct(v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		panic("validate: Struct requires a pointer to a struct")
+	}
+
+	value = value.Elem()
+	fields := make([]models.FieldError, 0)
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("validate")
+		rules := strings.Split(tag, ",")
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.String && !slices.Contains(rules, "notrim") {
+			fieldValue.SetString(strings.TrimSpace(fieldValue.String()))
+		}
+
+		if tag == "" {
+			continue
+		}
+
+		name := jsonName(field)
+		for _, rule := range rules {
+			if failure := check(name, rule, fieldValue); failure != nil {
+				fields = append(fields, *failure)
+				// One message per field is enough to act on.
+				break
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return &Error{Fields: fields}
+}
+
+func check(name, rule string, value reflect.Value) *models.FieldError {
+	rule, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+	if rule == "required" {
+		if value.IsZero() || (value.Kind() == reflect.Slice && value.Len() == 0) {
+			return fail(name, "required", "%s is required", name)
+		}
+		return nil
+	}
+
+	if value.IsZero() {
+		return nil
+	}
+
+	switch rule {
+	case "notrim":
+		// Applied by Struct before any rule runs.
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validate: invalid %s limit %q", rule, arg))
+		}
+		return checkBound(name, rule, limit, value)
+	case "maxbytes":
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			panic(fmt.Sprintf("validate: invalid maxbytes limit %q", arg))
+		}
+		if len(value.String()) > limit {
+			return fail(name, "max_bytes", "%s must be at most %d bytes", name, limit)
+		}
+	case "email":
+		address, err := mail.ParseAddress(value.String())
+		if err != nil || address.Address != value.String() {
+			return fail(name, "email", "%s must be a valid email address", name)
+		}
+	case "oneof":
+		options := strings.Split(arg, "|")
+		for _, option := range options {
+			if value.String() == option {
+				return nil
+			}
+		}
+		return fail(name, "oneof", "%s must be one of %s", name, strings.Join(options, ", "))
+	case "year":
+		if year := value.Int(); year < minYear || year > maxYear {
+			return fail(name, "year", "%s must be between %d and %d", name, minYear, maxYear)
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value.String()); err != nil {
+			return fail(name, "date", "%s must be a date in YYYY-MM-DD format", name)
+		}
+	default:
+		panic(fmt.Sprintf("validate: unknown rule %q", rule))
+	}
+
+	return nil
+}
+
+func checkBound(name, rule string, limit float64, value reflect.Value) *models.FieldError {
+	var (
+		actual float64
+		unit   string
+	)
+
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		actual, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	default:
+		panic(fmt.Sprintf("validate: %s does not apply to %s", rule, value.Kind()))
+	}
+
+	switch {
+	case rule == "max" && actual > limit:
+		if unit != "" {
+			return fail(name, "max_length", "%s must be at most %g%s", name, limit, unit)
+		}
+		return fail(name, "max", "%s must be at most %g", name, limit)
+	case rule == "min" && actual < limit:
+		if unit != "" {
+			return fail(name, "min_length", "%s must be at least %g%s", name, limit, unit)
+		}
+		return fail(name, "min", "%s must be at least %g", name, limit)
+	}
+
+	return nil
+}
+
+func fail(field, code, format string, args ...any) *models.FieldError {
+	return &models.FieldError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
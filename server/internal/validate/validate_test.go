@@ -0,0 +1,140 @@
+package validate
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type studentRequest struct {
+	FirstName string `json:"first_name" validate:"required,max=50"`
+	Email     string `json:"email" validate:"required,max=100,email"`
+	Status    string `json:"status" validate:"oneof=Active|Inactive"`
+	Year      int    `json:"year" validate:"year"`
+	Joined    string `json:"joined" validate:"date"`
+	Note      string `json:"note"`
+}
+
+func fieldCodes(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var validationErr *Error
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+
+	codes := make(map[string]string, len(validationErr.Fields))
+	for _, field := range validationErr.Fields {
+		codes[field.Field] = field.Code
+	}
+	return codes
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	req := studentRequest{
+		FirstName: strings.Repeat("a", 51),
+		Email:     "not an email",
+		Status:    "Deleted",
+		Year:      1800,
+		Joined:    "2024-02-30",
+	}
+
+	codes := fieldCodes(t, Struct(&req))
+
+	expected := map[string]string{
+		"first_name": "max_length",
+		"email":      "email",
+		"status":     "oneof",
+		"year":       "year",
+		"joined":     "date",
+	}
+	for field, code := range expected {
+		if codes[field] != code {
+			t.Errorf("expected %s to fail with %s, got %q", field, code, codes[field])
+		}
+	}
+}
+
+func TestStructTrimsAndSkipsOptionalFields(t *testing.T) {
+	req := studentRequest{FirstName: "  Ada ", Email: " ada@example.com ", Note: " hi "}
+
+	if err := Struct(&req); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+	if req.FirstName != "Ada" || req.Email != "ada@example.com" || req.Note != "hi" {
+		t.Fatalf("strings were not trimmed: %+v", req)
+	}
+}
+
+func TestStructRequiresTrimmedValue(t *testing.T) {
+	req := studentRequest{FirstName: "   ", Email: "ada@example.com"}
+
+	if codes := fieldCodes(t, Struct(&req)); codes["first_name"] != "required" {
+		t.Fatalf("expected first_name to be required, got %v", codes)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+		code  string
+	}{
+		{"unknown field", `{"first_name":"Ada","email":"ada@example.com","role":"admin"}`, "role", "unknown"},
+		{"wrong type", `{"first_name":"Ada","email":"ada@example.com","year":"soon"}`, "year", "type"},
+		{"malformed", `{"first_name":`, "body", "invalid_json"},
+		{"trailing data", `{"first_name":"Ada","email":"ada@example.com"} {}`, "body", "invalid_json"},
+		{"rules", `{"first_name":"","email":"ada@example.com"}`, "first_name", "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var req studentRequest
+			codes := fieldCodes(t, Decode(httptest.NewRecorder(), r, &req, DefaultMaxBodyBytes))
+			if codes[tt.field] != tt.code {
+				t.Fatalf("expected %s to fail with %s, got %v", tt.field, tt.code, codes)
+			}
+		})
+	}
+}
+
+func TestDecodeLimitsBodySize(t *testing.T) {
+	body := `{"first_name":"` + strings.Repeat("a", 200) + `","email":"ada@example.com"}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+	var req studentRequest
+	if err := Decode(httptest.NewRecorder(), r, &req, 64); !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("expected ErrBodyTooLarge, got %v", err)
+	}
+}
+
+func TestDecodeEmptyBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	var req studentRequest
+	if err := Decode(httptest.NewRecorder(), r, &req, DefaultMaxBodyBytes); !errors.Is(err, ErrEmptyBody) {
+		t.Fatalf("expected ErrEmptyBody, got %v", err)
+	}
+}
+
+func TestStructMaxBytesAndNoTrim(t *testing.T) {
+	type passwordRequest struct {
+		Password string `json:"password" validate:"notrim,required,maxbytes=8"`
+	}
+
+	req := passwordRequest{Password: " pass "}
+	if err := Struct(&req); err != nil || req.Password != " pass " {
+		t.Fatalf("expected the password to be kept as typed, got %q and %v", req.Password, err)
+	}
+
+	// Four characters, but twelve bytes.
+	req = passwordRequest{Password: "€€€€"}
+	if codes := fieldCodes(t, Struct(&req)); codes["password"] != "max_bytes" {
+		t.Fatalf("expected password to fail with max_bytes, got %v", codes)
+	}
+}